@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// errUsage is returned by commands given bad arguments, after printing what was wrong.
+var errUsage = errors.New("usage error")
+
+// command is a subcommand that runs without the UI, for scripts, cron jobs and CFW hooks.
+// flags registers the command's own flags and returns the function that runs it.
+type command struct {
+	name    string
+	summary string
+	flags   func(fs *flag.FlagSet) func(ctx context.Context, env cliEnv) error
+}
+
+// cliEnv is what every command gets once the config, session and cache are set up.
+type cliEnv struct {
+	config *internal.Config
+	host   romm.Host
+	json   bool
+}
+
+func commands() []command {
+	return []command{
+		{name: "daemon", summary: "Upload saves as games write them until stopped", flags: noFlags(runDaemon)},
+		{name: "sync-saves", summary: "Sync saves (and save states if enabled) with RomM", flags: noFlags(runSyncSaves)},
+		{name: "download", summary: "Download a game: --rom-id <id> [--file-id <id>]", flags: downloadFlags},
+		{name: "refresh-cache", summary: "Refresh the games cache for all mapped platforms", flags: noFlags(runRefreshCache)},
+		{name: "bios", summary: "Download missing BIOS files: --platform <fs_slug>", flags: biosFlags},
+		{name: "hook", summary: "Sync one game's save from a CFW launch hook: pre-launch|post-exit <rom path>, or install", flags: hookFlags},
+		{name: "status", summary: "Show the host, cache, download queue and storage status", flags: noFlags(runStatus)},
+	}
+}
+
+// runCLI runs the subcommand named by args[0]. Returns false if args don't name one,
+// in which case Grout starts its UI as usual.
+func runCLI(args []string) (int, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(os.Stdout)
+		return exitOK, true
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		asJSON := flags.Bool("json", false, "print the result as JSON")
+		run := cmd.flags(flags)
+		if err := flags.Parse(args[1:]); err != nil {
+			return exitUsage, true
+		}
+
+		env, err := headlessSetup(cmd.name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "grout %s: %v\n", cmd.name, err)
+			return exitFailure, true
+		}
+		env.json = *asJSON
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		if err := run(ctx, env); err != nil {
+			if errors.Is(err, errUsage) {
+				return exitUsage, true
+			}
+			gaba.GetLogger().Error("Command failed", "command", cmd.name, "error", err)
+			fmt.Fprintf(os.Stderr, "grout %s: %v\n", cmd.name, err)
+			return exitFailure, true
+		}
+		return exitOK, true
+	}
+
+	fmt.Fprintf(os.Stderr, "grout: unknown command %q\n\n", args[0])
+	printUsage(os.Stderr)
+	return exitUsage, true
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: grout [command] [--json]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command Grout starts as usual. Commands run without the UI:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run commands from the Grout folder with CFW set like the launch script does.")
+}
+
+// headlessSetup loads what the UI's setup would, without showing anything: the config,
+// a valid session for the active host and its cache. Logs go to grout-<command>.log.
+func headlessSetup(commandName string) (cliEnv, error) {
+	gaba.SetLogFilename(fmt.Sprintf("grout-%s.log", commandName))
+	logger := gaba.GetLogger()
+
+	config, err := internal.LoadConfig()
+	if err != nil {
+		return cliEnv{}, err
+	}
+	if len(config.Hosts) == 0 || len(config.DirectoryMappings) == 0 {
+		return cliEnv{}, errors.New("open Grout once to log in and map your platforms first")
+	}
+
+	if config.LogLevel != "" {
+		gaba.SetRawLogLevel(string(config.LogLevel))
+	}
+
+	romm.SetTokenRefreshHandler(func(host romm.Host) {
+		if err := config.UpdateHostToken(host); err != nil {
+			logger.Error("Failed to persist refreshed token", "error", err)
+		}
+	})
+
+	host := config.CurrentHost()
+	if err := config.LoadPlatformsBinding(host, config.ApiTimeout); err != nil {
+		logger.Debug("Failed to load platform bindings", "error", err)
+	}
+
+	if err := cache.InitCacheManager(host, config); err != nil {
+		logger.Error("Failed to initialize cache manager", "error", err)
+	}
+
+	return cliEnv{config: config, host: host}, nil
+}
+
+func noFlags(run func(ctx context.Context, env cliEnv) error) func(fs *flag.FlagSet) func(ctx context.Context, env cliEnv) error {
+	return func(*flag.FlagSet) func(ctx context.Context, env cliEnv) error { return run }
+}
+
+// output prints v as indented JSON when --json was given, and the text otherwise.
+func (env cliEnv) output(v any, text func(w io.Writer)) {
+	if env.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(v)
+		return
+	}
+	text(os.Stdout)
+}
+
+func (env cliEnv) mappedPlatforms() ([]romm.Platform, error) {
+	return internal.GetMappedPlatforms(env.host, env.config.DirectoryMappings, env.config.ApiTimeout)
+}
+
+// mappedPlatform finds one of the platforms mapped on this device by fs_slug or RomM ID.
+func (env cliEnv) mappedPlatform(fsSlug string, id int) (romm.Platform, error) {
+	platforms, err := env.mappedPlatforms()
+	if err != nil {
+		return romm.Platform{}, err
+	}
+
+	for _, p := range platforms {
+		if (fsSlug != "" && p.FSSlug == fsSlug) || (id != 0 && p.ID == id) {
+			return p, nil
+		}
+	}
+
+	if fsSlug != "" {
+		return romm.Platform{}, fmt.Errorf("platform %q is not mapped on this device", fsSlug)
+	}
+	return romm.Platform{}, fmt.Errorf("platform %d is not mapped on this device", id)
+}
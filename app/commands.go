@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"grout/bios"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal/fileutil"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"grout/sync"
+	"grout/ui"
+	"grout/version"
+	"io"
+	"os"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"go.uber.org/atomic"
+)
+
+type syncResultOutput struct {
+	Game      string      `json:"game"`
+	Action    sync.Action `json:"action"`
+	StateSlot string      `json:"state_slot,omitempty"`
+	Success   bool        `json:"success"`
+	Error     string      `json:"error,omitempty"`
+}
+
+type syncSavesOutput struct {
+	Results        []syncResultOutput `json:"results"`
+	Unmatched      []string           `json:"unmatched"`
+	PendingMatches int                `json:"pending_matches"`
+}
+
+// runSyncSaves syncs like the Save Sync screen does. Conflicts and fuzzy matches need a
+// decision from the user, so they are reported and left for Grout.
+func runSyncSaves(ctx context.Context, env cliEnv) error {
+	scan := sync.ScanRoms(env.config)
+	syncs, unmatched, pending, err := sync.FindSaveSyncsFromScan(ctx, env.host, env.config, scan)
+	if err != nil {
+		return err
+	}
+
+	var results []sync.Result
+	for i := range syncs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		results = append(results, syncs[i].Execute(env.config))
+	}
+
+	if env.config.SyncSaveStates {
+		stateSyncs, err := sync.FindStateSyncs(ctx, env.host, env.config, scan)
+		if err != nil {
+			gaba.GetLogger().Error("Unable to scan save states", "error", err)
+		}
+		for i := range stateSyncs {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			results = append(results, stateSyncs[i].Execute(env.config))
+		}
+	}
+
+	out := syncSavesOutput{Results: []syncResultOutput{}, Unmatched: []string{}, PendingMatches: len(pending)}
+	failed := 0
+	for _, r := range results {
+		name := r.RomDisplayName
+		if name == "" {
+			name = r.GameName
+		}
+		out.Results = append(out.Results, syncResultOutput{Game: name, Action: r.Action, StateSlot: r.StateSlot, Success: r.Success, Error: r.Error})
+		if !r.Success {
+			failed++
+		}
+	}
+	for _, u := range unmatched {
+		out.Unmatched = append(out.Unmatched, u.SavePath)
+	}
+
+	env.output(out, func(w io.Writer) {
+		for _, r := range out.Results {
+			name := r.Game
+			if r.StateSlot != "" {
+				name = fmt.Sprintf("%s (state %s)", r.Game, r.StateSlot)
+			}
+			if r.Success {
+				fmt.Fprintf(w, "%-10s %s\n", r.Action, name)
+			} else {
+				fmt.Fprintf(w, "%-10s %s: %s\n", "FAILED", name, r.Error)
+			}
+		}
+		for _, path := range out.Unmatched {
+			fmt.Fprintf(w, "%-10s %s\n", "UNMATCHED", path)
+		}
+		if out.PendingMatches > 0 {
+			fmt.Fprintf(w, "%d save(s) need a match confirmed in Grout\n", out.PendingMatches)
+		}
+		if len(out.Results) == 0 && len(out.Unmatched) == 0 {
+			fmt.Fprintln(w, "Saves are in sync")
+		}
+	})
+
+	if failed > 0 {
+		return fmt.Errorf("%d save(s) failed to sync", failed)
+	}
+	return nil
+}
+
+func downloadFlags(fs *flag.FlagSet) func(ctx context.Context, env cliEnv) error {
+	romID := fs.Int("rom-id", 0, "RomM ID of the game to download")
+	fileID := fs.Int("file-id", 0, "RomM ID of a single file of the game to download")
+	force := fs.Bool("force", false, "Download even if the game doesn't fit in the free space")
+
+	return func(ctx context.Context, env cliEnv) error {
+		if *romID == 0 {
+			fmt.Fprintln(os.Stderr, "grout download: --rom-id is required")
+			fs.Usage()
+			return errUsage
+		}
+		return runDownload(ctx, env, *romID, *fileID, *force)
+	}
+}
+
+type downloadOutput struct {
+	RomID      int    `json:"rom_id"`
+	Name       string `json:"name"`
+	Platform   string `json:"platform"`
+	Downloaded bool   `json:"downloaded"`
+}
+
+// runDownload downloads a game through the download queue, so a download that fails or is
+// interrupted is picked up again by Grout.
+func runDownload(ctx context.Context, env cliEnv, romID, fileID int, force bool) error {
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return cache.ErrNotInitialized
+	}
+
+	rc := romm.NewClientFromHost(env.host, env.config.ApiTimeout)
+	game, err := rc.GetRom(romID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch game %d: %w", romID, err)
+	}
+
+	platform, err := env.mappedPlatform("", game.PlatformID)
+	if err != nil {
+		return err
+	}
+
+	if needed, free, ok := ui.CheckFreeSpace(*env.config, platform, []romm.Rom{game}, fileID); !ok && !force {
+		return fmt.Errorf("not enough free space for %s: %s needed, %s available (use --force to download anyway)",
+			game.Name, stringutil.FormatBytes(needed), stringutil.FormatBytes(int64(free)))
+	}
+
+	if err := env.config.SaveRomHost(platform.FSSlug, game.FsName, env.host); err != nil {
+		gaba.GetLogger().Error("Failed to save download host", "error", err)
+	}
+
+	queued, err := cm.EnqueueDownloads(platform, []romm.Rom{game}, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to queue %s: %w", game.Name, err)
+	}
+	entry := queued[0]
+	cm.MarkDownloadActive(entry.ID)
+
+	downloaded, err := ui.DownloadGame(ctx, env.host, *env.config, platform, game, fileID, &atomic.Float64{})
+	if ctx.Err() != nil {
+		cm.MarkDownloadQueued(entry.ID)
+		return ctx.Err()
+	}
+	if err != nil {
+		cm.MarkDownloadFailed(entry.ID, err)
+		return fmt.Errorf("failed to download %s: %w", game.Name, err)
+	}
+	cm.MarkDownloadDone(entry.ID)
+
+	out := downloadOutput{RomID: game.ID, Name: game.Name, Platform: platform.FSSlug, Downloaded: downloaded}
+	env.output(out, func(w io.Writer) {
+		if downloaded {
+			fmt.Fprintf(w, "Downloaded %s [%s]\n", game.Name, platform.FSSlug)
+		} else {
+			fmt.Fprintf(w, "Nothing to download for %s [%s]\n", game.Name, platform.FSSlug)
+		}
+	})
+	return nil
+}
+
+type refreshCacheOutput struct {
+	Platforms    int `json:"platforms"`
+	GamesUpdated int `json:"games_updated"`
+	Collections  int `json:"collections"`
+}
+
+func runRefreshCache(ctx context.Context, env cliEnv) error {
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return cache.ErrNotInitialized
+	}
+
+	platforms, err := env.mappedPlatforms()
+	if err != nil {
+		return err
+	}
+
+	stats, err := cm.PopulateFullCacheWithProgress(ctx, platforms, &atomic.Float64{})
+	if err != nil {
+		return err
+	}
+
+	out := refreshCacheOutput{Platforms: stats.Platforms, GamesUpdated: stats.GamesUpdated, Collections: stats.Collectionssynced}
+	env.output(out, func(w io.Writer) {
+		fmt.Fprintf(w, "Refreshed %d platform(s): %d game(s) updated, %d collection(s)\n", out.Platforms, out.GamesUpdated, out.Collections)
+	})
+	return nil
+}
+
+func biosFlags(fs *flag.FlagSet) func(ctx context.Context, env cliEnv) error {
+	fsSlug := fs.String("platform", "", "fs_slug of the platform to download BIOS files for")
+
+	return func(ctx context.Context, env cliEnv) error {
+		if *fsSlug == "" {
+			fmt.Fprintln(os.Stderr, "grout bios: --platform is required")
+			fs.Usage()
+			return errUsage
+		}
+		return runBIOS(ctx, env, *fsSlug)
+	}
+}
+
+type biosFileOutput struct {
+	FileName string `json:"file_name"`
+	Status   string `json:"status"` // "installed", "present" or "failed"
+	Error    string `json:"error,omitempty"`
+}
+
+// runBIOS downloads the BIOS files RomM has for a platform that aren't on the device yet.
+func runBIOS(ctx context.Context, env cliEnv, fsSlug string) error {
+	platform, err := env.mappedPlatform(fsSlug, 0)
+	if err != nil {
+		return err
+	}
+
+	rc := romm.NewClientFromHost(env.host, env.config.ApiTimeout)
+	firmware, err := rc.GetFirmware(platform.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch BIOS files: %w", err)
+	}
+
+	out := []biosFileOutput{}
+	failed := 0
+	for _, fw := range firmware {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		entry := biosFileOutput{FileName: fw.FileName, Status: "present"}
+		if !bios.FirmwareExists(fw, platform.FSSlug) {
+			entry.Status = "installed"
+			data, err := rc.DownloadFirmware(fw)
+			if err == nil {
+				err = bios.SaveFirmware(fw, platform.FSSlug, data)
+			}
+			if err != nil {
+				gaba.GetLogger().Error("Failed to install BIOS file", "file", fw.FileName, "error", err)
+				entry.Status = "failed"
+				entry.Error = err.Error()
+				failed++
+			}
+		}
+		out = append(out, entry)
+	}
+
+	env.output(out, func(w io.Writer) {
+		if len(out) == 0 {
+			fmt.Fprintf(w, "RomM has no BIOS files for %s\n", platform.Name)
+		}
+		for _, f := range out {
+			if f.Error != "" {
+				fmt.Fprintf(w, "%-10s %s: %s\n", f.Status, f.FileName, f.Error)
+			} else {
+				fmt.Fprintf(w, "%-10s %s\n", f.Status, f.FileName)
+			}
+		}
+	})
+
+	if failed > 0 {
+		return fmt.Errorf("%d BIOS file(s) failed to install", failed)
+	}
+	return nil
+}
+
+type statusOutput struct {
+	Version      string            `json:"version"`
+	CFW          cfw.CFW           `json:"cfw"`
+	Host         string            `json:"host"`
+	Username     string            `json:"username,omitempty"`
+	SaveSyncMode string            `json:"save_sync_mode"`
+	Platforms    int               `json:"mapped_platforms"`
+	Cache        statusCacheOutput `json:"cache"`
+	Downloads    map[string]int    `json:"downloads"`
+	Disk         *statusDiskOutput `json:"disk,omitempty"`
+}
+
+type statusCacheOutput struct {
+	Populated   bool       `json:"populated"`
+	Platforms   *time.Time `json:"platforms_refreshed_at,omitempty"`
+	Games       *time.Time `json:"games_refreshed_at,omitempty"`
+	Collections *time.Time `json:"collections_refreshed_at,omitempty"`
+}
+
+type statusDiskOutput struct {
+	Free  uint64 `json:"free_bytes"`
+	Total uint64 `json:"total_bytes"`
+}
+
+// runStatus reports on the device without contacting RomM.
+func runStatus(ctx context.Context, env cliEnv) error {
+	out := statusOutput{
+		Version:      version.Get().Version,
+		CFW:          cfw.GetCFW(),
+		Host:         env.host.URL(),
+		Username:     env.host.Username,
+		SaveSyncMode: string(env.config.SaveSyncMode),
+		Platforms:    len(env.config.DirectoryMappings),
+		Downloads:    map[string]int{},
+	}
+
+	if cm := cache.GetCacheManager(); cm != nil {
+		out.Cache.Populated = cm.HasCache()
+		refreshed := cm.GetAllRefreshTimes()
+		for key, dest := range map[string]**time.Time{
+			cache.MetaKeyPlatformsRefreshedAt:   &out.Cache.Platforms,
+			cache.MetaKeyGamesRefreshedAt:       &out.Cache.Games,
+			cache.MetaKeyCollectionsRefreshedAt: &out.Cache.Collections,
+		} {
+			if t, ok := refreshed[key]; ok {
+				*dest = &t
+			}
+		}
+
+		if queue, err := cm.GetDownloadQueue(); err == nil {
+			for _, entry := range queue {
+				out.Downloads[string(entry.Status)]++
+			}
+		}
+	}
+
+	if disk, err := fileutil.GetDiskSpace(cfw.GetRomDirectory()); err == nil {
+		out.Disk = &statusDiskOutput{Free: disk.Free, Total: disk.Total}
+	}
+
+	env.output(out, func(w io.Writer) {
+		fmt.Fprintf(w, "Grout %s on %s\n", out.Version, out.CFW)
+		fmt.Fprintf(w, "Host:       %s\n", out.Host)
+		fmt.Fprintf(w, "Save sync:  %s\n", out.SaveSyncMode)
+		fmt.Fprintf(w, "Platforms:  %d mapped\n", out.Platforms)
+		if out.Cache.Games != nil {
+			fmt.Fprintf(w, "Cache:      games refreshed %s\n", out.Cache.Games.Local().Format(time.DateTime))
+		} else {
+			fmt.Fprintln(w, "Cache:      not populated")
+		}
+		fmt.Fprintf(w, "Downloads:  %d queued, %d failed\n", out.Downloads[string(cache.DownloadStatusQueued)]+out.Downloads[string(cache.DownloadStatusActive)], out.Downloads[string(cache.DownloadStatusFailed)])
+		if out.Disk != nil {
+			fmt.Fprintf(w, "Disk:       %s free of %s\n", stringutil.FormatBytes(int64(out.Disk.Free)), stringutil.FormatBytes(int64(out.Disk.Total)))
+		}
+	})
+	return nil
+}
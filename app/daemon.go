@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"grout/sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// runDaemon uploads saves as games write them until Grout is sent SIGINT or SIGTERM.
+func runDaemon(ctx context.Context, env cliEnv) error {
+	logger := gaba.GetLogger()
+
+	logger.Info("Starting save daemon")
+	if err := sync.NewSaveDaemon(env.host, env.config).Run(ctx); err != nil {
+		return err
+	}
+
+	logger.Info("Save daemon stopped")
+	return nil
+}
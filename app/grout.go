@@ -11,6 +11,10 @@ import (
 )
 
 func main() {
+	if code, ok := runCLI(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
 	defer cleanup()
 
 	result := setup()
@@ -21,8 +25,9 @@ func main() {
 	logger.Debug("Starting Grout")
 
 	currentCFW := cfw.GetCFW()
-	quitOnBack := len(config.Hosts) == 1
-	showCollections := config.ShowCollections(config.Hosts[0])
+	// Other servers are switched to from the platform screen, so it is always the first screen
+	quitOnBack := true
+	showCollections := config.ShowCollections(config.CurrentHost())
 
 	if err := runWithRouter(config, currentCFW, platforms, quitOnBack, showCollections); err != nil {
 		logger.Error("Router error", "error", err)
@@ -30,13 +35,25 @@ func main() {
 }
 
 func cleanup() {
+	if currentAppState != nil && currentAppState.Downloads != nil && currentAppState.Downloads.IsRunning() {
+		// Interrupted downloads stay queued and resume from their partial files next launch
+		gaba.GetLogger().Info("Pausing background downloads before exiting...")
+		currentAppState.Downloads.Stop()
+	}
+
+	if currentAppState != nil && currentAppState.CacheSync != nil {
+		// A partially saved platform is rolled back and fetched again next launch
+		currentAppState.CacheSync.Stop()
+	}
+
 	if currentAppState != nil && currentAppState.AutoSync != nil && currentAppState.AutoSync.IsRunning() {
-		gaba.GetLogger().Info("Waiting for auto-sync to complete before exiting...")
+		// The save being transferred finishes, the rest sync next launch
+		gaba.GetLogger().Info("Stopping auto-sync before exiting...")
 		gaba.ProcessMessage(
 			i18n.Localize(&goi18n.Message{ID: "auto_sync_waiting", Other: "Waiting for save sync to complete..."}, nil),
 			gaba.ProcessMessageOptions{},
 			func() (interface{}, error) {
-				currentAppState.AutoSync.Wait()
+				currentAppState.AutoSync.Stop()
 				return nil, nil
 			},
 		)
@@ -9,9 +9,7 @@ import (
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
-	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/router"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
-	uatomic "go.uber.org/atomic"
 )
 
 func filterGamesByPlatform(games []romm.Rom, platformID int) []romm.Rom {
@@ -40,24 +38,124 @@ func triggerAutoSyncRouter(state *AppState) {
 	}
 }
 
-func executeDownloadUI(state *AppState, r ui.GameDetailsOutput, stack *router.Stack) {
-	entry := stack.Peek()
-	var allGames []romm.Rom
-	var searchFilter string
-	if entry != nil {
-		if input, ok := entry.Input.(ui.GameListInput); ok {
-			allGames = input.Games
-			searchFilter = input.SearchFilter
+func executeDownloadUI(state *AppState, r ui.GameDetailsOutput) {
+	enqueueAndDownloadUI(state, r.Platform, []romm.Rom{r.Game}, r.SelectedFileID)
+}
+
+func executeMultiDownloadUI(state *AppState, r ui.GameListOutput) {
+	enqueueAndDownloadUI(state, r.Platform, r.SelectedGames, 0)
+}
+
+// enqueueAndDownloadUI adds games to the download queue and hands them to the background
+// worker. If the queue can't be written the games are downloaded in the foreground instead.
+// Games that won't fit on the card are only downloaded once the user confirms.
+func enqueueAndDownloadUI(state *AppState, platform romm.Platform, games []romm.Rom, fileID int) {
+	if !ui.ConfirmFreeSpace(*state.Config, platform, games, fileID) {
+		return
+	}
+
+	for _, game := range games {
+		slug := platform.FSSlug
+		if platform.ID == 0 {
+			slug = game.PlatformFSSlug
 		}
+		state.Config.SetRomHost(slug, game.FsName, state.Host)
+	}
+	if err := internal.SaveConfig(state.Config); err != nil {
+		gaba.GetLogger().Error("Failed to save download hosts", "error", err)
+	}
+
+	if _, err := cache.GetCacheManager().EnqueueDownloads(platform, games, fileID); err != nil {
+		gaba.GetLogger().Error("Failed to queue downloads", "error", err)
+		downloadScreen := ui.NewDownloadScreen()
+		downloadScreen.Execute(*state.Config, state.Host, platform, games, nil, "", fileID)
+		triggerAutoSyncRouter(state)
+		return
 	}
 
-	downloadScreen := ui.NewDownloadScreen()
-	downloadScreen.Execute(*state.Config, state.Host, r.Platform, []romm.Rom{r.Game}, allGames, searchFilter, r.SelectedFileID)
+	if internal.IsOffline() {
+		ui.ShowOfflineMessage(&goi18n.Message{ID: "download_queued_offline", Other: "RomM can't be reached right now.\nDownloads will start once it is back online."})
+		return
+	}
+
+	state.Downloads.Trigger()
 }
 
-func executeMultiDownloadUI(state *AppState, r ui.GameListOutput) {
-	downloadScreen := ui.NewDownloadScreen()
-	downloadScreen.Execute(*state.Config, state.Host, r.Platform, r.SelectedGames, r.AllGames, r.SearchFilter, 0)
+// downloadMissingPlatformUI queues every game of a platform that isn't on the device yet.
+func downloadMissingPlatformUI(state *AppState, platform romm.Platform) {
+	games, err := cache.GetCacheManager().GetPlatformGames(platform.ID)
+	if err != nil {
+		gaba.GetLogger().Error("Failed to load platform games", "platform", platform.Name, "error", err)
+	}
+	downloadMissingUI(state, platform.Name, platform, games)
+}
+
+// downloadMissingCollectionUI queues every game of a collection that isn't on the device
+// yet. Games on platforms without a ROM directory are left out.
+func downloadMissingCollectionUI(state *AppState, collection romm.Collection) {
+	games, err := cache.GetCacheManager().GetCollectionGames(collection)
+	if err != nil {
+		gaba.GetLogger().Error("Failed to load collection games", "collection", collection.Name, "error", err)
+	}
+
+	mapped := make([]romm.Rom, 0, len(games))
+	for _, game := range games {
+		if _, ok := state.Config.DirectoryMappings[game.PlatformFSSlug]; ok {
+			mapped = append(mapped, game)
+		}
+	}
+	downloadMissingUI(state, collection.Name, romm.Platform{}, mapped)
+}
+
+func downloadMissingUI(state *AppState, name string, platform romm.Platform, games []romm.Rom) {
+	if len(games) == 0 {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "bulk_download_empty", Other: "No games found in {{.Name}}."}, map[string]interface{}{"Name": name}),
+			ui.ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	missing := ui.MissingGames(*state.Config, games)
+	if !ui.ConfirmBulkDownload(name, missing, len(games)) {
+		return
+	}
+
+	// The cached list only holds what the list screens show, the queue needs everything
+	enqueueAndDownloadUI(state, platform, cache.GetCacheManager().LoadFullGames(missing), 0)
+}
+
+// resumeDownloadQueueUI offers to continue downloads left in the queue by a previous session.
+func resumeDownloadQueueUI(state *AppState) {
+	cm := cache.GetCacheManager()
+	if err := cm.ResetActiveDownloads(); err != nil {
+		return
+	}
+
+	// The queue is picked up by resumeHostWork once RomM is back
+	if internal.IsOffline() {
+		return
+	}
+
+	pending := cm.CountPendingDownloads()
+	if pending == 0 {
+		return
+	}
+
+	result, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "download_queue_resume", Other: "You have {{.Count}} queued downloads.\nResume them now?"}, map[string]interface{}{"Count": pending}),
+		[]gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_later", Other: "Later"}, nil)},
+			{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_resume", Other: "Resume"}, nil)},
+		},
+		gaba.MessageOptions{},
+	)
+	if err != nil || result == nil || !result.Confirmed {
+		return
+	}
+
+	state.Downloads.Trigger()
 }
 
 func handlePlatformMappingUpdateUI(state *AppState, r ui.PlatformMappingOutput) {
@@ -93,11 +191,19 @@ func handlePlatformMappingUpdateUI(state *AppState, r ui.PlatformMappingOutput)
 func handleLogout(state *AppState) {
 	logger := gaba.GetLogger()
 
-	if err := cache.DeleteCacheFolder(); err != nil {
-		logger.Error("Failed to delete cache folder", "error", err)
+	// Only the library of the host logged out of is dropped. Its download queue, save sync
+	// state and the caches of other hosts are kept for when they are added again.
+	state.hostMu.Lock()
+	defer state.hostMu.Unlock()
+
+	stopHostWork(state)
+	if err := cache.GetCacheManager().Clear(); err != nil {
+		logger.Error("Failed to clear cache", "error", err)
 	}
 
 	state.Config.Hosts = nil
+	state.Config.ActiveHost = ""
+	state.Config.RomHosts = nil
 	state.Config.DirectoryMappings = nil
 	state.Config.PlatformOrder = nil
 
@@ -121,6 +227,7 @@ func handleLogout(state *AppState) {
 	}
 
 	state.Host = state.Config.Hosts[0]
+	state.Offline.SetHost(state.Host)
 
 	if len(state.Config.DirectoryMappings) == 0 {
 		screen := ui.NewPlatformMappingScreen()
@@ -140,30 +247,17 @@ func handleLogout(state *AppState) {
 		}
 	}
 
-	if err := cache.InitCacheManager(state.Config.Hosts[0], state.Config); err != nil {
+	if err := cache.SwitchCacheManager(state.Host, state.Config); err != nil {
 		logger.Error("Failed to initialize cache manager after re-login", "error", err)
 	}
 
-	platforms, err := internal.GetMappedPlatforms(state.Config.Hosts[0], state.Config.DirectoryMappings, state.Config.ApiTimeout)
+	platforms, err := internal.GetMappedPlatforms(state.Host, state.Config.DirectoryMappings, state.Config.ApiTimeout)
 	if err != nil {
 		logger.Error("Failed to load platforms after re-login", "error", err)
-		return
+	} else {
+		state.Platforms = platforms
 	}
-	state.Platforms = platforms
 
-	if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
-		progress := uatomic.NewFloat64(0)
-		gaba.ProcessMessage(
-			i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
-			gaba.ProcessMessageOptions{
-				ShowThemeBackground: true,
-				ShowProgressBar:     true,
-				Progress:            progress,
-			},
-			func() (interface{}, error) {
-				_, err := cm.PopulateFullCacheWithProgress(platforms, progress)
-				return nil, err
-			},
-		)
-	}
+	// Builds the cache of the new host first if it has none
+	startHostWork(state)
 }
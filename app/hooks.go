@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"grout/cfw"
+	"grout/sync"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+const (
+	hookPreLaunch = "pre-launch"
+	hookPostExit  = "post-exit"
+	hookInstall   = "install"
+)
+
+func hookFlags(fs *flag.FlagSet) func(ctx context.Context, env cliEnv) error {
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: grout hook pre-launch|post-exit <rom path>")
+		fmt.Fprintln(fs.Output(), "       grout hook install")
+	}
+
+	return func(ctx context.Context, env cliEnv) error {
+		event := fs.Arg(0)
+		switch {
+		case event == hookInstall && fs.NArg() == 1:
+			return runHookInstall(env)
+		case (event == hookPreLaunch || event == hookPostExit) && fs.NArg() == 2:
+			return runHook(ctx, env, event, fs.Arg(1))
+		}
+		fs.Usage()
+		return errUsage
+	}
+}
+
+type hookOutput struct {
+	Event  string      `json:"event"`
+	Rom    string      `json:"rom"`
+	Game   string      `json:"game,omitempty"`
+	Action sync.Action `json:"action"`
+	Synced bool        `json:"synced"`
+	Reason string      `json:"reason,omitempty"`
+}
+
+// runHook syncs the save of the game being launched or exited. Before launch only a newer
+// save in RomM is downloaded, and after exit only a newer local save is uploaded, so a
+// hook never overwrites the save the game is about to use or has just written.
+func runHook(ctx context.Context, env cliEnv, event, romPath string) error {
+	logger := gaba.GetLogger()
+
+	out := hookOutput{Event: event, Rom: romPath, Action: sync.Skip}
+	s, err := sync.FindGameSaveSync(ctx, env.host, env.config, romPath)
+	if errors.Is(err, sync.ErrRomNotFound) || errors.Is(err, sync.ErrRomNotMatched) || errors.Is(err, sync.ErrRomOtherHost) {
+		logger.Info("Hook: Nothing to sync", "event", event, "rom", romPath, "reason", err)
+		out.Reason = err.Error()
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if s != nil {
+		out.Game = s.GameBase
+		out.Action = s.Action
+
+		wanted := sync.Download
+		if event == hookPostExit {
+			wanted = sync.Upload
+		}
+
+		if s.Action == wanted {
+			result := s.Execute(env.config)
+			if !result.Success {
+				return fmt.Errorf("failed to sync save for %s: %s", s.GameBase, result.Error)
+			}
+			out.Synced = true
+			logger.Info("Hook: Synced save", "event", event, "game", s.GameBase, "action", s.Action)
+		} else {
+			logger.Info("Hook: Leaving save for the next sync", "event", event, "game", s.GameBase, "action", s.Action)
+		}
+	}
+
+	env.output(out, func(w io.Writer) {
+		switch {
+		case out.Synced:
+			fmt.Fprintf(w, "%-10s %s\n", out.Action, out.Game)
+		case out.Game != "":
+			fmt.Fprintf(w, "Left %s for the next sync (%s)\n", out.Game, out.Action)
+		case out.Reason != "":
+			fmt.Fprintf(w, "Nothing to sync for %s: %s\n", romPath, out.Reason)
+		default:
+			fmt.Fprintf(w, "Save is in sync: %s\n", romPath)
+		}
+	})
+	return nil
+}
+
+// runHookInstall writes the hook scripts for the current CFW. They point back at the Grout
+// folder the command is run from.
+func runHookInstall(env cliEnv) error {
+	groutDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	scripts := cfw.HookScripts(groutDir)
+	if len(scripts) == 0 {
+		return fmt.Errorf("%s can't run scripts around game launch", cfw.GetCFW())
+	}
+
+	var paths []string
+	for path, script := range scripts {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create hook folder: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			return fmt.Errorf("failed to write hook script: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	env.output(paths, func(w io.Writer) {
+		for _, path := range paths {
+			fmt.Fprintf(w, "Installed %s\n", path)
+		}
+	})
+	return nil
+}
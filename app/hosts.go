@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"grout/sync"
+	"grout/ui"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	uatomic "go.uber.org/atomic"
+)
+
+func loadRommVersion(state *AppState, host romm.Host) {
+	client := romm.NewClientFromHost(host)
+	if heartbeat, err := client.GetHeartbeat(); err == nil {
+		state.RommVersion.Store(heartbeat.System.Version)
+	}
+}
+
+// startHostWork starts the background work for the host being browsed: keeping its cache
+// fresh and downloading its queue. A host without a cache yet has one built first.
+func startHostWork(state *AppState) {
+	state.Downloads = ui.NewBackgroundDownload(state.Host, state.Config, func() {
+		triggerAutoSyncRouter(state)
+	})
+
+	// The sync runs on its own goroutine, so it gets this host's work rather than reading
+	// the state the UI swaps when switching hosts
+	config, host, downloads := state.Config, state.Host, state.Downloads
+	state.CacheSync = cache.NewBackgroundSync(state.Platforms, func(ctx context.Context) {
+		syncSubscribedCollections(ctx, config, host, downloads)
+		syncPlayActivity(ctx, config, host)
+	})
+	ui.AddStatusBarIcon(state.CacheSync.Icon())
+
+	if internal.IsOffline() {
+		// The cache is refreshed by resumeHostWork once RomM is back
+		gaba.GetLogger().Debug("Offline, browsing from the cache")
+	} else if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
+		progress := uatomic.NewFloat64(0)
+		gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
+			gaba.ProcessMessageOptions{
+				ShowThemeBackground: true,
+				ShowProgressBar:     true,
+				Progress:            progress,
+			},
+			func() (interface{}, error) {
+				_, err := cm.PopulateFullCacheWithProgress(context.Background(), state.Platforms, progress)
+				return nil, err
+			},
+		)
+		state.CacheSync.SetSynced()
+	} else {
+		state.CacheSync.Start()
+	}
+
+	cache.RunArtworkValidation()
+}
+
+// resumeHostWork catches up on the work that waited while RomM was offline: refreshing
+// the cache, downloading the queue and syncing saves. Runs on the offline monitor's
+// goroutine, so the caller holds state.hostMu.
+func resumeHostWork(state *AppState) {
+	loadRommVersion(state, state.Host)
+	state.CacheSync.Restart()
+
+	if cm := cache.GetCacheManager(); cm != nil && cm.CountPendingDownloads() > 0 {
+		state.Downloads.Trigger()
+	}
+	triggerAutoSyncRouter(state)
+}
+
+// stopHostWork finishes or pauses the background work for the host being browsed, which
+// all uses its cache. Queued downloads stay in that host's queue for when it is back.
+func stopHostWork(state *AppState) {
+	if state.AutoSync != nil && state.AutoSync.IsRunning() {
+		gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "auto_sync_waiting", Other: "Waiting for save sync to complete..."}, nil),
+			gaba.ProcessMessageOptions{},
+			func() (interface{}, error) {
+				state.AutoSync.Stop()
+				return nil, nil
+			},
+		)
+	}
+
+	// The cache sync goes first as it can queue downloads for subscribed collections
+	if state.CacheSync != nil {
+		state.CacheSync.Stop()
+		ui.RemoveStatusBarIcon(state.CacheSync.Icon())
+	}
+
+	if state.Downloads != nil {
+		state.Downloads.Stop()
+		ui.RemoveStatusBarIcon(state.Downloads.Icon())
+	}
+}
+
+// switchHostUI makes host the server being browsed, with its own cache, platforms and
+// downloads. If its platforms can't be loaded the previous host stays active. Returns
+// whether the switch happened.
+func switchHostUI(state *AppState, host romm.Host) bool {
+	logger := gaba.GetLogger()
+	previous := state.Host
+
+	if internal.CheckConnectivity(host) {
+		var err error
+		host, err = ensureHostSession(state.Config, host, ui.CancellableLoginFlow)
+		if err != nil {
+			if !errors.Is(err, ui.ErrLoginCancelled) {
+				logger.Error("Login flow failed", "error", err)
+			}
+			internal.CheckConnectivity(previous)
+			return false
+		}
+	}
+
+	state.hostMu.Lock()
+	defer state.hostMu.Unlock()
+
+	stopHostWork(state)
+
+	platforms, err := openHost(state.Config, host)
+	if err != nil {
+		logger.Error("Failed to switch host", "host", host.URL(), "error", err)
+		gaba.ConfirmationMessage(
+			i18n.Localize(classifyStartupError(err), nil),
+			ui.ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+
+		internal.CheckConnectivity(previous)
+		if _, err := openHost(state.Config, previous); err != nil {
+			logger.Error("Failed to switch back to previous host", "host", previous.URL(), "error", err)
+		}
+		startHostWork(state)
+		return false
+	}
+
+	logger.Info("Switched host", "from", previous.URL(), "to", host.URL())
+	state.Host = host
+	state.Offline.SetHost(host)
+	state.Platforms = platforms
+	state.RommVersion.Store("")
+	go loadRommVersion(state, host)
+
+	startHostWork(state)
+
+	if state.AutoSync != nil {
+		ui.RemoveStatusBarIcon(state.AutoSync.Icon())
+		state.AutoSync = sync.NewAutoSync(state.Host, state.Config)
+		ui.AddStatusBarIcon(state.AutoSync.Icon())
+		state.AutoSync.Start()
+	}
+
+	resumeDownloadQueueUI(state)
+	return true
+}
+
+// openHost makes host the active one and opens its cache, returning its mapped platforms.
+func openHost(config *internal.Config, host romm.Host) ([]romm.Platform, error) {
+	config.SetActiveHost(host)
+	if err := internal.SaveConfig(config); err != nil {
+		gaba.GetLogger().Error("Failed to save active host", "error", err)
+	}
+
+	if err := cache.SwitchCacheManager(host, config); err != nil {
+		gaba.GetLogger().Error("Failed to open cache for host", "host", host.URL(), "error", err)
+	}
+
+	var platforms []romm.Platform
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "host_connecting", Other: "Connecting to {{.Name}}..."}, map[string]interface{}{"Name": ui.HostDisplayName(host)}),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			if internal.IsOffline() {
+				gaba.GetLogger().Debug("Offline, using saved platform bindings")
+			} else if err := config.LoadPlatformsBinding(host, config.ApiTimeout); err != nil {
+				gaba.GetLogger().Debug("Failed to load platform bindings", "error", err)
+			}
+
+			var err error
+			platforms, err = internal.GetMappedPlatforms(host, config.DirectoryMappings, config.ApiTimeout)
+			if err != nil {
+				return nil, err
+			}
+			platforms = internal.SortPlatformsByOrder(platforms, config.PlatformOrder)
+			return nil, nil
+		},
+	)
+	return platforms, err
+}
+
+// addHostUI logs in to another server and switches to it.
+func addHostUI(state *AppState) bool {
+	loginConfig, err := ui.CancellableLoginFlow(romm.Host{})
+	if err != nil {
+		if !errors.Is(err, ui.ErrLoginCancelled) {
+			gaba.GetLogger().Error("Login flow failed", "error", err)
+		}
+		return false
+	}
+
+	return switchHostUI(state, loginConfig.Hosts[0])
+}
+
+// removeHostUI forgets a server other than the one being browsed, along with its cache.
+func removeHostUI(state *AppState, host romm.Host) {
+	result, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "host_remove_confirm", Other: "Remove {{.Name}}?\nIts cache and download queue will be deleted, and saves of games downloaded from it stop syncing."}, map[string]interface{}{"Name": ui.HostDisplayName(host)}),
+		[]gaba.FooterHelpItem{ui.FooterCancel(), ui.FooterContinue()},
+		gaba.MessageOptions{},
+	)
+	if err != nil || result == nil || !result.Confirmed {
+		return
+	}
+
+	state.Config.RemoveHost(host)
+	if err := internal.SaveConfig(state.Config); err != nil {
+		gaba.GetLogger().Error("Failed to save config after removing host", "error", err)
+	}
+
+	if err := cache.DeleteHostCache(host); err != nil {
+		gaba.GetLogger().Error("Failed to delete host cache", "host", host.URL(), "error", err)
+	}
+}
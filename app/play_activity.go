@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"grout/internal"
+	"grout/romm"
+	"grout/sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// syncPlayActivity picks up what the CFW recorded being played and reports it to RomM.
+// Called by the cache sync, once the games it matches against have been refreshed, and
+// stops once ctx is cancelled.
+func syncPlayActivity(ctx context.Context, config *internal.Config, host romm.Host) {
+	logger := gaba.GetLogger()
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err := sync.ScanPlayActivity(host, config); err != nil {
+		logger.Error("Failed to scan play activity", "error", err)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if err := sync.ReportPlayActivity(ctx, host, config); err != nil && ctx.Err() == nil {
+		logger.Error("Failed to report play activity", "error", err)
+	}
+}
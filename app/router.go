@@ -9,31 +9,32 @@ import (
 	"grout/ui"
 	"grout/update"
 
-	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
-	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/router"
-	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
-	uatomic "go.uber.org/atomic"
 )
 
 func runWithRouter(config *internal.Config, currentCFW cfw.CFW, platforms []romm.Platform, quitOnBack bool, showCollections bool) error {
 	state := &AppState{
 		Config:    config,
-		Host:      config.Hosts[0],
+		Host:      config.CurrentHost(),
 		CFW:       currentCFW,
 		Platforms: platforms,
 	}
 	currentAppState = state
 
-	go func() {
-		client := romm.NewClientFromHost(state.Host)
-		if heartbeat, err := client.GetHeartbeat(); err == nil {
-			state.RommVersion.Store(heartbeat.System.Version)
-		}
-	}()
+	go loadRommVersion(state, state.Host)
 
 	r := buildRouter(state, quitOnBack, showCollections)
 
+	state.Offline = ui.NewOfflineMonitor(state.Host, func() {
+		state.hostMu.Lock()
+		defer state.hostMu.Unlock()
+		resumeHostWork(state)
+	})
+	ui.AddStatusBarIcon(state.Offline.Icon())
+	state.Offline.Start()
+
+	resumeDownloadQueueUI(state)
+
 	initialInput := ui.PlatformSelectionInput{
 		Platforms:       &state.Platforms,
 		QuitOnBack:      quitOnBack,
@@ -47,29 +48,7 @@ func runWithRouter(config *internal.Config, currentCFW cfw.CFW, platforms []romm
 func buildRouter(state *AppState, quitOnBack bool, showCollections bool) *router.Router {
 	r := router.New()
 
-	state.CacheSync = cache.NewBackgroundSync(state.Platforms)
-	ui.AddStatusBarIcon(state.CacheSync.Icon())
-
-	if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
-		progress := uatomic.NewFloat64(0)
-		gaba.ProcessMessage(
-			i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
-			gaba.ProcessMessageOptions{
-				ShowThemeBackground: true,
-				ShowProgressBar:     true,
-				Progress:            progress,
-			},
-			func() (interface{}, error) {
-				_, err := cm.PopulateFullCacheWithProgress(state.Platforms, progress)
-				return nil, err
-			},
-		)
-		state.CacheSync.SetSynced()
-	} else {
-		state.CacheSync.Start()
-	}
-
-	cache.RunArtworkValidation()
+	startHostWork(state)
 
 	registerScreens(r, state)
 	r.OnTransition(buildTransitionFunc(state, quitOnBack, showCollections))
@@ -98,6 +77,8 @@ func registerScreens(r *router.Router, state *AppState) {
 		if in.ShowSaveSync == nil {
 			in.ShowSaveSync = computeShowSaveSync(state)
 		}
+		in.ShowDownloadQueue = cache.GetCacheManager().HasDownloadQueue()
+		in.ShowRecentlyPlayed, _ = cache.GetCacheManager().HasPlayActivity()
 
 		screen := ui.NewPlatformSelectionScreen()
 		return screen.Draw(in)
@@ -181,6 +162,7 @@ func registerScreens(r *router.Router, state *AppState) {
 	r.Register(ScreenRebuildCache, func(input any) (any, error) {
 		in := input.(ui.RebuildCacheInput)
 		in.CacheSync = state.CacheSync
+		in.Downloads = state.Downloads
 		screen := ui.NewRebuildCacheScreen()
 		return screen.Draw(in)
 	})
@@ -213,4 +195,49 @@ func registerScreens(r *router.Router, state *AppState) {
 		screen := ui.NewGameFiltersScreen()
 		return screen.Draw(input.(ui.GameFiltersInput))
 	})
+
+	r.Register(ScreenDownloadQueue, func(input any) (any, error) {
+		screen := ui.NewDownloadQueueScreen()
+		return screen.Draw(input.(ui.DownloadQueueInput))
+	})
+
+	r.Register(ScreenLocalGames, func(input any) (any, error) {
+		screen := ui.NewLocalGamesScreen()
+		return screen.Draw(input.(ui.LocalGamesInput))
+	})
+
+	r.Register(ScreenStorage, func(input any) (any, error) {
+		screen := ui.NewStorageScreen()
+		return screen.Draw(input.(ui.StorageInput))
+	})
+
+	r.Register(ScreenGameCollections, func(input any) (any, error) {
+		screen := ui.NewGameCollectionsScreen()
+		return screen.Draw(input.(ui.GameCollectionsInput))
+	})
+
+	r.Register(ScreenCollectionEditor, func(input any) (any, error) {
+		screen := ui.NewCollectionEditorScreen()
+		return screen.Draw(input.(ui.CollectionEditorInput))
+	})
+
+	r.Register(ScreenCollectionSubscription, func(input any) (any, error) {
+		screen := ui.NewCollectionSubscriptionScreen()
+		return screen.Draw(input.(ui.CollectionSubscriptionInput))
+	})
+
+	r.Register(ScreenRecentlyPlayed, func(input any) (any, error) {
+		screen := ui.NewRecentlyPlayedScreen()
+		return screen.Draw(input.(ui.RecentlyPlayedInput))
+	})
+
+	r.Register(ScreenSaveHistory, func(input any) (any, error) {
+		screen := ui.NewSaveHistoryScreen()
+		return screen.Draw(input.(ui.SaveHistoryInput))
+	})
+
+	r.Register(ScreenHostSelection, func(input any) (any, error) {
+		screen := ui.NewHostSelectionScreen()
+		return screen.Draw(input.(ui.HostSelectionInput))
+	})
 }
@@ -30,4 +30,13 @@ const (
 	ScreenArtworkSync
 	ScreenUpdateCheck
 	ScreenGameFilters
+	ScreenDownloadQueue
+	ScreenLocalGames
+	ScreenStorage
+	ScreenSaveHistory
+	ScreenHostSelection
+	ScreenGameCollections
+	ScreenCollectionEditor
+	ScreenCollectionSubscription
+	ScreenRecentlyPlayed
 )
@@ -112,7 +112,7 @@ func setup() SetupResult {
 		if config == nil {
 			config = &internal.Config{
 				ShowRegularCollections: true,
-				ApiTimeout:             30 * time.Minute,
+				ApiTimeout:             romm.DefaultClientTimeout,
 				DownloadTimeout:        60 * time.Minute,
 			}
 		}
@@ -134,6 +134,20 @@ func setup() SetupResult {
 		internal.SaveConfig(config)
 	}
 
+	romm.SetTokenRefreshHandler(func(host romm.Host) {
+		if err := config.UpdateHostToken(host); err != nil {
+			logger.Error("Failed to persist refreshed token", "error", err)
+		}
+	})
+
+	if !internal.CheckConnectivity(config.CurrentHost()) {
+		logger.Info("RomM is unreachable, starting offline", "host", config.CurrentHost().URL())
+	} else if _, err := ensureHostSession(config, config.CurrentHost(), ui.LoginFlow); err != nil {
+		logger.Error("Login flow failed", "error", err)
+		log.SetOutput(os.Stderr)
+		log.Fatalf("Login failed: %v", err)
+	}
+
 	if config.LogLevel != "" {
 		gaba.SetRawLogLevel(string(config.LogLevel))
 	}
@@ -169,7 +183,7 @@ func setup() SetupResult {
 	if len(config.DirectoryMappings) == 0 {
 		screen := ui.NewPlatformMappingScreen()
 		result, err := screen.Draw(ui.PlatformMappingInput{
-			Host:             config.Hosts[0],
+			Host:             config.CurrentHost(),
 			ApiTimeout:       config.ApiTimeout,
 			CFW:              currentCFW,
 			RomDirectory:     cfw.GetRomDirectory(),
@@ -187,7 +201,7 @@ func setup() SetupResult {
 	logger.Debug("Configuration Loaded!", "config", config.ToLoggable())
 
 	// Initialize cache manager early so platforms can be loaded from cache
-	if err := cache.InitCacheManager(config.Hosts[0], config); err != nil {
+	if err := cache.InitCacheManager(config.CurrentHost(), config); err != nil {
 		logger.Error("Failed to initialize cache manager", "error", err)
 	}
 
@@ -203,12 +217,14 @@ func setup() SetupResult {
 			ImageHeight: 540,
 		}, func() (interface{}, error) {
 			// Load platform bindings from RomM server (non-fatal if it fails)
-			if err := config.LoadPlatformsBinding(config.Hosts[0], config.ApiTimeout); err != nil {
+			if internal.IsOffline() {
+				logger.Debug("Offline, using saved platform bindings")
+			} else if err := config.LoadPlatformsBinding(config.CurrentHost(), config.ApiTimeout); err != nil {
 				logger.Debug("Failed to load platform bindings", "error", err)
 			}
 
 			var err error
-			platforms, err = internal.GetMappedPlatforms(config.Hosts[0], config.DirectoryMappings, config.ApiTimeout)
+			platforms, err = internal.GetMappedPlatforms(config.CurrentHost(), config.DirectoryMappings, config.ApiTimeout)
 			if err != nil {
 				loadErr = err
 				return nil, err
@@ -233,6 +249,7 @@ func setup() SetupResult {
 			os.Exit(1)
 		}
 		logger.Info("User chose to retry connection")
+		internal.CheckConnectivity(config.CurrentHost())
 	}
 
 	return SetupResult{
@@ -241,6 +258,46 @@ func setup() SetupResult {
 	}
 }
 
+// ensureHostSession exchanges a password left over from older configs for a token pair,
+// and sends the user back to the login screen if the stored refresh token was rejected.
+// Network failures are ignored so the app can still start while offline. The host is
+// made the active one and returned with its current session.
+func ensureHostSession(config *internal.Config, host romm.Host, login func(romm.Host) (*internal.Config, error)) (romm.Host, error) {
+	logger := gaba.GetLogger()
+	client := romm.NewClientFromHost(host, internal.LoginTimeout)
+
+	var err error
+	if host.Password != "" && host.RefreshToken == "" {
+		var token romm.Token
+		token, err = client.Login(host.Username, host.Password)
+		if err == nil {
+			logger.Info("Migrated stored password to token authentication")
+			host = host.WithToken(token)
+			config.SetActiveHost(host)
+			internal.SaveConfig(config)
+			return host, nil
+		}
+	} else {
+		_, err = client.EnsureToken()
+	}
+
+	if err == nil || !errors.Is(err, romm.ErrUnauthorized) {
+		return host, nil
+	}
+
+	logger.Info("Stored session is no longer valid, starting login flow", "error", err)
+	host.Password = ""
+	loginConfig, err := login(host)
+	if err != nil {
+		return host, err
+	}
+
+	host = loginConfig.Hosts[0]
+	config.SetActiveHost(host)
+	internal.SaveConfig(config)
+	return host, nil
+}
+
 func classifyStartupError(err error) *goi18n.Message {
 	if err == nil {
 		return nil
@@ -6,6 +6,7 @@ import (
 	"grout/internal"
 	"grout/romm"
 	"grout/sync"
+	"grout/ui"
 	"grout/update"
 	gosync "sync"
 	"sync/atomic"
@@ -14,6 +15,10 @@ import (
 var currentAppState *AppState
 
 type AppState struct {
+	// hostMu is held while the host and its background work change, so work started from
+	// other goroutines never sees them half swapped
+	hostMu gosync.Mutex
+
 	Config    *internal.Config
 	Host      romm.Host
 	CFW       cfw.CFW
@@ -24,6 +29,8 @@ type AppState struct {
 	AutoSync   *sync.AutoSync
 	AutoUpdate *update.AutoUpdate
 	CacheSync  *cache.BackgroundSync
+	Downloads  *ui.BackgroundDownload
+	Offline    *ui.OfflineMonitor
 
 	autoSyncOnce   gosync.Once
 	autoUpdateOnce gosync.Once
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"grout/sync"
+	"grout/ui"
+	"slices"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// syncSubscribedCollections brings the device in line with the collections kept in sync:
+// games added to them since the last sync are queued for download and, where asked for,
+// games removed from them are deleted. Called by the cache sync after each collection
+// refresh, so it never asks anything. Once ctx is cancelled it stops without recording
+// anything, and the next sync picks up where it left off.
+func syncSubscribedCollections(ctx context.Context, config *internal.Config, host romm.Host, downloads *ui.BackgroundDownload) {
+	logger := gaba.GetLogger()
+	cm := cache.GetCacheManager()
+
+	subscriptions, err := cm.GetCollectionSubscriptions()
+	if err != nil {
+		logger.Error("Failed to load collection subscriptions", "error", err)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	collections, err := cm.GetCollections()
+	if err != nil {
+		logger.Error("Failed to load collections for subscriptions", "error", err)
+		return
+	}
+
+	current := make(map[int][]int, len(subscriptions))
+	kept := make(map[int]bool)
+	removed := make(map[int]bool)
+	var added []int
+
+	for i, sub := range subscriptions {
+		// Collections that are hidden or were deleted in RomM aren't refreshed, so nothing changes
+		idx := slices.IndexFunc(collections, sub.Matches)
+		if idx < 0 {
+			continue
+		}
+		romIDs := collections[idx].ROMIDs
+		current[i] = romIDs
+
+		for _, id := range romIDs {
+			kept[id] = true
+			if !slices.Contains(sub.ROMIDs, id) && !slices.Contains(added, id) {
+				added = append(added, id)
+			}
+		}
+		if sub.DeleteRemoved {
+			for _, id := range sub.ROMIDs {
+				if !slices.Contains(romIDs, id) {
+					removed[id] = true
+				}
+			}
+		}
+	}
+
+	// A game still in another subscribed collection stays on the device
+	for id := range kept {
+		delete(removed, id)
+	}
+
+	handled := queueSubscribedGames(ctx, config, host, downloads, added)
+	if len(removed) > 0 {
+		deleteUnsubscribedGames(ctx, config, host, removed)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	for i, sub := range subscriptions {
+		romIDs, ok := current[i]
+		if !ok {
+			continue
+		}
+		// Added games that aren't cached yet are picked up by a later sync
+		romIDs = slices.DeleteFunc(slices.Clone(romIDs), func(id int) bool {
+			return !slices.Contains(sub.ROMIDs, id) && !handled[id]
+		})
+		if slices.Equal(romIDs, sub.ROMIDs) {
+			continue
+		}
+		sub.ROMIDs = romIDs
+		if err := cm.SaveCollectionSubscription(sub); err != nil {
+			logger.Error("Failed to update collection subscription", "collection", sub.Name, "error", err)
+		}
+	}
+}
+
+// queueSubscribedGames queues the games of romIDs that aren't on the device yet. Games on
+// platforms without a ROM directory are skipped. Returns the games that were looked at,
+// which are those found in the cache.
+func queueSubscribedGames(ctx context.Context, config *internal.Config, host romm.Host, downloads *ui.BackgroundDownload, romIDs []int) map[int]bool {
+	logger := gaba.GetLogger()
+	cm := cache.GetCacheManager()
+
+	handled := make(map[int]bool)
+	if len(romIDs) == 0 {
+		return handled
+	}
+
+	games, err := cm.GetGamesByIDs(romIDs)
+	if err != nil {
+		logger.Error("Failed to load subscribed games", "error", err)
+		return handled
+	}
+
+	mapped := make([]romm.Rom, 0, len(games))
+	for _, game := range games {
+		handled[game.ID] = true
+		if _, ok := config.DirectoryMappings[game.PlatformFSSlug]; ok {
+			mapped = append(mapped, game)
+		}
+	}
+
+	missing := ui.MissingGames(*config, mapped)
+	if len(missing) == 0 || ctx.Err() != nil {
+		return handled
+	}
+
+	for _, game := range missing {
+		config.SetRomHost(game.PlatformFSSlug, game.FsName, host)
+	}
+	if err := internal.SaveConfig(config); err != nil {
+		logger.Error("Failed to save download hosts", "error", err)
+	}
+
+	if _, err := cm.EnqueueDownloads(romm.Platform{}, missing, 0); err != nil {
+		logger.Error("Failed to queue subscribed games", "error", err)
+		// Left for the next sync to try again
+		for _, game := range missing {
+			delete(handled, game.ID)
+		}
+		return handled
+	}
+
+	logger.Info("Queued games added to subscribed collections", "count", len(missing))
+	downloads.Trigger()
+	return handled
+}
+
+// deleteUnsubscribedGames deletes the games of romIDs from the device, keeping their saves.
+// Games downloaded from another server are left alone.
+func deleteUnsubscribedGames(ctx context.Context, config *internal.Config, host romm.Host, romIDs map[int]bool) {
+	logger := gaba.GetLogger()
+
+	deleted := 0
+	for _, games := range sync.ScanLocalGames(config) {
+		for _, game := range games {
+			if ctx.Err() != nil {
+				break
+			}
+			if !game.IsMatched() || !romIDs[game.Rom.ID] || !config.SyncsWithHost(game.FSSlug, game.FileName(), host) {
+				continue
+			}
+			if err := sync.DeleteLocalGame(config, game, false); err != nil {
+				logger.Error("Failed to delete game removed from subscribed collection", "game", game.Name, "error", err)
+				continue
+			}
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		logger.Info("Deleted games removed from subscribed collections", "count", deleted)
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
+	"grout/romm"
 	"grout/ui"
 	"os"
 
@@ -76,6 +78,16 @@ func buildTransitionFunc(state *AppState, quitOnBack bool, initialShowCollection
 			return transitionUpdateCheck(ctx, result)
 		case ScreenGameFilters:
 			return transitionGameFilters(ctx, result)
+		case ScreenDownloadQueue:
+			return transitionDownloadQueue(ctx, result)
+		case ScreenHostSelection:
+			return transitionHostSelection(ctx, result)
+		case ScreenCollectionSubscription:
+			return transitionCollectionSubscription(ctx, result)
+		case ScreenRecentlyPlayed:
+			return transitionRecentlyPlayed(ctx, result)
+		case ScreenLocalGames, ScreenStorage, ScreenSaveHistory, ScreenGameCollections, ScreenCollectionEditor:
+			return popOrExit(ctx.stack)
 		}
 
 		return router.ScreenExit, nil
@@ -127,6 +139,27 @@ func transitionPlatformSelection(ctx *transitionContext, result any) (router.Scr
 			Host:   ctx.state.Host,
 		}
 
+	case ui.PlatformSelectionActionDownloadQueue:
+		ctx.stack.Push(ScreenPlatformSelection, pushInput, r)
+		return ScreenDownloadQueue, ui.DownloadQueueInput{}
+
+	case ui.PlatformSelectionActionHosts:
+		ctx.stack.Push(ScreenPlatformSelection, pushInput, r)
+		return ScreenHostSelection, hostSelectionInput(ctx.state)
+
+	case ui.PlatformSelectionActionRecentlyPlayed:
+		ctx.stack.Push(ScreenPlatformSelection, pushInput, r)
+		return ScreenRecentlyPlayed, ui.RecentlyPlayedInput{
+			Config: ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+
+	case ui.PlatformSelectionActionDownloadMissing:
+		downloadMissingPlatformUI(ctx.state, r.SelectedPlatform)
+		pushInput.LastSelectedIndex = r.LastSelectedIndex
+		pushInput.LastSelectedPosition = r.LastSelectedPosition
+		return ScreenPlatformSelection, pushInput
+
 	case ui.PlatformSelectionActionQuit:
 		return router.ScreenExit, nil
 	}
@@ -153,7 +186,6 @@ func transitionGameList(ctx *transitionContext, result any) (router.Screen, any)
 	case ui.GameListActionSelected:
 		if len(r.SelectedGames) > 1 {
 			executeMultiDownloadUI(ctx.state, r)
-			triggerAutoSyncRouter(ctx.state)
 			return ScreenGameList, ui.GameListInput{
 				Config:               ctx.state.Config,
 				Host:                 ctx.state.Host,
@@ -205,6 +237,14 @@ func transitionGameList(ctx *transitionContext, result any) (router.Screen, any)
 			SearchQuery:    r.SearchFilter,
 		}
 
+	case ui.GameListActionCollections:
+		ctx.stack.Push(ScreenGameList, pushInput, r)
+		return ScreenGameCollections, ui.GameCollectionsInput{
+			Config: ctx.state.Config,
+			Host:   ctx.state.Host,
+			Games:  r.SelectedGames,
+		}
+
 	case ui.GameListActionBIOS:
 		ctx.stack.Push(ScreenGameList, pushInput, r)
 		return ScreenBIOSDownload, ui.BIOSDownloadInput{
@@ -292,8 +332,7 @@ func transitionGameDetails(ctx *transitionContext, result any) (router.Screen, a
 
 	switch r.Action {
 	case ui.GameDetailsActionDownload:
-		executeDownloadUI(ctx.state, r, ctx.stack)
-		triggerAutoSyncRouter(ctx.state)
+		executeDownloadUI(ctx.state, r)
 		return popOrExit(ctx.stack)
 
 	case ui.GameDetailsActionOptions:
@@ -334,6 +373,32 @@ func transitionGameOptions(ctx *transitionContext, result any) (router.Screen, a
 		}
 	}
 
+	if r.Action == ui.GameOptionsActionSaveHistory {
+		ctx.stack.Push(ScreenGameOptions, ui.GameOptionsInput{
+			Config: ctx.state.Config,
+			Host:   r.Host,
+			Game:   r.Game,
+		}, nil)
+		return ScreenSaveHistory, ui.SaveHistoryInput{
+			Config: ctx.state.Config,
+			Host:   r.Host,
+			Game:   r.Game,
+		}
+	}
+
+	if r.Action == ui.GameOptionsActionCollections {
+		ctx.stack.Push(ScreenGameOptions, ui.GameOptionsInput{
+			Config: ctx.state.Config,
+			Host:   r.Host,
+			Game:   r.Game,
+		}, nil)
+		return ScreenGameCollections, ui.GameCollectionsInput{
+			Config: ctx.state.Config,
+			Host:   r.Host,
+			Games:  []romm.Rom{r.Game},
+		}
+	}
+
 	return popOrExit(ctx.stack)
 }
 
@@ -367,6 +432,33 @@ func transitionCollectionList(ctx *transitionContext, result any) (router.Screen
 			Host:   ctx.state.Host,
 		}
 
+	case ui.CollectionListActionEdit:
+		ctx.stack.Push(ScreenCollectionList, pushInput, r)
+		return ScreenCollectionEditor, ui.CollectionEditorInput{
+			Config:     ctx.state.Config,
+			Host:       ctx.state.Host,
+			Collection: r.SelectedCollection,
+		}
+
+	case ui.CollectionListActionNew:
+		ctx.stack.Push(ScreenCollectionList, pushInput, r)
+		return ScreenCollectionEditor, ui.CollectionEditorInput{
+			Config: ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+
+	case ui.CollectionListActionDownloadMissing:
+		downloadMissingCollectionUI(ctx.state, r.SelectedCollection)
+		pushInput.LastSelectedIndex = r.LastSelectedIndex
+		pushInput.LastSelectedPosition = r.LastSelectedPosition
+		return ScreenCollectionList, pushInput
+
+	case ui.CollectionListActionSubscription:
+		ctx.stack.Push(ScreenCollectionList, pushInput, r)
+		return ScreenCollectionSubscription, ui.CollectionSubscriptionInput{
+			Collection: r.SelectedCollection,
+		}
+
 	case ui.CollectionListActionBack:
 		return popOrExit(ctx.stack)
 	}
@@ -374,6 +466,33 @@ func transitionCollectionList(ctx *transitionContext, result any) (router.Screen
 	return router.ScreenExit, nil
 }
 
+func transitionCollectionSubscription(ctx *transitionContext, result any) (router.Screen, any) {
+	r := result.(ui.CollectionSubscriptionOutput)
+	if r.Action == ui.CollectionSubscriptionActionSubscribed {
+		downloadMissingCollectionUI(ctx.state, r.Collection)
+	}
+	return popOrExit(ctx.stack)
+}
+
+func transitionRecentlyPlayed(ctx *transitionContext, result any) (router.Screen, any) {
+	r := result.(ui.RecentlyPlayedOutput)
+
+	if r.Action == ui.RecentlyPlayedActionSelected {
+		ctx.stack.Push(ScreenRecentlyPlayed, ui.RecentlyPlayedInput{
+			Config: ctx.state.Config,
+			Host:   ctx.state.Host,
+		}, r)
+		return ScreenGameDetails, ui.GameDetailsInput{
+			Config:   ctx.state.Config,
+			Host:     ctx.state.Host,
+			Platform: r.Platform,
+			Game:     r.SelectedGame,
+		}
+	}
+
+	return popOrExit(ctx.stack)
+}
+
 func transitionCollectionPlatformSelection(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.CollectionPlatformSelectionOutput)
 
@@ -459,6 +578,14 @@ func transitionSettings(ctx *transitionContext, result any) (router.Screen, any)
 			Host:           &ctx.state.Host,
 		}
 
+	case ui.SettingsActionManageGames:
+		ctx.stack.Push(ScreenSettings, pushInput, r)
+		return ScreenLocalGames, ui.LocalGamesInput{Config: ctx.state.Config}
+
+	case ui.SettingsActionStorage:
+		ctx.stack.Push(ScreenSettings, pushInput, r)
+		return ScreenStorage, ui.StorageInput{Config: ctx.state.Config}
+
 	case ui.SettingsActionSaved, ui.SettingsActionBack:
 		return popOrExitWithCollections(ctx.stack, ctx.showCollections)
 	}
@@ -476,6 +603,14 @@ func transitionGeneralSettings(ctx *transitionContext, result any) (router.Scree
 
 func transitionCollectionsSettings(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.CollectionsSettingsOutput)
+
+	if r.Action == ui.CollectionsSettingsActionNewCollection {
+		ctx.stack.Push(ScreenCollectionsSettings, ui.CollectionsSettingsInput{Config: ctx.state.Config}, nil)
+		return ScreenCollectionEditor, ui.CollectionEditorInput{
+			Config: ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+	}
 	if r.SyncNeeded {
 		if cm := cache.GetCacheManager(); cm != nil {
 			cm.ClearCollections()
@@ -485,7 +620,7 @@ func transitionCollectionsSettings(ctx *transitionContext, result any) (router.S
 				i18n.Localize(&goi18n.Message{ID: "collections_syncing", Other: "Syncing collections..."}, nil),
 				gaba.ProcessMessageOptions{ShowThemeBackground: true},
 				func() (any, error) {
-					cm.SyncCollectionsOnly()
+					cm.SyncCollectionsOnly(context.Background())
 					return nil, nil
 				},
 			)
@@ -578,6 +713,47 @@ func transitionLogoutConfirmation(ctx *transitionContext, result any) (router.Sc
 	return popOrExit(ctx.stack)
 }
 
+func hostSelectionInput(state *AppState) ui.HostSelectionInput {
+	return ui.HostSelectionInput{Hosts: state.Config.Hosts, ActiveHost: state.Host}
+}
+
+func transitionHostSelection(ctx *transitionContext, result any) (router.Screen, any) {
+	r := result.(ui.HostSelectionOutput)
+
+	switched := false
+	switch r.Action {
+	case ui.HostSelectionActionSelected:
+		if r.Host.URL() == ctx.state.Host.URL() {
+			return popOrExit(ctx.stack)
+		}
+		switched = switchHostUI(ctx.state, r.Host)
+
+	case ui.HostSelectionActionAdd:
+		switched = addHostUI(ctx.state)
+
+	case ui.HostSelectionActionRemove:
+		removeHostUI(ctx.state, r.Host)
+		return ScreenHostSelection, hostSelectionInput(ctx.state)
+
+	case ui.HostSelectionActionBack:
+		return popOrExit(ctx.stack)
+	}
+
+	if !switched {
+		return ScreenHostSelection, hostSelectionInput(ctx.state)
+	}
+
+	// The screens on the stack belong to the previous host, so start over from the platforms
+	ctx.stack.Clear()
+	ctx.showCollections = ctx.state.Config.ShowCollections(ctx.state.Host)
+	return ScreenPlatformSelection, ui.PlatformSelectionInput{
+		Platforms:       &ctx.state.Platforms,
+		QuitOnBack:      ctx.quitOnBack,
+		ShowCollections: ctx.showCollections,
+		ShowSaveSync:    computeShowSaveSync(ctx.state),
+	}
+}
+
 func transitionRebuildCache(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.RebuildCacheOutput)
 	if len(r.UpdatedPlatforms) > 0 {
@@ -636,6 +812,20 @@ func transitionGameFilters(ctx *transitionContext, result any) (router.Screen, a
 	return popOrExit(ctx.stack)
 }
 
+func transitionDownloadQueue(ctx *transitionContext, result any) (router.Screen, any) {
+	r := result.(ui.DownloadQueueOutput)
+
+	if r.Action == ui.DownloadQueueActionStart {
+		ctx.state.Downloads.Trigger()
+		return ScreenDownloadQueue, ui.DownloadQueueInput{
+			LastSelectedIndex:    r.LastSelectedIndex,
+			LastSelectedPosition: r.LastSelectedPosition,
+		}
+	}
+
+	return popOrExit(ctx.stack)
+}
+
 func popOrExitWithCollections(stack *router.Stack, showCollections bool) (router.Screen, any) {
 	screen, input := popOrExit(stack)
 	if psInput, ok := input.(ui.PlatformSelectionInput); ok {
@@ -679,6 +869,14 @@ func popOrExit(stack *router.Stack) (router.Screen, any) {
 		}
 		return entry.Screen, input
 
+	case ui.RecentlyPlayedInput:
+		if entry.Resume != nil {
+			output := entry.Resume.(ui.RecentlyPlayedOutput)
+			input.LastSelectedIndex = output.LastSelectedIndex
+			input.LastSelectedPosition = output.LastSelectedPosition
+		}
+		return entry.Screen, input
+
 	case ui.CollectionPlatformSelectionInput:
 		if entry.Resume != nil {
 			output := entry.Resume.(ui.CollectionPlatformSelectionOutput)
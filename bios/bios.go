@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"grout/cfw"
 	"grout/internal/jsonutil"
+	"grout/romm"
 	"os"
 	"path/filepath"
 	"strings"
@@ -88,3 +89,40 @@ func GetFilesForPlatform(platformFSSlug string) []File {
 
 	return biosFiles
 }
+
+// MatchFirmware finds the BIOS file a RomM firmware entry provides for a platform, comparing
+// file names and paths case-insensitively. Returns false if the firmware isn't a known BIOS file.
+func MatchFirmware(fw romm.Firmware, platformFSSlug string) (File, bool) {
+	baseName := filepath.Base(fw.FilePath)
+
+	for _, candidate := range []string{fw.FileName, fw.FilePath, baseName} {
+		for _, biosFile := range GetFilesForPlatform(platformFSSlug) {
+			if strings.EqualFold(biosFile.FileName, candidate) ||
+				strings.EqualFold(biosFile.RelativePath, candidate) ||
+				strings.EqualFold(filepath.Base(biosFile.RelativePath), candidate) {
+				return biosFile, true
+			}
+		}
+	}
+
+	return File{}, false
+}
+
+// firmwareFile returns the BIOS file to install a firmware entry as, falling back to its
+// file name for firmware without metadata.
+func firmwareFile(fw romm.Firmware, platformFSSlug string) File {
+	if biosFile, ok := MatchFirmware(fw, platformFSSlug); ok {
+		return biosFile
+	}
+	return File{FileName: fw.FileName, RelativePath: fw.FileName}
+}
+
+// FirmwareExists checks if a RomM firmware entry is already installed for the given platform.
+func FirmwareExists(fw romm.Firmware, platformFSSlug string) bool {
+	return FileExists(firmwareFile(fw, platformFSSlug), platformFSSlug)
+}
+
+// SaveFirmware installs a RomM firmware entry where the platform's emulators look for it.
+func SaveFirmware(fw romm.Firmware, platformFSSlug string, data []byte) error {
+	return SaveFile(firmwareFile(fw, platformFSSlug), platformFSSlug, data)
+}
@@ -138,7 +138,7 @@ func DownloadAndCacheArtwork(rom romm.Rom, kind artutil.ArtKind, host romm.Host)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Authorization", host.BasicAuthHeader())
+	req.Header.Set("Authorization", host.AuthHeader())
 
 	client := &http.Client{Timeout: romm.DefaultClientTimeout}
 	resp, err := client.Do(req)
@@ -191,6 +191,11 @@ func SyncArtworkInBackground(artkind artutil.ArtKind, host romm.Host, games []ro
 		return
 	}
 
+	// Artwork URLs other than the cover are only in the full metadata
+	if cm := GetCacheManager(); cm != nil {
+		missing = cm.LoadFullGames(missing)
+	}
+
 	for _, rom := range missing {
 		if err := DownloadAndCacheArtwork(rom, artkind, host); err != nil {
 			logger.Debug("Failed to download artwork", "rom", rom.Name, "error", err)
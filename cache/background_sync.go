@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"grout/romm"
 	"sync"
 
@@ -27,21 +29,28 @@ type syncRequest struct {
 }
 
 type BackgroundSync struct {
-	platforms []romm.Platform
-	icon      *gaba.DynamicStatusBarIcon
-	requests  chan syncRequest
-	stop      chan struct{}
-	wg        sync.WaitGroup
-	mu        sync.Mutex
-	running   bool
+	platforms           []romm.Platform
+	onCollectionsSynced func(ctx context.Context)
+	icon                *gaba.DynamicStatusBarIcon
+	requests            chan syncRequest
+	stop                chan struct{}
+	cancel              context.CancelFunc
+	wg                  sync.WaitGroup
+	mu                  sync.Mutex
+	running             bool
 }
 
-func NewBackgroundSync(platforms []romm.Platform) *BackgroundSync {
+// NewBackgroundSync creates the sync for platforms. onCollectionsSynced, if set, is called
+// on the sync's goroutine each time the collections have been refreshed, with a context that
+// is cancelled when the sync is stopped.
+func NewBackgroundSync(platforms []romm.Platform, onCollectionsSynced func(ctx context.Context)) *BackgroundSync {
 	return &BackgroundSync{
-		platforms: platforms,
-		icon:      gaba.NewDynamicStatusBarIcon(iconSyncing),
-		requests:  make(chan syncRequest, 1),
-		stop:      make(chan struct{}),
+		platforms:           platforms,
+		onCollectionsSynced: onCollectionsSynced,
+		icon:                gaba.NewDynamicStatusBarIcon(iconSyncing),
+		requests:            make(chan syncRequest, 1),
+		stop:                make(chan struct{}),
+		cancel:              func() {},
 	}
 }
 
@@ -84,8 +93,10 @@ func (b *BackgroundSync) ensureWorkerRunning() bool {
 	b.running = true
 	b.requests = make(chan syncRequest, 1)
 	b.stop = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
 	b.wg.Add(1)
-	go b.worker()
+	go b.worker(ctx)
 	return true
 }
 
@@ -104,6 +115,8 @@ func (b *BackgroundSync) IsRunning() bool {
 	return b.running
 }
 
+// Stop cancels the sync in progress and waits for the worker to exit, so nothing is
+// left writing to the cache. Games of a platform being saved are rolled back.
 func (b *BackgroundSync) Stop() {
 	b.mu.Lock()
 	if !b.running {
@@ -112,16 +125,18 @@ func (b *BackgroundSync) Stop() {
 	}
 	b.running = false
 	close(b.stop)
+	b.cancel()
 	b.mu.Unlock()
 
 	gaba.GetLogger().Debug("BackgroundSync: Stop requested")
+	b.wg.Wait()
 }
 
 func (b *BackgroundSync) SetSynced() {
 	b.icon.SetText(iconSynced)
 }
 
-func (b *BackgroundSync) worker() {
+func (b *BackgroundSync) worker(ctx context.Context) {
 	logger := gaba.GetLogger()
 	defer b.wg.Done()
 
@@ -131,12 +146,12 @@ func (b *BackgroundSync) worker() {
 			logger.Debug("BackgroundSync: Worker stopped")
 			return
 		case req := <-b.requests:
-			b.runSync(req)
+			b.runSync(ctx, req)
 		}
 	}
 }
 
-func (b *BackgroundSync) runSync(req syncRequest) {
+func (b *BackgroundSync) runSync(ctx context.Context, req syncRequest) {
 	logger := gaba.GetLogger()
 
 	defer func() {
@@ -167,31 +182,30 @@ func (b *BackgroundSync) runSync(req syncRequest) {
 	switch req.Type {
 	case syncCollectionsOnly:
 		logger.Debug("BackgroundSync: Starting collections-only sync")
-		_, err = cm.SyncCollectionsOnly()
+		_, err = cm.SyncCollectionsOnly(ctx)
 
 	case syncPlatformsOnly:
 		logger.Debug("BackgroundSync: Starting platform games sync", "platforms", len(req.Platforms))
-		_, err = cm.SyncPlatformGames(req.Platforms)
+		_, err = cm.SyncPlatformGames(ctx, req.Platforms)
 
 	default:
 		logger.Debug("BackgroundSync: Starting full cache update")
-		_, err = cm.PopulateFullCacheWithProgress(b.platforms, nil)
+		_, err = cm.PopulateFullCacheWithProgress(ctx, b.platforms, nil)
 
 		// After full sync, retry any platforms that previously failed
 		if err == nil {
 			needSync := cm.GetPlatformsNeedingSync(b.platforms)
 			if len(needSync) > 0 {
 				logger.Debug("BackgroundSync: Retrying failed platforms", "count", len(needSync))
-				cm.SyncPlatformGames(needSync)
+				cm.SyncPlatformGames(ctx, needSync)
 			}
 		}
 	}
 
 	// Check if we were stopped mid-sync
-	select {
-	case <-b.stop:
+	if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+		logger.Debug("BackgroundSync: Sync cancelled")
 		return
-	default:
 	}
 
 	if err != nil {
@@ -202,4 +216,8 @@ func (b *BackgroundSync) runSync(req syncRequest) {
 
 	b.icon.SetText(iconSynced)
 	logger.Debug("BackgroundSync: Sync completed")
+
+	if req.Type != syncPlatformsOnly && b.onCollectionsSynced != nil {
+		b.onCollectionsSynced(ctx)
+	}
 }
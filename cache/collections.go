@@ -101,6 +101,27 @@ func (cm *Manager) GetCollectionsByType(collType string) ([]romm.Collection, err
 	return collections, nil
 }
 
+// upsertCollectionSQL stores a collection, updating the row it already has so the
+// game_collections entries pointing at it stay valid.
+const upsertCollectionSQL = `
+	INSERT INTO collections
+	(romm_id, virtual_id, type, name, rom_count, data_json, updated_at, cached_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(romm_id, type) DO UPDATE SET
+		name = excluded.name,
+		rom_count = excluded.rom_count,
+		data_json = excluded.data_json,
+		updated_at = excluded.updated_at,
+		cached_at = excluded.cached_at
+	ON CONFLICT(virtual_id) DO UPDATE SET
+		name = excluded.name,
+		rom_count = excluded.rom_count,
+		data_json = excluded.data_json,
+		updated_at = excluded.updated_at,
+		cached_at = excluded.cached_at
+	RETURNING id
+`
+
 func (cm *Manager) SaveCollections(collections []romm.Collection) error {
 	if cm == nil || !cm.initialized {
 		return ErrNotInitialized
@@ -118,11 +139,7 @@ func (cm *Manager) SaveCollections(collections []romm.Collection) error {
 	defer tx.Rollback()
 
 	now := nowUTC()
-	stmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO collections
-		(romm_id, virtual_id, type, name, rom_count, data_json, updated_at, cached_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`)
+	stmt, err := tx.Prepare(upsertCollectionSQL)
 	if err != nil {
 		return newCacheError("save", "collections", "", err)
 	}
@@ -174,3 +191,108 @@ func (cm *Manager) SaveCollections(collections []romm.Collection) error {
 	logger.Debug("Saved collections to cache", "count", len(collections))
 	return nil
 }
+
+// SaveCollection stores a single regular collection and replaces its games with its
+// ROMIDs. Used to show changes made from Grout without waiting for the next sync.
+func (cm *Manager) SaveCollection(collection romm.Collection) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	key := GetCollectionCacheKey(collection)
+	collection.ROMCount = len(collection.ROMIDs)
+
+	dataJSON, err := json.Marshal(collection)
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+	defer tx.Rollback()
+
+	var collectionID int64
+	err = tx.QueryRow(upsertCollectionSQL, collection.ID, nil, "regular", collection.Name, collection.ROMCount,
+		string(dataJSON), collection.UpdatedAt, nowUTC()).Scan(&collectionID)
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM game_collections WHERE collection_id = ?`, collectionID); err != nil {
+		return newCacheError("save", "collection_mappings", key, err)
+	}
+
+	for _, romID := range collection.ROMIDs {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO game_collections (game_id, collection_id) VALUES (?, ?)`, romID, collectionID); err != nil {
+			return newCacheError("save", "collection_mappings", key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	return nil
+}
+
+// DeleteCollection removes a regular collection deleted from Grout along with its games
+// and any subscription to it.
+func (cm *Manager) DeleteCollection(collection romm.Collection) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	key := GetCollectionCacheKey(collection)
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("delete", "collections", key, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		DELETE FROM game_collections WHERE collection_id IN
+		(SELECT id FROM collections WHERE romm_id = ? AND type = 'regular')
+	`, collection.ID); err != nil {
+		return newCacheError("delete", "collection_mappings", key, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM collections WHERE romm_id = ? AND type = 'regular'`, collection.ID); err != nil {
+		return newCacheError("delete", "collections", key, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM collection_subscriptions WHERE romm_id = ? AND type = 'regular'`, collection.ID); err != nil {
+		return newCacheError("delete", "collection_subscriptions", key, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("delete", "collections", key, err)
+	}
+
+	return nil
+}
+
+// InvalidateCollections makes the next sync fetch every collection instead of only
+// those changed since the last one, bringing the cache back in line with RomM.
+func (cm *Manager) InvalidateCollections() error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if _, err := cm.db.Exec(`DELETE FROM cache_metadata WHERE key = ?`, MetaKeyCollectionsRefreshedAt); err != nil {
+		return newCacheError("delete_metadata", MetaKeyCollectionsRefreshedAt, "", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package cache
+
+import (
+	"encoding/json"
+	"grout/romm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxDownloadRetries is how many times a queued download is attempted before it is marked failed.
+const MaxDownloadRetries = 3
+
+type DownloadStatus string
+
+const (
+	DownloadStatusQueued DownloadStatus = "queued"
+	DownloadStatusActive DownloadStatus = "active"
+	DownloadStatusFailed DownloadStatus = "failed"
+	DownloadStatusDone   DownloadStatus = "done"
+)
+
+type QueuedDownload struct {
+	ID         int64
+	Rom        romm.Rom
+	Platform   romm.Platform
+	FileID     int
+	Status     DownloadStatus
+	RetryCount int
+	LastError  string
+	QueuedAt   time.Time
+	UpdatedAt  time.Time
+}
+
+// EnqueueDownloads adds games to the download queue. Games already in the queue are reset
+// to queued with a fresh retry count. Games without a platform ID use their own platform fields.
+func (cm *Manager) EnqueueDownloads(platform romm.Platform, games []romm.Rom, fileID int) ([]QueuedDownload, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return nil, newCacheError("save", "download_queue", "", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO download_queue
+		(rom_id, file_id, platform_id, name, status, retry_count, last_error, rom_json, platform_json, queued_at, updated_at)
+		VALUES (?, ?, ?, ?, 'queued', 0, '', ?, ?, ?, ?)
+		ON CONFLICT(rom_id, file_id) DO UPDATE SET
+			status = 'queued',
+			retry_count = 0,
+			last_error = '',
+			rom_json = excluded.rom_json,
+			platform_json = excluded.platform_json,
+			updated_at = excluded.updated_at
+		RETURNING id, queued_at
+	`)
+	if err != nil {
+		return nil, newCacheError("save", "download_queue", "", err)
+	}
+	defer stmt.Close()
+
+	now := nowUTC()
+	queued := make([]QueuedDownload, 0, len(games))
+
+	for _, game := range games {
+		gamePlatform := platform
+		if platform.ID == 0 && game.PlatformID != 0 {
+			gamePlatform = romm.Platform{
+				ID:     game.PlatformID,
+				FSSlug: game.PlatformFSSlug,
+				Name:   game.PlatformDisplayName,
+			}
+		}
+
+		romJSON, err := json.Marshal(game)
+		if err != nil {
+			return nil, newCacheError("save", "download_queue", strconv.Itoa(game.ID), err)
+		}
+		platformJSON, err := json.Marshal(gamePlatform)
+		if err != nil {
+			return nil, newCacheError("save", "download_queue", strconv.Itoa(game.ID), err)
+		}
+
+		entry := QueuedDownload{
+			Rom:      game,
+			Platform: gamePlatform,
+			FileID:   fileID,
+			Status:   DownloadStatusQueued,
+		}
+
+		var queuedAt string
+		if err := stmt.QueryRow(game.ID, fileID, gamePlatform.ID, game.Name, string(romJSON), string(platformJSON), now, now).Scan(&entry.ID, &queuedAt); err != nil {
+			return nil, newCacheError("save", "download_queue", strconv.Itoa(game.ID), err)
+		}
+		entry.QueuedAt, _ = time.Parse(time.RFC3339, queuedAt)
+		entry.UpdatedAt, _ = time.Parse(time.RFC3339, now)
+
+		queued = append(queued, entry)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, newCacheError("save", "download_queue", "", err)
+	}
+
+	return queued, nil
+}
+
+// GetDownloadQueue returns queue entries in the order they were added, optionally limited to the given statuses.
+func (cm *Manager) GetDownloadQueue(statuses ...DownloadStatus) ([]QueuedDownload, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	query := `
+		SELECT id, file_id, status, retry_count, last_error, rom_json, platform_json, queued_at, updated_at
+		FROM download_queue
+	`
+	args := make([]any, 0, len(statuses))
+	if len(statuses) > 0 {
+		placeholders := make([]string, len(statuses))
+		for i, status := range statuses {
+			placeholders[i] = "?"
+			args = append(args, string(status))
+		}
+		query += " WHERE status IN (" + strings.Join(placeholders, ",") + ")"
+	}
+	query += " ORDER BY id"
+
+	rows, err := cm.db.Query(query, args...)
+	if err != nil {
+		return nil, newCacheError("get", "download_queue", "", err)
+	}
+	defer rows.Close()
+
+	var entries []QueuedDownload
+	for rows.Next() {
+		var entry QueuedDownload
+		var status, romJSON, platformJSON, queuedAt, updatedAt string
+		if err := rows.Scan(&entry.ID, &entry.FileID, &status, &entry.RetryCount, &entry.LastError, &romJSON, &platformJSON, &queuedAt, &updatedAt); err != nil {
+			return nil, newCacheError("get", "download_queue", "", err)
+		}
+		if err := json.Unmarshal([]byte(romJSON), &entry.Rom); err != nil {
+			return nil, newCacheError("get", "download_queue", strconv.FormatInt(entry.ID, 10), err)
+		}
+		if err := json.Unmarshal([]byte(platformJSON), &entry.Platform); err != nil {
+			return nil, newCacheError("get", "download_queue", strconv.FormatInt(entry.ID, 10), err)
+		}
+		entry.Status = DownloadStatus(status)
+		entry.QueuedAt, _ = time.Parse(time.RFC3339, queuedAt)
+		entry.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newCacheError("get", "download_queue", "", err)
+	}
+
+	return entries, nil
+}
+
+// CountPendingDownloads returns how many entries are queued or active.
+func (cm *Manager) CountPendingDownloads() int {
+	if cm == nil || !cm.initialized {
+		return 0
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var count int
+	err := cm.db.QueryRow(`
+		SELECT COUNT(*) FROM download_queue WHERE status IN ('queued', 'active')
+	`).Scan(&count)
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
+
+// HasDownloadQueue reports whether the queue holds anything other than finished downloads.
+func (cm *Manager) HasDownloadQueue() bool {
+	if cm == nil || !cm.initialized {
+		return false
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var exists int
+	err := cm.db.QueryRow(`
+		SELECT 1 FROM download_queue WHERE status != 'done' LIMIT 1
+	`).Scan(&exists)
+
+	return err == nil
+}
+
+func (cm *Manager) setDownloadStatus(id int64, status DownloadStatus) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		UPDATE download_queue SET status = ?, updated_at = ? WHERE id = ?
+	`, string(status), nowUTC(), id)
+	if err != nil {
+		return newCacheError("save", "download_queue", strconv.FormatInt(id, 10), err)
+	}
+
+	return nil
+}
+
+func (cm *Manager) MarkDownloadActive(id int64) error {
+	return cm.setDownloadStatus(id, DownloadStatusActive)
+}
+
+func (cm *Manager) MarkDownloadQueued(id int64) error {
+	return cm.setDownloadStatus(id, DownloadStatusQueued)
+}
+
+func (cm *Manager) MarkDownloadDone(id int64) error {
+	return cm.setDownloadStatus(id, DownloadStatusDone)
+}
+
+// MarkDownloadFailed records a failed attempt. The entry goes back to queued until it has
+// failed MaxDownloadRetries times, after which it stays failed until retried by the user.
+func (cm *Manager) MarkDownloadFailed(id int64, downloadErr error) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	message := ""
+	if downloadErr != nil {
+		message = downloadErr.Error()
+	}
+
+	_, err := cm.db.Exec(`
+		UPDATE download_queue SET
+			retry_count = retry_count + 1,
+			status = CASE WHEN retry_count + 1 >= ? THEN 'failed' ELSE 'queued' END,
+			last_error = ?,
+			updated_at = ?
+		WHERE id = ?
+	`, MaxDownloadRetries, message, nowUTC(), id)
+	if err != nil {
+		return newCacheError("save", "download_queue", strconv.FormatInt(id, 10), err)
+	}
+
+	return nil
+}
+
+// RetryDownload puts a failed entry back in the queue with a fresh retry count.
+func (cm *Manager) RetryDownload(id int64) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		UPDATE download_queue SET status = 'queued', retry_count = 0, last_error = '', updated_at = ?
+		WHERE id = ?
+	`, nowUTC(), id)
+	if err != nil {
+		return newCacheError("save", "download_queue", strconv.FormatInt(id, 10), err)
+	}
+
+	return nil
+}
+
+func (cm *Manager) RemoveDownload(id int64) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`DELETE FROM download_queue WHERE id = ?`, id)
+	if err != nil {
+		return newCacheError("delete", "download_queue", strconv.FormatInt(id, 10), err)
+	}
+
+	return nil
+}
+
+// ClearFinishedDownloads removes completed entries from the queue.
+func (cm *Manager) ClearFinishedDownloads() error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`DELETE FROM download_queue WHERE status = 'done'`)
+	if err != nil {
+		return newCacheError("delete", "download_queue", "", err)
+	}
+
+	return nil
+}
+
+// ResetActiveDownloads requeues entries left active by an app exit mid-download.
+func (cm *Manager) ResetActiveDownloads() error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		UPDATE download_queue SET status = 'queued', updated_at = ? WHERE status = 'active'
+	`, nowUTC())
+	if err != nil {
+		return newCacheError("save", "download_queue", "", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -42,42 +43,81 @@ func GetCollectionCacheKey(collection romm.Collection) string {
 	return GetCacheKey(Collection, strconv.Itoa(collection.ID))
 }
 
-func (cm *Manager) GetPlatformGames(platformID int) ([]romm.Rom, error) {
-	if cm == nil || !cm.initialized {
-		return nil, ErrNotInitialized
+// listValueSeparator joins the values stored in list columns, such as regions.
+const listValueSeparator = "\x1f"
+
+// gameListColumns are what list screens show and filter games by. Lists read these instead
+// of data_json, so thousands of games are listed without decoding their full metadata.
+const gameListColumns = `g.id, g.platform_id, g.platform_fs_slug, g.platform_display_name, g.name,
+	g.fs_name, g.fs_name_no_ext, g.fs_size_bytes, g.crc_hash, g.md5_hash, g.sha1_hash,
+	g.regions, g.file_names, g.has_multiple_files, g.has_nested_single_file, g.has_manual,
+	g.is_identified, g.is_unidentified, g.missing_from_fs, g.first_release_date, g.average_rating,
+	g.path_cover_small, g.path_cover_large, g.url_cover`
+
+func joinListValues(values []string) string {
+	return strings.Join(values, listValueSeparator)
+}
+
+func splitListValues(value string) []string {
+	if value == "" {
+		return nil
 	}
+	return strings.Split(value, listValueSeparator)
+}
 
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+// scanListGame reads a row of gameListColumns. The game has only the fields those columns
+// hold; LoadFullGames loads the rest for games that need it.
+func scanListGame(rows *sql.Rows) (romm.Rom, error) {
+	var game romm.Rom
+	var regions, fileNames string
+	var hasMultiple, hasNested, hasManual, identified, unidentified, missing int
+	err := rows.Scan(
+		&game.ID, &game.PlatformID, &game.PlatformFSSlug, &game.PlatformDisplayName, &game.Name,
+		&game.FsName, &game.FsNameNoExt, &game.FsSizeBytes, &game.CrcHash, &game.Md5Hash, &game.Sha1Hash,
+		&regions, &fileNames, &hasMultiple, &hasNested, &hasManual,
+		&identified, &unidentified, &missing, &game.Metadatum.FirstReleaseDate, &game.Metadatum.AverageRating,
+		&game.PathCoverSmall, &game.PathCoverLarge, &game.URLCover,
+	)
+	if err != nil {
+		return romm.Rom{}, err
+	}
 
-	rows, err := cm.db.Query(`
-		SELECT data_json FROM games WHERE platform_id = ? ORDER BY name
-	`, platformID)
+	game.Regions = splitListValues(regions)
+	for _, name := range splitListValues(fileNames) {
+		game.Files = append(game.Files, romm.RomFile{RomID: game.ID, FileName: name})
+	}
+	game.HasMultipleFiles = hasMultiple != 0
+	game.HasNestedSingleFile = hasNested != 0
+	game.HasManual = hasManual != 0
+	game.IsIdentified = identified != 0
+	game.IsUnidentified = unidentified != 0
+	game.MissingFromFs = missing != 0
+	return game, nil
+}
+
+// queryListGames runs a query selecting gameListColumns from games aliased as g. The
+// caller holds the read lock.
+func (cm *Manager) queryListGames(key string, query string, args ...any) ([]romm.Rom, error) {
+	rows, err := cm.db.Query(query, args...)
 	if err != nil {
 		cm.stats.recordError()
-		return nil, newCacheError("get", "games", GetPlatformCacheKey(platformID), err)
+		return nil, newCacheError("get", "games", key, err)
 	}
 	defer rows.Close()
 
 	var games []romm.Rom
 	for rows.Next() {
-		var dataJSON string
-		if err := rows.Scan(&dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", GetPlatformCacheKey(platformID), err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
+		game, err := scanListGame(rows)
+		if err != nil {
 			cm.stats.recordError()
-			return nil, newCacheError("get", "games", GetPlatformCacheKey(platformID), err)
+			return nil, newCacheError("get", "games", key, err)
 		}
 		games = append(games, game)
 	}
 
 	if err := rows.Err(); err != nil {
 		cm.stats.recordError()
-		return nil, newCacheError("get", "games", GetPlatformCacheKey(platformID), err)
+		return nil, newCacheError("get", "games", key, err)
 	}
 
 	if len(games) > 0 {
@@ -89,6 +129,53 @@ func (cm *Manager) GetPlatformGames(platformID int) ([]romm.Rom, error) {
 	return games, nil
 }
 
+// LoadFullGames returns the full cached metadata of games read from a list, in the same
+// order and keeping their display names. Games missing from the cache are returned as is.
+func (cm *Manager) LoadFullGames(games []romm.Rom) []romm.Rom {
+	const batchSize = 500
+
+	byID := make(map[int]romm.Rom, len(games))
+	for i := 0; i < len(games); i += batchSize {
+		batch := games[i:min(i+batchSize, len(games))]
+		ids := make([]int, len(batch))
+		for j, game := range batch {
+			ids[j] = game.ID
+		}
+
+		full, err := cm.GetGamesByIDs(ids)
+		if err != nil {
+			gaba.GetLogger().Warn("Unable to load full game metadata", "error", err)
+			return games
+		}
+		for _, game := range full {
+			byID[game.ID] = game
+		}
+	}
+
+	result := make([]romm.Rom, len(games))
+	for i, game := range games {
+		result[i] = game
+		if full, ok := byID[game.ID]; ok {
+			full.DisplayName = game.DisplayName
+			result[i] = full
+		}
+	}
+	return result
+}
+
+func (cm *Manager) GetPlatformGames(platformID int) ([]romm.Rom, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return cm.queryListGames(GetPlatformCacheKey(platformID), `
+		SELECT `+gameListColumns+` FROM games g WHERE g.platform_id = ? ORDER BY g.name
+	`, platformID)
+}
+
 func boolToInt(b bool) int {
 	if b {
 		return 1
@@ -183,54 +270,74 @@ func batchInsertJunction(tx *sql.Tx, junctionTable, fkCol, lookupTable string, g
 	return nil
 }
 
-func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
+// SaveGames writes a batch of games to the cache in one transaction, replacing any
+// already cached. The transaction is rolled back if ctx is cancelled before it commits.
+func (cm *Manager) SaveGames(ctx context.Context, games []romm.Rom) error {
 	if cm == nil || !cm.initialized {
 		return ErrNotInitialized
 	}
 
+	if len(games) == 0 {
+		return nil
+	}
+
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
-	tx, err := cm.db.Begin()
+	tx, err := cm.db.BeginTx(ctx, nil)
 	if err != nil {
-		return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+		return newCacheError("save", "games", "batch", err)
 	}
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
 		INSERT OR REPLACE INTO games (
-			id, platform_id, platform_fs_slug, name, fs_name, fs_name_no_ext,
+			id, platform_id, platform_fs_slug, name, fs_name, fs_name_no_ext, platform_display_name,
 			crc_hash, md5_hash, sha1_hash,
 			player_count, first_release_date, average_rating, fs_size_bytes,
 			is_identified, is_unidentified, missing_from_fs, has_manual, has_multiple_files,
+			has_nested_single_file, regions, file_names, path_cover_small, path_cover_large, url_cover,
 			data_json, updated_at, cached_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
-		return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+		return newCacheError("save", "games", "batch", err)
 	}
 	defer stmt.Close()
 
-	// Delete existing junction table data for this platform's games
+	// Delete existing junction table data for these games
+	placeholders := make([]string, len(games))
+	ids := make([]any, len(games))
+	for i, game := range games {
+		placeholders[i] = "?"
+		ids[i] = game.ID
+	}
 	for _, table := range junctionTables {
-		_, err := tx.Exec(
-			"DELETE FROM "+table+" WHERE game_id IN (SELECT id FROM games WHERE platform_id = ?)",
-			platformID,
-		)
+		_, err := tx.Exec("DELETE FROM "+table+" WHERE game_id IN ("+strings.Join(placeholders, ",")+")", ids...)
 		if err != nil {
-			return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+			return newCacheError("save", "games", "batch", err)
 		}
 	}
 
 	now := nowUTC()
-	cacheKey := GetPlatformCacheKey(platformID)
 
 	for _, game := range games {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cacheKey := GetPlatformCacheKey(game.PlatformID)
+
 		dataJSON, err := json.Marshal(game)
 		if err != nil {
 			return newCacheError("save", "games", cacheKey, err)
 		}
 
+		fileNames := make([]string, len(game.Files))
+		for i, file := range game.Files {
+			fileNames[i] = file.FileName
+		}
+
 		_, err = stmt.Exec(
 			game.ID,
 			game.PlatformID,
@@ -238,6 +345,7 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 			game.Name,
 			game.FsName,
 			game.FsNameNoExt,
+			game.PlatformDisplayName,
 			game.CrcHash,
 			game.Md5Hash,
 			game.Sha1Hash,
@@ -250,6 +358,12 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 			boolToInt(game.MissingFromFs),
 			boolToInt(game.HasManual),
 			boolToInt(game.HasMultipleFiles),
+			boolToInt(game.HasNestedSingleFile),
+			joinListValues(game.Regions),
+			joinListValues(fileNames),
+			game.PathCoverSmall,
+			game.PathCoverLarge,
+			game.URLCover,
 			string(dataJSON),
 			game.UpdatedAt,
 			now,
@@ -280,7 +394,7 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 	}
 
 	if err := tx.Commit(); err != nil {
-		return newCacheError("save", "games", cacheKey, err)
+		return newCacheError("save", "games", "batch", err)
 	}
 
 	return nil
@@ -299,46 +413,12 @@ func (cm *Manager) GetCollectionGames(collection romm.Collection) ([]romm.Rom, e
 		return nil, err
 	}
 
-	rows, err := cm.db.Query(`
-		SELECT g.data_json FROM games g
+	return cm.queryListGames(GetCollectionCacheKey(collection), `
+		SELECT `+gameListColumns+` FROM games g
 		INNER JOIN game_collections gc ON g.id = gc.game_id
 		WHERE gc.collection_id = ?
 		ORDER BY g.name
 	`, collectionID)
-	if err != nil {
-		cm.stats.recordError()
-		return nil, newCacheError("get", "games", GetCollectionCacheKey(collection), err)
-	}
-	defer rows.Close()
-
-	var games []romm.Rom
-	for rows.Next() {
-		var dataJSON string
-		if err := rows.Scan(&dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", GetCollectionCacheKey(collection), err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", GetCollectionCacheKey(collection), err)
-		}
-		games = append(games, game)
-	}
-
-	if err := rows.Err(); err != nil {
-		cm.stats.recordError()
-		return nil, newCacheError("get", "games", GetCollectionCacheKey(collection), err)
-	}
-
-	if len(games) > 0 {
-		cm.stats.recordHit()
-	} else {
-		cm.stats.recordMiss()
-	}
-
-	return games, nil
 }
 
 func (cm *Manager) SaveCollectionGames(collection romm.Collection, games []romm.Rom) error {
@@ -466,8 +546,9 @@ func (cm *Manager) SaveAllCollectionMappings(collections []romm.Collection) erro
 	}
 	defer tx.Rollback()
 
-	// Clear all existing mappings
-	if _, err := tx.Exec(`DELETE FROM game_collections`); err != nil {
+	// Incremental syncs only bring the collections that changed, so only theirs are
+	// replaced. Mappings left behind by collections no longer cached go as well.
+	if _, err := tx.Exec(`DELETE FROM game_collections WHERE collection_id NOT IN (SELECT id FROM collections)`); err != nil {
 		return newCacheError("save", "collection_mappings", "", err)
 	}
 
@@ -489,6 +570,9 @@ func (cm *Manager) SaveAllCollectionMappings(collections []romm.Collection) erro
 
 		if err == nil {
 			collectionIDs[GetCollectionCacheKey(coll)] = id
+			if _, err := tx.Exec(`DELETE FROM game_collections WHERE collection_id = ?`, id); err != nil {
+				return newCacheError("save", "collection_mappings", GetCollectionCacheKey(coll), err)
+			}
 		}
 	}
 
@@ -658,43 +742,8 @@ func (cm *Manager) GetGamesForPlatform(fsSlug string) ([]romm.Rom, error) {
 		args[i] = slug
 	}
 
-	query := `SELECT id, name, data_json FROM games WHERE platform_fs_slug IN (` + strings.Join(placeholders, ",") + `)`
-	rows, err := cm.db.Query(query, args...)
-	if err != nil {
-		cm.stats.recordError()
-		return nil, newCacheError("get", "games", fsSlug, err)
-	}
-	defer rows.Close()
-
-	var games []romm.Rom
-	for rows.Next() {
-		var id int
-		var name string
-		var dataJSON string
-		if err := rows.Scan(&id, &name, &dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", fsSlug, err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
-			game = romm.Rom{ID: id, Name: name}
-		}
-		games = append(games, game)
-	}
-
-	if err := rows.Err(); err != nil {
-		cm.stats.recordError()
-		return nil, newCacheError("get", "games", fsSlug, err)
-	}
-
-	if len(games) > 0 {
-		cm.stats.recordHit()
-	} else {
-		cm.stats.recordMiss()
-	}
-
-	return games, nil
+	query := `SELECT ` + gameListColumns + ` FROM games g WHERE g.platform_fs_slug IN (` + strings.Join(placeholders, ",") + `)`
+	return cm.queryListGames(fsSlug, query, args...)
 }
 
 func (cm *Manager) GetRomIDByFilename(fsSlug, filename string) (int, string, bool) {
@@ -1002,7 +1051,7 @@ func (f GameFilter) HasActiveFilters() bool {
 }
 
 // GetFilteredGames returns games matching all the given filter criteria.
-// Results are always deserialized from data_json for full Rom objects.
+// Results hold the list columns only; see scanListGame.
 func (cm *Manager) GetFilteredGames(filter GameFilter) ([]romm.Rom, error) {
 	if cm == nil || !cm.initialized {
 		return nil, ErrNotInitialized
@@ -1011,7 +1060,7 @@ func (cm *Manager) GetFilteredGames(filter GameFilter) ([]romm.Rom, error) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
-	query := "SELECT g.data_json FROM games g WHERE 1=1"
+	query := "SELECT " + gameListColumns + " FROM games g WHERE 1=1"
 	var args []interface{}
 
 	if filter.CollectionInternalID != 0 {
@@ -1116,41 +1165,7 @@ func (cm *Manager) GetFilteredGames(filter GameFilter) ([]romm.Rom, error) {
 
 	query += " ORDER BY g.name"
 
-	rows, err := cm.db.Query(query, args...)
-	if err != nil {
-		cm.stats.recordError()
-		return nil, newCacheError("get", "games", "filtered", err)
-	}
-	defer rows.Close()
-
-	var games []romm.Rom
-	for rows.Next() {
-		var dataJSON string
-		if err := rows.Scan(&dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", "filtered", err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", "filtered", err)
-		}
-		games = append(games, game)
-	}
-
-	if err := rows.Err(); err != nil {
-		cm.stats.recordError()
-		return nil, newCacheError("get", "games", "filtered", err)
-	}
-
-	if len(games) > 0 {
-		cm.stats.recordHit()
-	} else {
-		cm.stats.recordMiss()
-	}
-
-	return games, nil
+	return cm.queryListGames("filtered", query, args...)
 }
 
 // GetDistinctValues returns all distinct names from a lookup table, optionally
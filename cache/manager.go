@@ -1,11 +1,13 @@
 package cache
 
 import (
+	"context"
 	"database/sql"
 	"grout/internal/fileutil"
 	"grout/romm"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 
 type Manager struct {
 	db          *sql.DB
+	dir         string
 	dbPath      string
 	mu          sync.RWMutex
 	host        romm.Host
@@ -54,33 +57,75 @@ func (s *Stats) recordError() {
 }
 
 var (
+	// cacheManagerMu guards the manager, which is swapped when the host changes
+	cacheManagerMu   sync.RWMutex
 	cacheManager     *Manager
 	cacheManagerOnce sync.Once
 	cacheManagerErr  error
 )
 
 func GetCacheManager() *Manager {
+	cacheManagerMu.RLock()
+	defer cacheManagerMu.RUnlock()
 	return cacheManager
 }
 
 func InitCacheManager(host romm.Host, config Config) error {
+	cacheManagerMu.Lock()
+	defer cacheManagerMu.Unlock()
+	return initCacheManager(host, config)
+}
+
+func initCacheManager(host romm.Host, config Config) error {
 	cacheManagerOnce.Do(func() {
 		cacheManager, cacheManagerErr = newCacheManager(host, config)
 	})
 	return cacheManagerErr
 }
 
+// SwitchCacheManager closes the cache of the current host and opens the one for host.
+// Every host has its own database and artwork, so switching back and forth keeps both.
+// Background work holding on to the current manager must be stopped first, as it is closed.
+func SwitchCacheManager(host romm.Host, config Config) error {
+	cacheManagerMu.Lock()
+	defer cacheManagerMu.Unlock()
+
+	if cacheManager != nil {
+		cacheManager.Close()
+		cacheManager = nil
+	}
+
+	cacheManagerOnce = sync.Once{}
+	cacheManagerErr = nil
+	return initCacheManager(host, config)
+}
+
+// HostCache returns the cache of host along with a function to call once done with it. That
+// is the current cache if it belongs to host, or else host's own, opened until released.
+func HostCache(host romm.Host, config Config) (*Manager, func(), error) {
+	if cm := GetCacheManager(); cm != nil && cm.host.URL() == host.URL() {
+		return cm, func() {}, nil
+	}
+
+	cm, err := newCacheManager(host, config)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return cm, func() { cm.Close() }, nil
+}
+
 func newCacheManager(host romm.Host, config Config) (*Manager, error) {
 	logger := gaba.GetLogger()
 
-	dbPath := getCacheDBPath()
-
-	cacheDir := filepath.Dir(dbPath)
+	cacheDir := hostCacheDir(host)
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return nil, newCacheError("init", "", "", err)
 	}
 
 	cleanupLegacyCache()
+	moveSingleHostCache(cacheDir)
+
+	dbPath := filepath.Join(cacheDir, "grout.db")
 
 	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
 	if err != nil {
@@ -102,6 +147,7 @@ func newCacheManager(host romm.Host, config Config) (*Manager, error) {
 
 	cm := &Manager{
 		db:          db,
+		dir:         cacheDir,
 		dbPath:      dbPath,
 		host:        host,
 		config:      config,
@@ -179,6 +225,9 @@ func (cm *Manager) HasCache() bool {
 	return err == nil && count > 0
 }
 
+// Clear empties the host's library: its games, platforms, collections and artwork, so the
+// next sync fetches everything again. The download queue, save sync state, collection
+// subscriptions and play activity are kept.
 func (cm *Manager) Clear() error {
 	if cm == nil || !cm.initialized {
 		return ErrNotInitialized
@@ -205,11 +254,17 @@ func (cm *Manager) Clear() error {
 		}
 	}
 
+	for _, key := range []string{MetaKeyPlatformsRefreshedAt, MetaKeyGamesRefreshedAt, MetaKeyCollectionsRefreshedAt} {
+		if _, err := tx.Exec(`DELETE FROM cache_metadata WHERE key = ?`, key); err != nil {
+			return newCacheError("clear", "cache_metadata", key, err)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return newCacheError("clear", "", "", err)
 	}
 
-	artworkDir := GetArtworkCacheDir()
+	artworkDir := filepath.Join(cm.dir, "artwork")
 	if fileutil.FileExists(artworkDir) {
 		os.RemoveAll(artworkDir)
 	}
@@ -361,23 +416,26 @@ func (cm *Manager) GetAllRefreshTimes() map[string]time.Time {
 	return result
 }
 
-func (cm *Manager) PopulateFullCacheWithProgress(platforms []romm.Platform, progress *atomic.Float64) (SyncStats, error) {
+// PopulateFullCacheWithProgress refreshes the cache from RomM until it is up to date or
+// ctx is done, in which case ctx's error is returned.
+func (cm *Manager) PopulateFullCacheWithProgress(ctx context.Context, platforms []romm.Platform, progress *atomic.Float64) (SyncStats, error) {
 	if cm == nil || !cm.initialized {
 		return SyncStats{}, ErrNotInitialized
 	}
 
-	return cm.populateCache(platforms, progress)
+	return cm.populateCache(ctx, platforms, progress)
 }
 
-func (cm *Manager) SyncCollectionsOnly() (int, error) {
+func (cm *Manager) SyncCollectionsOnly(ctx context.Context) (int, error) {
 	if cm == nil || !cm.initialized {
 		return 0, ErrNotInitialized
 	}
 
-	return cm.fetchAndCacheCollectionsWithProgress(nil, 0.0, 1.0), nil
+	count := cm.fetchAndCacheCollectionsWithProgress(ctx, nil, 0.0, 1.0)
+	return count, ctx.Err()
 }
 
-func (cm *Manager) SyncPlatformGames(platforms []romm.Platform) (int, error) {
+func (cm *Manager) SyncPlatformGames(ctx context.Context, platforms []romm.Platform) (int, error) {
 	if cm == nil || !cm.initialized {
 		return 0, ErrNotInitialized
 	}
@@ -386,7 +444,10 @@ func (cm *Manager) SyncPlatformGames(platforms []romm.Platform) (int, error) {
 	totalGames := 0
 
 	for _, platform := range platforms {
-		if err := cm.fetchPlatformGames(platform, nil); err != nil {
+		if err := cm.fetchPlatformGames(platform, &fetchOpts{ctx: ctx}); err != nil {
+			if ctx.Err() != nil {
+				return totalGames, ctx.Err()
+			}
 			logger.Error("Failed to sync platform games", "platform", platform.Name, "error", err)
 			cm.RecordPlatformSyncFailure(platform.ID)
 			continue
@@ -399,20 +460,24 @@ func (cm *Manager) SyncPlatformGames(platforms []romm.Platform) (int, error) {
 	return totalGames, nil
 }
 
-func getCacheDBPath() string {
-	wd, err := os.Getwd()
-	if err != nil {
-		return filepath.Join(os.TempDir(), ".cache", "grout.db")
-	}
-	return filepath.Join(wd, ".cache", "grout.db")
+// hostCacheDir returns the folder holding the cache database and artwork for a host.
+func hostCacheDir(host romm.Host) string {
+	key := strings.TrimPrefix(strings.TrimPrefix(host.URL(), "https://"), "http://")
+	key = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, key)
+	return filepath.Join(GetCacheDir(), "hosts", key)
 }
 
+// GetArtworkCacheDir returns the artwork folder of the current host's cache.
 func GetArtworkCacheDir() string {
-	wd, err := os.Getwd()
-	if err != nil {
-		return filepath.Join(os.TempDir(), ".cache", "artwork")
+	if cm := GetCacheManager(); cm != nil && cm.dir != "" {
+		return filepath.Join(cm.dir, "artwork")
 	}
-	return filepath.Join(wd, ".cache", "artwork")
+	return filepath.Join(GetCacheDir(), "artwork")
 }
 
 func GetCacheDir() string {
@@ -423,25 +488,31 @@ func GetCacheDir() string {
 	return filepath.Join(wd, ".cache")
 }
 
-func DeleteCacheFolder() error {
+// DeleteHostCache deletes the cache of a host that is no longer configured.
+func DeleteHostCache(host romm.Host) error {
+	return os.RemoveAll(hostCacheDir(host))
+}
+
+// moveSingleHostCache moves the cache from before each host had its own into hostDir. Only
+// one host could be configured then, so it belongs to the first host the cache is opened for.
+func moveSingleHostCache(hostDir string) {
 	logger := gaba.GetLogger()
 
-	if cacheManager != nil {
-		cacheManager.Close()
-		cacheManager = nil
+	legacyDB := filepath.Join(GetCacheDir(), "grout.db")
+	if !fileutil.FileExists(legacyDB) || fileutil.FileExists(filepath.Join(hostDir, "grout.db")) {
+		return
 	}
 
-	cacheManagerOnce = sync.Once{}
-	cacheManagerErr = nil
-
-	cacheDir := GetCacheDir()
-	if err := os.RemoveAll(cacheDir); err != nil {
-		logger.Error("Failed to delete cache folder", "path", cacheDir, "error", err)
-		return err
+	for _, name := range []string{"grout.db", "grout.db-wal", "grout.db-shm", "artwork"} {
+		from := filepath.Join(GetCacheDir(), name)
+		if !fileutil.FileExists(from) {
+			continue
+		}
+		if err := os.Rename(from, filepath.Join(hostDir, name)); err != nil {
+			logger.Warn("Failed to move cache to host folder", "path", from, "error", err)
+		}
 	}
-
-	logger.Info("Cache folder deleted", "path", cacheDir)
-	return nil
+	logger.Info("Moved cache to host folder", "path", hostDir)
 }
 
 func cleanupLegacyCache() {
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"strconv"
+	"time"
+)
+
+// PlayActivity is what the CFW recorded about a game being played. ReportedLastPlayed is
+// the last play that was reported to RomM, zero if none was.
+type PlayActivity struct {
+	RomID              int
+	LastPlayed         time.Time
+	PlayTime           time.Duration
+	ReportedLastPlayed time.Time
+}
+
+// NeedsReport reports whether the game was played since RomM was last told about it.
+func (a PlayActivity) NeedsReport() bool {
+	return a.LastPlayed.After(a.ReportedLastPlayed)
+}
+
+// SavePlayActivity records the latest play of a game. Older plays than the one recorded are
+// ignored, and ReportedLastPlayed is only stored for games not recorded yet.
+func (cm *Manager) SavePlayActivity(activity PlayActivity) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		INSERT INTO play_activity (rom_id, last_played, play_time, reported_last_played)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(rom_id) DO UPDATE SET
+			last_played = excluded.last_played,
+			play_time = excluded.play_time
+		WHERE excluded.last_played >= play_activity.last_played
+	`, activity.RomID, formatPlayTime(activity.LastPlayed), int64(activity.PlayTime/time.Second), formatPlayTime(activity.ReportedLastPlayed))
+	if err != nil {
+		return newCacheError("save", "play_activity", strconv.Itoa(activity.RomID), err)
+	}
+	return nil
+}
+
+// GetPlayActivity returns the games played, most recently played first.
+func (cm *Manager) GetPlayActivity() ([]PlayActivity, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	rows, err := cm.db.Query(`
+		SELECT rom_id, last_played, play_time, reported_last_played FROM play_activity
+		ORDER BY last_played DESC
+	`)
+	if err != nil {
+		return nil, newCacheError("get", "play_activity", "", err)
+	}
+	defer rows.Close()
+
+	var activity []PlayActivity
+	for rows.Next() {
+		var a PlayActivity
+		var lastPlayed, reportedLastPlayed string
+		var playTime int64
+		if err := rows.Scan(&a.RomID, &lastPlayed, &playTime, &reportedLastPlayed); err != nil {
+			return nil, newCacheError("get", "play_activity", "", err)
+		}
+		a.LastPlayed, _ = time.Parse(time.RFC3339, lastPlayed)
+		a.ReportedLastPlayed, _ = time.Parse(time.RFC3339, reportedLastPlayed)
+		a.PlayTime = time.Duration(playTime) * time.Second
+		activity = append(activity, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newCacheError("get", "play_activity", "", err)
+	}
+	return activity, nil
+}
+
+func (cm *Manager) HasPlayActivity() (bool, error) {
+	if cm == nil || !cm.initialized {
+		return false, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var count int
+	if err := cm.db.QueryRow(`SELECT COUNT(*) FROM play_activity`).Scan(&count); err != nil {
+		return false, newCacheError("get", "play_activity", "", err)
+	}
+	return count > 0, nil
+}
+
+// MarkPlayActivityReported records that RomM was told about the play of activity.
+func (cm *Manager) MarkPlayActivityReported(activity PlayActivity) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		UPDATE play_activity SET reported_last_played = ? WHERE rom_id = ?
+	`, formatPlayTime(activity.LastPlayed), activity.RomID)
+	if err != nil {
+		return newCacheError("save", "play_activity", strconv.Itoa(activity.RomID), err)
+	}
+	return nil
+}
+
+func formatPlayTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
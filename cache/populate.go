@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"grout/romm"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@ import (
 
 const (
 	DefaultRomPageSize           = 1000
+	gameSaveBatchSize            = 250
 	MaxConcurrentPlatformFetches = 10
 )
 
@@ -20,7 +22,11 @@ type SyncStats struct {
 	Collectionssynced int
 }
 
-func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Float64) (SyncStats, error) {
+// populateCache fetches the platforms, games and collections changed since the last
+// refresh. Cancelling ctx aborts the requests in flight and rolls back the platform being
+// saved; refresh times are only recorded for what completed, so the next run picks up
+// the rest.
+func (cm *Manager) populateCache(ctx context.Context, platforms []romm.Platform, progress *atomic.Float64) (SyncStats, error) {
 	logger := gaba.GetLogger()
 	stats := SyncStats{Platforms: len(platforms)}
 
@@ -47,7 +53,10 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 
 		// Fetch only updated platforms if we have a previous refresh time
 		if platformsRefresh, err := cm.GetLastRefreshTime(MetaKeyPlatformsRefreshedAt); err == nil {
-			updatedPlatforms, err := client.GetPlatforms(romm.GetPlatformsQuery{UpdatedAfter: platformsRefresh.Format(time.RFC3339)})
+			updatedPlatforms, err := client.GetPlatformsContext(ctx, romm.GetPlatformsQuery{UpdatedAfter: platformsRefresh.Format(time.RFC3339)})
+			if ctx.Err() != nil {
+				return stats, ctx.Err()
+			}
 			if err != nil {
 				logger.Error("Failed to fetch updated platforms", "error", err)
 			} else {
@@ -71,7 +80,10 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 
 		// Save all platforms on first run / empty cache
 		// Fetch all platforms from API, not just mapped ones
-		allPlatforms, err := client.GetPlatforms()
+		allPlatforms, err := client.GetPlatformsContext(ctx)
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
 		if err != nil {
 			logger.Error("Failed to fetch all platforms", "error", err)
 			// Fall back to saving just the mapped platforms
@@ -86,62 +98,33 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 		cm.RecordRefreshTime(MetaKeyPlatformsRefreshedAt)
 	}
 
-	totalExpectedGames := int64(0)
-	for _, p := range platforms {
-		totalExpectedGames += int64(p.ROMCount)
-	}
-	if totalExpectedGames == 0 {
-		totalExpectedGames = int64(len(platforms))
-	}
-
 	// Progress: games 0-85%, collections 85-98%, done 100%
-	gamesFetched := &atomic.Int64{}
-	updateProgress := func(count int) {
-		if progress != nil {
-			fetched := gamesFetched.Add(int64(count))
-			pct := float64(fetched) / float64(totalExpectedGames) * 0.85
-			if pct > 0.85 {
-				pct = 0.85
-			}
-			progress.Store(pct)
+	updateProgress := func(saved, total int) {
+		if progress != nil && total > 0 {
+			progress.Store(min(float64(saved)/float64(total), 1.0) * 0.85)
 		}
 	}
 
 	// BIOS availability - fire and forget
-	go cm.fetchBIOSAvailability(platforms, client)
+	go cm.fetchBIOSAvailability(ctx, platforms, client)
 
-	// Fetch all games in bulk (in goroutine so UI can update)
-	var wg sync.WaitGroup
 	var firstErr error
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		allGames, err := cm.fetchAllGames(client, updatedAfter, updateProgress)
-		if err != nil {
-			logger.Error("Failed to fetch games", "error", err)
-			firstErr = err
-			return
-		}
-		// Group by platform and save
-		gamesByPlatform := make(map[int][]romm.Rom)
-		for _, game := range allGames {
-			gamesByPlatform[game.PlatformID] = append(gamesByPlatform[game.PlatformID], game)
-		}
-		for platformID, games := range gamesByPlatform {
-			if err := cm.SavePlatformGames(platformID, games); err != nil {
-				logger.Error("Failed to save platform games", "platformID", platformID, "error", err)
-				cm.RecordPlatformSyncFailure(platformID)
-				if firstErr == nil {
-					firstErr = err
-				}
-			} else {
-				cm.RecordPlatformSyncSuccess(platformID, len(games))
-			}
+	savedByPlatform, err := cm.fetchAllGames(ctx, client, updatedAfter, updateProgress)
+	if ctx.Err() != nil {
+		logger.Debug("Cache population cancelled")
+		return stats, ctx.Err()
+	}
+	if err != nil {
+		logger.Error("Failed to fetch games", "error", err)
+		firstErr = err
+	} else {
+		for platformID, count := range savedByPlatform {
+			cm.RecordPlatformSyncSuccess(platformID, count)
 		}
-	}()
-
-	wg.Wait()
+	}
+	for _, count := range savedByPlatform {
+		stats.GamesUpdated += count
+	}
 
 	// Record refresh time
 	if firstErr == nil {
@@ -152,7 +135,11 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 	}
 
 	// Collections (85-98%)
-	stats.Collectionssynced = cm.fetchAndCacheCollectionsWithProgress(progress, 0.85, 0.98)
+	stats.Collectionssynced = cm.fetchAndCacheCollectionsWithProgress(ctx, progress, 0.85, 0.98)
+	if ctx.Err() != nil {
+		logger.Debug("Cache population cancelled")
+		return stats, ctx.Err()
+	}
 
 	cm.RecordRefreshTime(MetaKeyCollectionsRefreshedAt)
 
@@ -160,14 +147,13 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 		progress.Store(1.0)
 	}
 
-	stats.GamesUpdated = int(gamesFetched.Load())
 	logger.Debug("Cache population completed", "platforms", stats.Platforms, "games", stats.GamesUpdated)
 	return stats, firstErr
 }
 
 type fetchOpts struct {
+	ctx           context.Context // Cancels the fetch and save; defaults to context.Background
 	client        *romm.Client    // Reusable HTTP client
-	onProgress    func(count int) // Called with count of games fetched (for batch progress)
 	onPctProgress *atomic.Float64 // Set with percentage 0.0-1.0 (for UI progress bars)
 	updatedAfter  string
 }
@@ -178,118 +164,118 @@ func (cm *Manager) fetchPlatformGames(platform romm.Platform, opts *fetchOpts) e
 	}
 
 	logger := gaba.GetLogger()
-	client := opts.client
-	if client == nil {
-		client = romm.NewClientFromHost(cm.host, cm.config.GetApiTimeout())
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	var allGames []romm.Rom
-	offset := 0
-	expectedTotal := 0
-
-	for {
-		q := romm.GetRomsQuery{
-			PlatformID:   platform.ID,
-			Offset:       offset,
-			Limit:        DefaultRomPageSize,
-			UpdatedAfter: opts.updatedAfter,
+	query := romm.GetRomsQuery{PlatformID: platform.ID, UpdatedAfter: opts.updatedAfter}
+	saved, err := cm.streamGames(ctx, opts.client, query, func(saved, total int) {
+		if opts.onPctProgress != nil && total > 0 {
+			opts.onPctProgress.Store(min(float64(saved)/float64(total), 1.0))
 		}
-
-		res, err := client.GetRoms(q)
-		if err != nil {
-			logger.Error("Failed to fetch games",
-				"platform", platform.Name,
-				"offset", offset,
-				"error", err)
-			return err
-		}
-
-		if offset == 0 {
-			expectedTotal = res.Total
-		}
-
-		allGames = append(allGames, res.Items...)
-
-		if opts.onProgress != nil && len(res.Items) > 0 {
-			opts.onProgress(len(res.Items))
-		}
-		if opts.onPctProgress != nil && expectedTotal > 0 {
-			pct := float64(len(allGames)) / float64(expectedTotal)
-			if pct > 1.0 {
-				pct = 1.0
-			}
-			opts.onPctProgress.Store(pct)
-		}
-
-		if len(allGames) >= expectedTotal || len(res.Items) == 0 || len(res.Items) < DefaultRomPageSize {
-			break
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to fetch games", "platform", platform.Name, "error", err)
 		}
-
-		offset += len(res.Items)
+		return err
 	}
 
 	if opts.updatedAfter != "" {
 		logger.Debug("Fetched updated platform games",
 			"platform", platform.Name,
-			"count", len(allGames),
+			"count", saved[platform.ID],
 			"updated_after", opts.updatedAfter)
 	} else {
 		logger.Debug("Cached platform games",
 			"platform", platform.Name,
-			"count", len(allGames))
+			"count", saved[platform.ID])
 	}
 
-	return cm.SavePlatformGames(platform.ID, allGames)
+	return nil
 }
 
-// fetchAllGames fetches all games from the API in bulk (without platform filter)
-func (cm *Manager) fetchAllGames(client *romm.Client, updatedAfter string, onProgress func(count int)) ([]romm.Rom, error) {
-	logger := gaba.GetLogger()
+// fetchAllGames streams all games from the API in bulk (without platform filter) into the
+// cache. Returns how many games were saved for each platform.
+func (cm *Manager) fetchAllGames(ctx context.Context, client *romm.Client, updatedAfter string, onProgress func(saved, total int)) (map[int]int, error) {
+	saved, err := cm.streamGames(ctx, client, romm.GetRomsQuery{UpdatedAfter: updatedAfter}, onProgress)
+	if err == nil {
+		total := 0
+		for _, count := range saved {
+			total += count
+		}
+		gaba.GetLogger().Debug("Fetched all games", "count", total)
+	}
+	return saved, err
+}
 
+// streamGames pages through the games matching query, decoding each page as it arrives and
+// saving the games in batches of gameSaveBatchSize. Only one batch is held in memory, however
+// big the library. onProgress is called after each batch with the games saved so far and
+// the total RomM reported. Returns how many games were saved for each platform, including
+// those saved before an error.
+func (cm *Manager) streamGames(ctx context.Context, client *romm.Client, query romm.GetRomsQuery, onProgress func(saved, total int)) (map[int]int, error) {
 	if client == nil {
 		client = romm.NewClientFromHost(cm.host, cm.config.GetApiTimeout())
 	}
 
-	var allGames []romm.Rom
-	offset := 0
-	expectedTotal := 0
+	savedByPlatform := make(map[int]int)
+	batch := make([]romm.Rom, 0, gameSaveBatchSize)
+	saved, total := 0, 0
 
-	for {
-		q := romm.GetRomsQuery{
-			Offset:       offset,
-			Limit:        DefaultRomPageSize,
-			UpdatedAfter: updatedAfter,
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
 		}
-
-		res, err := client.GetRoms(q)
-		if err != nil {
-			logger.Error("Failed to fetch games", "offset", offset, "error", err)
-			return allGames, err
+		if err := cm.SaveGames(ctx, batch); err != nil {
+			return err
 		}
-
-		if offset == 0 {
-			expectedTotal = res.Total
-			logger.Debug("Fetching all games", "total", expectedTotal)
+		for _, game := range batch {
+			savedByPlatform[game.PlatformID]++
 		}
+		saved += len(batch)
+		batch = batch[:0]
+		if onProgress != nil {
+			onProgress(saved, total)
+		}
+		return nil
+	}
 
-		allGames = append(allGames, res.Items...)
+	query.Limit = DefaultRomPageSize
+	for offset := 0; ; {
+		query.Offset = offset
+		pageCount := 0
 
-		if onProgress != nil && len(res.Items) > 0 {
-			onProgress(len(res.Items))
+		pageTotal, err := client.StreamRomsContext(ctx, query, func(rom romm.Rom) error {
+			pageCount++
+			batch = append(batch, rom)
+			if len(batch) >= gameSaveBatchSize {
+				return flush()
+			}
+			return nil
+		})
+		if offset == 0 {
+			total = pageTotal
 		}
-
-		if len(allGames) >= expectedTotal || len(res.Items) == 0 || len(res.Items) < DefaultRomPageSize {
-			break
+		if err == nil {
+			err = flush()
+		}
+		if ctx.Err() != nil {
+			return savedByPlatform, ctx.Err()
+		}
+		if err != nil {
+			return savedByPlatform, err
 		}
 
-		offset += len(res.Items)
+		offset += pageCount
+		if offset >= total || pageCount < DefaultRomPageSize {
+			return savedByPlatform, nil
+		}
 	}
-
-	logger.Debug("Fetched all games", "count", len(allGames))
-	return allGames, nil
 }
 
-func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64, progressStart, progressEnd float64) int {
+func (cm *Manager) fetchAndCacheCollectionsWithProgress(ctx context.Context, progress *atomic.Float64, progressStart, progressEnd float64) int {
 	logger := gaba.GetLogger()
 
 	showRegular := cm.config.GetShowCollections()
@@ -325,7 +311,7 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			collections, err := client.GetCollections(query)
+			collections, err := client.GetCollectionsContext(ctx, query)
 			if err != nil {
 				logger.Error("Failed to fetch regular collections", "error", err)
 				return
@@ -340,7 +326,7 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			collections, err := client.GetSmartCollections(query)
+			collections, err := client.GetSmartCollectionsContext(ctx, query)
 			if err != nil {
 				logger.Error("Failed to fetch smart collections", "error", err)
 				return
@@ -358,7 +344,7 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			virtualCollections, err := client.GetVirtualCollections()
+			virtualCollections, err := client.GetVirtualCollectionsContext(ctx)
 			if err != nil {
 				logger.Error("Failed to fetch virtual collections", "error", err)
 				return
@@ -373,6 +359,11 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 
 	wg.Wait()
 
+	// A partial set of collections would drop the ones that weren't fetched
+	if ctx.Err() != nil {
+		return 0
+	}
+
 	if progress != nil {
 		progress.Store(progressStart + (progressEnd-progressStart)*0.5)
 	}
@@ -400,7 +391,7 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 	return len(allCollections)
 }
 
-func (cm *Manager) fetchBIOSAvailability(platforms []romm.Platform, client *romm.Client) {
+func (cm *Manager) fetchBIOSAvailability(ctx context.Context, platforms []romm.Platform, client *romm.Client) {
 	logger := gaba.GetLogger()
 
 	if client == nil {
@@ -417,7 +408,14 @@ func (cm *Manager) fetchBIOSAvailability(platforms []romm.Platform, client *romm
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			firmware, err := client.GetFirmware(p.ID)
+			if ctx.Err() != nil {
+				return
+			}
+
+			firmware, err := client.GetFirmwareContext(ctx, p.ID)
+			if ctx.Err() != nil {
+				return
+			}
 			if err != nil {
 				logger.Debug("Failed to fetch BIOS info", "platform", p.Name, "error", err)
 				cm.SetBIOSAvailability(p.ID, false)
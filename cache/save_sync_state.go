@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SaveSyncState records a save as it was right after it was last synced, so the next sync
+// can tell whether the local file, the RomM copy or both have changed since.
+type SaveSyncState struct {
+	RomID           int
+	BaseName        string
+	LocalHash       string
+	LocalModifiedAt time.Time
+	RemoteSaveID    int
+	SyncedAt        time.Time
+}
+
+func (cm *Manager) GetSaveSyncState(romID int, baseName string) (SaveSyncState, bool, error) {
+	if cm == nil || !cm.initialized {
+		return SaveSyncState{}, false, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	state := SaveSyncState{RomID: romID, BaseName: baseName}
+	var localModifiedAt, syncedAt string
+
+	err := cm.db.QueryRow(`
+		SELECT local_hash, local_modified_at, remote_save_id, synced_at
+		FROM save_sync_state WHERE rom_id = ? AND base_name = ?
+	`, romID, baseName).Scan(&state.LocalHash, &localModifiedAt, &state.RemoteSaveID, &syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SaveSyncState{}, false, nil
+	}
+	if err != nil {
+		return SaveSyncState{}, false, newCacheError("get", "save_sync_state", fmt.Sprintf("%d/%s", romID, baseName), err)
+	}
+
+	state.LocalModifiedAt, _ = time.Parse(time.RFC3339, localModifiedAt)
+	state.SyncedAt, _ = time.Parse(time.RFC3339, syncedAt)
+	return state, true, nil
+}
+
+func (cm *Manager) SetSaveSyncState(state SaveSyncState) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		INSERT OR REPLACE INTO save_sync_state (rom_id, base_name, local_hash, local_modified_at, remote_save_id, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, state.RomID, state.BaseName, state.LocalHash, state.LocalModifiedAt.UTC().Format(time.RFC3339), state.RemoteSaveID, nowUTC())
+	if err != nil {
+		return newCacheError("save", "save_sync_state", fmt.Sprintf("%d/%s", state.RomID, state.BaseName), err)
+	}
+
+	return nil
+}
+
+func GetSaveSyncState(romID int, baseName string) (SaveSyncState, bool) {
+	cm := GetCacheManager()
+	if cm == nil {
+		return SaveSyncState{}, false
+	}
+	state, found, err := cm.GetSaveSyncState(romID, baseName)
+	if err != nil {
+		return SaveSyncState{}, false
+	}
+	return state, found
+}
+
+func SetSaveSyncState(state SaveSyncState) error {
+	cm := GetCacheManager()
+	if cm == nil {
+		return ErrNotInitialized
+	}
+	return cm.SetSaveSyncState(state)
+}
@@ -9,7 +9,7 @@ import (
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
-const schemaVersion = 7
+const schemaVersion = 8
 
 // nowUTC returns the current UTC time formatted as RFC3339 for consistent datetime storage
 func nowUTC() string {
@@ -63,18 +63,19 @@ func migrateIfNeeded(db *sql.DB) error {
 
 	logger.Info("Migrating cache schema", "from", currentVersion, "to", schemaVersion)
 
-	if currentVersion < 7 {
-		if err := migrateToV7(db); err != nil {
-			return fmt.Errorf("migration to v7 failed: %w", err)
+	// v7 normalized game metadata and v8 added the columns list screens read
+	if currentVersion < 8 {
+		if err := dropGameTables(db); err != nil {
+			return fmt.Errorf("migration to v%d failed: %w", schemaVersion, err)
 		}
 	}
 
 	return nil
 }
 
-// migrateToV7 drops games and all related tables so they get
-// recreated with the normalized schema by createTables. The next sync refills everything.
-func migrateToV7(db *sql.DB) error {
+// dropGameTables drops games and all related tables so they get
+// recreated with the current schema by createTables. The next sync refills everything.
+func dropGameTables(db *sql.DB) error {
 	logger := gaba.GetLogger()
 
 	tx, err := db.Begin()
@@ -92,7 +93,7 @@ func migrateToV7(db *sql.DB) error {
 		}
 	}
 
-	logger.Info("Dropped games, junction, and lookup tables for v7 migration")
+	logger.Info("Dropped games, junction, and lookup tables for migration", "version", schemaVersion)
 	return tx.Commit()
 }
 
@@ -170,6 +171,7 @@ func createTables(db *sql.DB) error {
 			name TEXT NOT NULL,
 			fs_name TEXT DEFAULT '',
 			fs_name_no_ext TEXT DEFAULT '',
+			platform_display_name TEXT DEFAULT '',
 			crc_hash TEXT DEFAULT '',
 			md5_hash TEXT DEFAULT '',
 			sha1_hash TEXT DEFAULT '',
@@ -182,6 +184,12 @@ func createTables(db *sql.DB) error {
 			missing_from_fs INTEGER DEFAULT 0,
 			has_manual INTEGER DEFAULT 0,
 			has_multiple_files INTEGER DEFAULT 0,
+			has_nested_single_file INTEGER DEFAULT 0,
+			regions TEXT DEFAULT '',
+			file_names TEXT DEFAULT '',
+			path_cover_small TEXT DEFAULT '',
+			path_cover_large TEXT DEFAULT '',
+			url_cover TEXT DEFAULT '',
 			data_json TEXT NOT NULL,
 			updated_at TEXT,
 			cached_at TEXT NOT NULL
@@ -376,6 +384,78 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	// Persistent download queue so downloads survive an app exit
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS download_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rom_id INTEGER NOT NULL,
+			file_id INTEGER NOT NULL DEFAULT 0,
+			platform_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'queued',
+			retry_count INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			rom_json TEXT NOT NULL,
+			platform_json TEXT NOT NULL,
+			queued_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			UNIQUE(rom_id, file_id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_download_queue_status ON download_queue(status)`)
+	if err != nil {
+		return err
+	}
+
+	// What each save looked like when it was last synced, to tell which side changed since
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS save_sync_state (
+			rom_id INTEGER NOT NULL,
+			base_name TEXT NOT NULL,
+			local_hash TEXT NOT NULL,
+			local_modified_at TEXT NOT NULL,
+			remote_save_id INTEGER NOT NULL,
+			synced_at TEXT NOT NULL,
+			PRIMARY KEY (rom_id, base_name)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Collections kept in sync with the device, with the games they held when last synced
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS collection_subscriptions (
+			romm_id INTEGER NOT NULL,
+			type TEXT NOT NULL,
+			name TEXT NOT NULL,
+			delete_removed INTEGER NOT NULL DEFAULT 0,
+			rom_ids_json TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			PRIMARY KEY (romm_id, type)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Games the CFW recorded being played, and the last play reported to RomM
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS play_activity (
+			rom_id INTEGER PRIMARY KEY,
+			last_played TEXT NOT NULL,
+			play_time INTEGER NOT NULL DEFAULT 0,
+			reported_last_played TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(`
 		INSERT OR REPLACE INTO cache_metadata (key, value, updated_at)
 		VALUES ('schema_version', ?, ?)
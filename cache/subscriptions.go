@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"grout/romm"
+	"strconv"
+)
+
+// CollectionSubscription is a collection whose games are kept on the device: games added
+// to it in RomM are downloaded and, with DeleteRemoved, games removed from it are deleted.
+// ROMIDs holds the games it had when it was last synced, to tell what changed since.
+type CollectionSubscription struct {
+	CollectionID  int
+	Smart         bool
+	Name          string
+	DeleteRemoved bool
+	ROMIDs        []int
+}
+
+// Matches reports whether collection is the one subscribed to. Virtual collections can't
+// be subscribed to.
+func (s CollectionSubscription) Matches(collection romm.Collection) bool {
+	return !collection.IsVirtual && collection.ID == s.CollectionID && collection.IsSmart == s.Smart
+}
+
+func subscriptionType(smart bool) string {
+	if smart {
+		return "smart"
+	}
+	return "regular"
+}
+
+func (cm *Manager) GetCollectionSubscriptions() ([]CollectionSubscription, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	rows, err := cm.db.Query(`
+		SELECT romm_id, type, name, delete_removed, rom_ids_json FROM collection_subscriptions ORDER BY name
+	`)
+	if err != nil {
+		return nil, newCacheError("get", "collection_subscriptions", "", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []CollectionSubscription
+	for rows.Next() {
+		sub, err := scanCollectionSubscription(rows)
+		if err != nil {
+			return nil, newCacheError("get", "collection_subscriptions", "", err)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newCacheError("get", "collection_subscriptions", "", err)
+	}
+	return subscriptions, nil
+}
+
+func (cm *Manager) GetCollectionSubscription(collection romm.Collection) (CollectionSubscription, bool, error) {
+	if cm == nil || !cm.initialized {
+		return CollectionSubscription{}, false, ErrNotInitialized
+	}
+	if collection.IsVirtual {
+		return CollectionSubscription{}, false, nil
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	row := cm.db.QueryRow(`
+		SELECT romm_id, type, name, delete_removed, rom_ids_json FROM collection_subscriptions
+		WHERE romm_id = ? AND type = ?
+	`, collection.ID, subscriptionType(collection.IsSmart))
+	sub, err := scanCollectionSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CollectionSubscription{}, false, nil
+	}
+	if err != nil {
+		return CollectionSubscription{}, false, newCacheError("get", "collection_subscriptions", strconv.Itoa(collection.ID), err)
+	}
+	return sub, true, nil
+}
+
+// SaveCollectionSubscription subscribes to a collection, or updates the subscription
+// once its changes have been synced.
+func (cm *Manager) SaveCollectionSubscription(sub CollectionSubscription) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	romIDs := sub.ROMIDs
+	if romIDs == nil {
+		romIDs = []int{}
+	}
+	romIDsJSON, err := json.Marshal(romIDs)
+	if err != nil {
+		return newCacheError("save", "collection_subscriptions", sub.Name, err)
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err = cm.db.Exec(`
+		INSERT OR REPLACE INTO collection_subscriptions (romm_id, type, name, delete_removed, rom_ids_json, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, sub.CollectionID, subscriptionType(sub.Smart), sub.Name, boolToInt(sub.DeleteRemoved), string(romIDsJSON), nowUTC())
+	if err != nil {
+		return newCacheError("save", "collection_subscriptions", sub.Name, err)
+	}
+	return nil
+}
+
+func (cm *Manager) DeleteCollectionSubscription(collection romm.Collection) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	_, err := cm.db.Exec(`
+		DELETE FROM collection_subscriptions WHERE romm_id = ? AND type = ?
+	`, collection.ID, subscriptionType(collection.IsSmart))
+	if err != nil {
+		return newCacheError("delete", "collection_subscriptions", collection.Name, err)
+	}
+	return nil
+}
+
+func scanCollectionSubscription(row interface{ Scan(...any) error }) (CollectionSubscription, error) {
+	var sub CollectionSubscription
+	var collType, romIDsJSON string
+	var deleteRemoved int
+	if err := row.Scan(&sub.CollectionID, &collType, &sub.Name, &deleteRemoved, &romIDsJSON); err != nil {
+		return CollectionSubscription{}, err
+	}
+	sub.Smart = collType == "smart"
+	sub.DeleteRemoved = deleteRemoved != 0
+	if err := json.Unmarshal([]byte(romIDsJSON), &sub.ROMIDs); err != nil {
+		return CollectionSubscription{}, err
+	}
+	return sub, nil
+}
@@ -40,6 +40,11 @@ func GetBaseSavePath() string {
 	return filepath.Join(GetBasePath(), "Saves", "saves")
 }
 
+// GetStateDirectory returns where RetroArch keeps save states for a save folder.
+func GetStateDirectory(saveFolder string) string {
+	return filepath.Join(GetBasePath(), "Saves", "states", saveFolder)
+}
+
 func GetArtDirectory(romDir string) string {
 	return filepath.Join(romDir, "Imgs")
 }
@@ -59,3 +64,8 @@ func GetInputMappingBytes() ([]byte, error) {
 
 	return data, nil
 }
+
+// GetRetroArchDirectory returns RetroArch's home directory, where its playlists and logs are kept.
+func GetRetroArchDirectory() string {
+	return filepath.Join(GetBasePath(), "RetroArch", ".retroarch")
+}
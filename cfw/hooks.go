@@ -0,0 +1,23 @@
+package cfw
+
+import (
+	"grout/cfw/knulli"
+	"grout/cfw/muos"
+	"grout/cfw/rocknix"
+)
+
+// HookScripts returns the scripts that have the current CFW sync a game's save around
+// launch, keyed by where they need to be installed. groutDir is the absolute path of the
+// Grout folder. Nil if the CFW can't run scripts around game launch.
+func HookScripts(groutDir string) map[string]string {
+	switch GetCFW() {
+	case MuOS:
+		return muos.HookScripts(groutDir)
+	case Knulli:
+		return knulli.HookScripts(groutDir)
+	case ROCKNIX:
+		return rocknix.HookScripts(groutDir)
+	default:
+		return nil
+	}
+}
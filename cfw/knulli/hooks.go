@@ -0,0 +1,32 @@
+package knulli
+
+import (
+	"fmt"
+	"grout/internal/stringutil"
+	"path/filepath"
+)
+
+// HookScripts returns the Batocera game script that runs Grout's launch hooks. Knulli runs
+// every script in system/scripts with gameStart or gameStop, the system, emulator, core
+// and ROM path as arguments.
+func HookScripts(groutDir string) map[string]string {
+	script := fmt.Sprintf(`#!/bin/bash
+# Generated by Grout: syncs the save of the game being launched with RomM.
+case "$1" in
+gameStart) EVENT=pre-launch ;;
+gameStop) EVENT=post-exit ;;
+*) exit 0 ;;
+esac
+
+cd %[1]s || exit 0
+export CFW=KNULLI
+export LD_LIBRARY_PATH=%[2]s:$LD_LIBRARY_PATH
+
+./grout hook "$EVENT" "$5"
+exit 0
+`, stringutil.ShellQuote(groutDir), stringutil.ShellQuote(filepath.Join(groutDir, "lib")))
+
+	return map[string]string{
+		filepath.Join(GetBasePath(), "system", "scripts", "grout-save-sync.sh"): script,
+	}
+}
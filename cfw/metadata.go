@@ -1,11 +1,15 @@
 package cfw
 
 import (
+	"errors"
+	"fmt"
 	"grout/cfw/knulli"
 	"grout/cfw/muos"
 	"grout/cfw/rocknix"
 	"grout/internal/emulationstation"
 	"grout/internal/gamelist"
+	"os"
+	"path/filepath"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
@@ -44,3 +48,18 @@ func FillGamesMetadata(entries []gamelist.RomGameEntry) {
 		return
 	}
 }
+
+// RemoveGameMetadata undoes FillGamesMetadata for a deleted game. fileNames are the
+// game's files and folders in romDirectory; gameName is its name without extension.
+func RemoveGameMetadata(romDirectory, platformFSSlug, platformName, gameName string, fileNames []string) error {
+	switch GetCFW() {
+	case Knulli, ROCKNIX:
+		return gamelist.RemoveRomGamesFromGamelist(romDirectory, fileNames)
+	case MuOS:
+		textPath := filepath.Join(muos.GetTextDirectory(platformFSSlug, platformName), fmt.Sprintf("%s.txt", gameName))
+		if err := os.Remove(textPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
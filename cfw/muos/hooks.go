@@ -0,0 +1,29 @@
+package muos
+
+import (
+	"fmt"
+	"grout/internal/stringutil"
+	"path/filepath"
+)
+
+// HookScripts returns scripts that run Grout's launch hooks with the ROM path as their
+// only argument. muOS has no folder of game scripts, so they are kept in the Grout folder
+// to be called from a launch script.
+func HookScripts(groutDir string) map[string]string {
+	return map[string]string{
+		filepath.Join(groutDir, "hooks", "pre-launch.sh"): hookScript(groutDir, "pre-launch"),
+		filepath.Join(groutDir, "hooks", "post-exit.sh"):  hookScript(groutDir, "post-exit"),
+	}
+}
+
+func hookScript(groutDir, event string) string {
+	return fmt.Sprintf(`#!/bin/sh
+# Generated by Grout: syncs the save of a game with RomM. Usage: %[3]s.sh <rom path>
+cd %[1]s || exit 0
+export CFW=MUOS
+export LD_LIBRARY_PATH=%[2]s:$LD_LIBRARY_PATH
+
+./grout hook %[3]s "$1"
+exit 0
+`, stringutil.ShellQuote(groutDir), stringutil.ShellQuote(filepath.Join(groutDir, "lib")), event)
+}
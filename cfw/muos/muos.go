@@ -6,6 +6,7 @@ import (
 	"grout/internal/jsonutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 //go:embed data/*.json
@@ -56,10 +57,20 @@ func GetInfoDirectory() string {
 	return filepath.Join(GetBasePath(), "MUOS", "info")
 }
 
+// GetPlaytimeFile returns where muOS's activity tracker keeps how long each game was played.
+func GetPlaytimeFile() string {
+	return filepath.Join(GetInfoDirectory(), "track", "playtime_data.json")
+}
+
 func GetBaseSavePath() string {
 	return filepath.Join(GetBasePath(), "MUOS", "save")
 }
 
+// GetStateDirectory returns where RetroArch keeps save states for a save folder such as "file/mGBA".
+func GetStateDirectory(saveFolder string) string {
+	return filepath.Join(GetBasePath(), "MUOS", "save", "state", strings.TrimPrefix(saveFolder, "file/"))
+}
+
 func GetArtDirectory(platformFSSlug, platformName string) string {
 	systemName, exists := ArtDirectories[platformFSSlug]
 	if !exists {
@@ -34,6 +34,11 @@ func GetBaseSavePath() string {
 	return filepath.Join(GetBasePath(), "Saves")
 }
 
+// GetStateDirectory returns where save states are kept for a save folder such as "GBA".
+func GetStateDirectory(saveFolder string) string {
+	return filepath.Join(GetBasePath(), ".userdata", "shared", ".minui", saveFolder)
+}
+
 func GetArtDirectory(romDir string) string {
 	return filepath.Join(romDir, ".media")
 }
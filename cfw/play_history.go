@@ -0,0 +1,183 @@
+package cfw
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grout/cfw/allium"
+	"grout/cfw/muos"
+	"grout/cfw/spruce"
+	"grout/cfw/trimui"
+	"grout/internal/gamelist"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PlayRecord is a game the CFW has recorded being played.
+type PlayRecord struct {
+	Path       string // Path of the game, or only its name without extension if that's all the CFW keeps
+	LastPlayed time.Time
+	PlayTime   time.Duration // Zero if the CFW doesn't keep it
+}
+
+// PlayHistory reads what a CFW records about the games played on it.
+type PlayHistory interface {
+	// PlayRecords returns the games played, in no particular order. Sources kept per ROM
+	// directory are only read in romDirectories.
+	PlayRecords(romDirectories []string) ([]PlayRecord, error)
+}
+
+// GetPlayHistory returns where the current CFW keeps its play history. Nil if it keeps none.
+func GetPlayHistory() PlayHistory {
+	switch GetCFW() {
+	case Knulli, ROCKNIX:
+		return gamelistPlayHistory{}
+	case MuOS:
+		return muosPlayHistory{path: muos.GetPlaytimeFile()}
+	case Spruce:
+		return retroArchPlayHistory{logDirectory: retroArchLogDirectory(spruce.GetRetroArchDirectory())}
+	case Trimui:
+		return retroArchPlayHistory{logDirectory: retroArchLogDirectory(trimui.GetRetroArchDirectory())}
+	case Allium:
+		return retroArchPlayHistory{logDirectory: retroArchLogDirectory(allium.GetRetroArchDirectory())}
+	default:
+		return nil
+	}
+}
+
+// gamelistPlayHistory reads the last played time and play time EmulationStation keeps
+// in each ROM directory's gamelist.xml.
+type gamelistPlayHistory struct{}
+
+func (gamelistPlayHistory) PlayRecords(romDirectories []string) ([]PlayRecord, error) {
+	var records []PlayRecord
+	var errs []error
+	for _, dir := range romDirectories {
+		played, err := gamelist.ReadPlayedGames(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read gamelist in %s: %w", dir, err))
+			continue
+		}
+		for _, game := range played {
+			records = append(records, PlayRecord{Path: game.Path, LastPlayed: game.LastPlayed, PlayTime: game.PlayTime})
+		}
+	}
+	return records, errors.Join(errs...)
+}
+
+// muosPlayHistory reads the file muOS's activity tracker keeps, keyed by the path of
+// each game launched.
+type muosPlayHistory struct {
+	path string
+}
+
+type muosPlaytime struct {
+	TotalTime int64 `json:"total_time"` // Seconds
+	StartTime int64 `json:"start_time"` // Unix time the last session started
+}
+
+func (h muosPlayHistory) PlayRecords([]string) ([]PlayRecord, error) {
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var playtimes map[string]muosPlaytime
+	if err := json.Unmarshal(data, &playtimes); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", h.path, err)
+	}
+
+	var records []PlayRecord
+	for path, playtime := range playtimes {
+		if playtime.StartTime == 0 {
+			continue
+		}
+		records = append(records, PlayRecord{
+			Path:       path,
+			LastPlayed: time.Unix(playtime.StartTime, 0),
+			PlayTime:   time.Duration(playtime.TotalTime) * time.Second,
+		})
+	}
+	return records, nil
+}
+
+// retroArchPlayHistory reads RetroArch's runtime logs, written when "Save Runtime Log" is
+// turned on. The logs only name the game, so records have no directory.
+type retroArchPlayHistory struct {
+	logDirectory string
+}
+
+type retroArchRuntimeLog struct {
+	Runtime    string `json:"runtime"`     // H:MM:SS
+	LastPlayed string `json:"last_played"` // Local time
+}
+
+const retroArchTimeFormat = "2006-01-02 15:04:05"
+
+func retroArchLogDirectory(retroArchDirectory string) string {
+	return filepath.Join(retroArchDirectory, "playlists", "logs")
+}
+
+func (h retroArchPlayHistory) PlayRecords([]string) ([]PlayRecord, error) {
+	latest := make(map[string]PlayRecord)
+
+	// Logs are kept per core, so a game played with two cores has two of them. Their run
+	// times add up and the latest play wins.
+	err := filepath.WalkDir(h.logDirectory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".lrtl") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var log retroArchRuntimeLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil
+		}
+		lastPlayed, err := time.ParseInLocation(retroArchTimeFormat, log.LastPlayed, time.Local)
+		if err != nil {
+			return nil
+		}
+
+		name := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		record := latest[name]
+		record.Path = name
+		record.PlayTime += parseRetroArchRuntime(log.Runtime)
+		if lastPlayed.After(record.LastPlayed) {
+			record.LastPlayed = lastPlayed
+		}
+		latest[name] = record
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]PlayRecord, 0, len(latest))
+	for _, record := range latest {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func parseRetroArchRuntime(runtime string) time.Duration {
+	var hours, minutes, seconds int
+	if _, err := fmt.Sscanf(runtime, "%d:%d:%d", &hours, &minutes, &seconds); err != nil {
+		return 0
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+}
@@ -0,0 +1,30 @@
+package rocknix
+
+import (
+	"fmt"
+	"grout/internal/stringutil"
+	"path/filepath"
+)
+
+// HookScripts returns the EmulationStation event scripts that run Grout's launch hooks.
+// EmulationStation runs the scripts in the game-start and game-end folders with the ROM
+// path as the first argument.
+func HookScripts(groutDir string) map[string]string {
+	scriptsDir := filepath.Join(GetBasePath(), ".emulationstation", "scripts")
+	return map[string]string{
+		filepath.Join(scriptsDir, "game-start", "grout-save-sync.sh"): hookScript(groutDir, "pre-launch"),
+		filepath.Join(scriptsDir, "game-end", "grout-save-sync.sh"):   hookScript(groutDir, "post-exit"),
+	}
+}
+
+func hookScript(groutDir, event string) string {
+	return fmt.Sprintf(`#!/bin/bash
+# Generated by Grout: syncs the save of the game being launched with RomM.
+cd %[1]s || exit 0
+export CFW=ROCKNIX
+export LD_LIBRARY_PATH=%[2]s:$LD_LIBRARY_PATH
+
+./grout hook %[3]s "$1"
+exit 0
+`, stringutil.ShellQuote(groutDir), stringutil.ShellQuote(filepath.Join(groutDir, "lib")), event)
+}
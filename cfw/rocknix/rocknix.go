@@ -34,6 +34,11 @@ func GetBaseSavePath() string {
 	return GetRomDirectory()
 }
 
+// GetStateDirectory returns where save states are kept for a save folder. They sit next to the saves.
+func GetStateDirectory(saveFolder string) string {
+	return filepath.Join(GetBaseSavePath(), saveFolder)
+}
+
 func GetArtDirectory(romDir string) string {
 	return filepath.Join(romDir, "images")
 }
@@ -37,3 +37,24 @@ func EmulatorFoldersForFSSlug(fsSlug string) []string {
 	}
 	return saveDirectoriesMap[fsSlug]
 }
+
+// StateDirectory returns the folder save states are kept in for one of the emulator
+// folders returned by EmulatorFoldersForFSSlug. Empty if the CFW isn't supported.
+func StateDirectory(emulatorFolder string) string {
+	switch GetCFW() {
+	case MuOS:
+		return muos.GetStateDirectory(emulatorFolder)
+	case NextUI:
+		return nextui.GetStateDirectory(emulatorFolder)
+	case Knulli:
+		return knulli.GetStateDirectory(emulatorFolder)
+	case Spruce:
+		return spruce.GetStateDirectory(emulatorFolder)
+	case ROCKNIX:
+		return rocknix.GetStateDirectory(emulatorFolder)
+	case Allium:
+		return allium.GetStateDirectory(emulatorFolder)
+	default:
+		return ""
+	}
+}
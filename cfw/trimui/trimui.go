@@ -27,11 +27,11 @@ func GetRomDirectory() string {
 }
 
 func GetBIOSDirectory() string {
-	return filepath.Join(GetBasePath(), "RetroArch", ".retroarch", "system")
+	return filepath.Join(GetRetroArchDirectory(), "system")
 }
 
 func GetBaseSavePath() string {
-	return filepath.Join(GetBasePath(), "RetroArch", ".retroarch", "saves")
+	return filepath.Join(GetRetroArchDirectory(), "saves")
 }
 
 func GetArtDirectory(platformFSSlug, platformName string) string {
@@ -41,3 +41,8 @@ func GetArtDirectory(platformFSSlug, platformName string) string {
 	}
 	return filepath.Join(GetBasePath(), "Imgs", systemName)
 }
+
+// GetRetroArchDirectory returns RetroArch's home directory, where its playlists and logs are kept.
+func GetRetroArchDirectory() string {
+	return filepath.Join(GetBasePath(), "RetroArch", ".retroarch")
+}
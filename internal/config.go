@@ -8,6 +8,10 @@ import (
 	"grout/internal/artutil"
 	"grout/romm"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -17,15 +21,24 @@ import (
 
 var kidModeEnabled atomic.Bool
 
+// hostsMu guards the hosts and which games came from them, which background work changes
+// too. SaveConfig holds it while writing the config out.
+var hostsMu sync.Mutex
+
 type Config struct {
 	Hosts                  []romm.Host                 `json:"hosts,omitempty"`
+	ActiveHost             string                      `json:"active_host,omitempty"`
+	RomHosts               map[string]string           `json:"rom_hosts,omitempty"`
 	DirectoryMappings      map[string]DirectoryMapping `json:"directory_mappings,omitempty"`
 	SaveSyncMode           SaveSyncMode                `json:"save_sync_mode"`
 	SaveDirectoryMappings  map[string]string           `json:"save_directory_mappings,omitempty"`
 	GameSaveOverrides      map[int]string              `json:"game_save_overrides,omitempty"`
+	ExcludedSyncGames      map[int]bool                `json:"excluded_sync_games,omitempty"`
+	ExcludedSyncPlatforms  map[string]bool             `json:"excluded_sync_platforms,omitempty"`
 	DownloadArt            bool                        `json:"download_art,omitempty"`
 	ShowBoxArt             bool                        `json:"show_box_art,omitempty"`
 	UnzipDownloads         bool                        `json:"unzip_downloads,omitempty"`
+	SyncSaveStates         bool                        `json:"sync_save_states"`
 	ShowRegularCollections bool                        `json:"show_collections"`
 	ShowSmartCollections   bool                        `json:"show_smart_collections"`
 	ShowVirtualCollections bool                        `json:"show_virtual_collections"`
@@ -57,6 +70,7 @@ func (c Config) ToLoggable() any {
 
 	return map[string]any{
 		"hosts":                   safeHosts,
+		"active_host":             c.ActiveHost,
 		"directory_mappings":      c.DirectoryMappings,
 		"api_timeout":             c.ApiTimeout,
 		"download_timeout":        c.DownloadTimeout,
@@ -65,7 +79,10 @@ func (c Config) ToLoggable() any {
 		"art_kind":                c.ArtKind,
 		"show_box_art":            c.ShowBoxArt,
 		"save_directory_mappings": c.SaveDirectoryMappings,
+		"sync_save_states":        c.SyncSaveStates,
 		"game_save_overrides":     c.GameSaveOverrides,
+		"excluded_sync_games":     c.ExcludedSyncGames,
+		"excluded_sync_platforms": c.ExcludedSyncPlatforms,
 		"collections":             c.ShowRegularCollections,
 		"smart_collections":       c.ShowSmartCollections,
 		"virtual_collections":     c.ShowVirtualCollections,
@@ -85,8 +102,19 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("parsing config.json: %w", err)
 	}
 
-	if config.ApiTimeout == 0 {
-		config.ApiTimeout = 30 * time.Minute
+	// Save states were synced along with saves before they got their own setting, so
+	// configs from before it keep syncing them. New installs start with it off.
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err == nil {
+		if _, ok := keys["sync_save_states"]; !ok {
+			config.SyncSaveStates = true
+		}
+	}
+
+	// The API timeout used to default to 30 minutes, which the settings never offered, so
+	// configs still set to it get the current default.
+	if config.ApiTimeout == 0 || config.ApiTimeout == 30*time.Minute {
+		config.ApiTimeout = romm.DefaultClientTimeout
 	}
 
 	if config.DownloadTimeout == 0 {
@@ -151,13 +179,64 @@ func SaveConfig(config *Config) error {
 		gaba.GetLogger().Error("Failed to set language", "error", err, "language", config.Language)
 	}
 
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	// The daemon and CLI commands write refreshed tokens to config.json themselves, which
+	// are kept rather than replaced with the older ones loaded here.
+	if disk, err := LoadConfig(); err == nil {
+		for _, h := range disk.Hosts {
+			setHostToken(config.Hosts, h, true)
+		}
+	}
+
+	return writeConfigFile(config)
+}
+
+// updateConfigFile applies update to the config as config.json has it now and writes it
+// back if update changed it. Processes that hold a copy of the config for a long time, like
+// the daemon, use it so changes made elsewhere since they loaded theirs aren't reverted.
+func updateConfigFile(update func(*Config) bool) error {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	disk, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !update(disk) {
+		return nil
+	}
+	return writeConfigFile(disk)
+}
+
+// writeConfigFile writes config to a temporary file that then replaces config.json, so other
+// processes never read it half written.
+func writeConfigFile(config *Config) error {
 	pretty, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		gaba.GetLogger().Error("Failed to marshal config to JSON", "error", err)
 		return err
 	}
 
-	if err := os.WriteFile("config.json", pretty, 0644); err != nil {
+	tmp, err := os.CreateTemp(".", "config-*.json.tmp")
+	if err != nil {
+		gaba.GetLogger().Error("Failed to write config file", "error", err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(pretty)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), "config.json")
+	}
+	if err != nil {
 		gaba.GetLogger().Error("Failed to write config file", "error", err)
 		return err
 	}
@@ -195,6 +274,146 @@ func (c *Config) LoadPlatformsBinding(host romm.Host, timeout ...time.Duration)
 	return nil
 }
 
+// UpdateHostToken stores a refreshed token pair on the matching host. Only the token is
+// written to config.json, the rest of the file is left as it is.
+// This requires the pointer receiver!
+func (c *Config) UpdateHostToken(host romm.Host) error {
+	if !c.updateHostToken(host) {
+		return nil
+	}
+	return updateConfigFile(func(disk *Config) bool {
+		return setHostToken(disk.Hosts, host, false)
+	})
+}
+
+func (c *Config) updateHostToken(host romm.Host) bool {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	return setHostToken(c.Hosts, host, false)
+}
+
+// setHostToken gives the host in hosts that matches host its token pair, if onlyNewer is
+// false or the token was issued later than the one it has.
+func setHostToken(hosts []romm.Host, host romm.Host, onlyNewer bool) bool {
+	for i, h := range hosts {
+		if h.URL() != host.URL() || h.Username != host.Username || host.AccessToken == "" {
+			continue
+		}
+		if onlyNewer && !host.TokenIssuedAt.After(h.TokenIssuedAt) {
+			return false
+		}
+		hosts[i] = h.WithToken(host.Token())
+		return true
+	}
+	return false
+}
+
+// CurrentHost returns the RomM server being browsed. Configs written before more than one
+// server could be added have no active host and use the first.
+func (c *Config) CurrentHost() romm.Host {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	for _, h := range c.Hosts {
+		if h.URL() == c.ActiveHost {
+			return h
+		}
+	}
+	if len(c.Hosts) > 0 {
+		return c.Hosts[0]
+	}
+	return romm.Host{}
+}
+
+// SetActiveHost makes host the server being browsed, adding it if it isn't configured yet
+// and replacing the stored session if it is. Doesn't save the config.
+func (c *Config) SetActiveHost(host romm.Host) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	c.ActiveHost = host.URL()
+	for i, h := range c.Hosts {
+		if h.URL() == host.URL() {
+			c.Hosts[i] = host
+			return
+		}
+	}
+	c.Hosts = append(c.Hosts, host)
+}
+
+// RemoveHost forgets a server. Which games came from it is kept, so their saves don't
+// sync with another server, and do again if it is added back. Doesn't save the config.
+func (c *Config) RemoveHost(host romm.Host) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	c.Hosts = slices.DeleteFunc(c.Hosts, func(h romm.Host) bool { return h.URL() == host.URL() })
+	if c.ActiveHost == host.URL() {
+		c.ActiveHost = ""
+	}
+}
+
+// SetRomHost records the server a game was downloaded from, so its saves only ever sync with
+// that server. Games are told apart by platform and file name without extension, which
+// survives the download being extracted. Doesn't save the config.
+func (c *Config) SetRomHost(fsSlug, fileName string, host romm.Host) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	if c.RomHosts == nil {
+		c.RomHosts = make(map[string]string)
+	}
+	c.RomHosts[romHostKey(fsSlug, fileName)] = host.URL()
+}
+
+// SaveRomHost is SetRomHost for the daemon and CLI commands, and writes only the game's
+// server to config.json.
+func (c *Config) SaveRomHost(fsSlug, fileName string, host romm.Host) error {
+	c.SetRomHost(fsSlug, fileName, host)
+	return updateConfigFile(func(disk *Config) bool {
+		if disk.RomHosts == nil {
+			disk.RomHosts = make(map[string]string)
+		}
+		disk.RomHosts[romHostKey(fsSlug, fileName)] = host.URL()
+		return true
+	})
+}
+
+// SyncsWithHost reports whether a local ROM's saves belong on host: games downloaded from
+// another server only sync with that server, and any other game syncs with whichever
+// server knows it.
+func (c *Config) SyncsWithHost(fsSlug, fileName string, host romm.Host) bool {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	url, ok := c.RomHosts[romHostKey(fsSlug, fileName)]
+	return !ok || url == host.URL()
+}
+
+// SaveHost returns the server a local ROM's saves sync with: the one it was downloaded
+// from, or fallback for any other game. Returns false if it came from a server that is no
+// longer configured.
+func (c *Config) SaveHost(fsSlug, fileName string, fallback romm.Host) (romm.Host, bool) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	url, ok := c.RomHosts[romHostKey(fsSlug, fileName)]
+	if !ok || url == fallback.URL() {
+		return fallback, true
+	}
+	for _, h := range c.Hosts {
+		if h.URL() == url {
+			return h, true
+		}
+	}
+	return romm.Host{}, false
+}
+
+func romHostKey(fsSlug, fileName string) string {
+	return fsSlug + "/" + strings.TrimSuffix(fileName, filepath.Ext(fileName))
+}
+
 func (c Config) GetApiTimeout() time.Duration    { return c.ApiTimeout }
 func (c Config) GetShowCollections() bool        { return c.ShowRegularCollections }
 func (c Config) GetShowSmartCollections() bool   { return c.ShowSmartCollections }
@@ -233,6 +452,12 @@ func (c Config) ResolveRommFSSlug(cfwKey string) string {
 	return cfwKey
 }
 
+// IsSyncExcluded reports whether saves for a game are kept off RomM, either because the game
+// itself or its whole platform was excluded from save sync.
+func (c Config) IsSyncExcluded(fsSlug string, romID int) bool {
+	return c.ExcludedSyncPlatforms[fsSlug] || (romID != 0 && c.ExcludedSyncGames[romID])
+}
+
 func (c Config) GetPlatformRomDirectory(platform romm.Platform) string {
 	rp := platform.FSSlug
 	if mapping, ok := c.DirectoryMappings[platform.FSSlug]; ok && mapping.RelativePath != "" {
@@ -257,6 +482,11 @@ func (c Config) ShowCollections(host romm.Host) bool {
 		return true
 	}
 
+	// Offline there is nothing to browse beyond the cache
+	if IsOffline() {
+		return false
+	}
+
 	// Fallback to network check
 	rc := romm.NewClientFromHost(host, c.ApiTimeout)
 
@@ -0,0 +1,344 @@
+package download
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"grout/internal/fileutil"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+const DefaultTimeout = 120 * time.Minute
+
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Checksum holds the expected properties of a downloaded file. Empty fields are not checked.
+type Checksum struct {
+	Size  int64
+	CRC32 string
+	MD5   string
+	SHA1  string
+}
+
+func (c Checksum) hasHashes() bool {
+	return c.CRC32 != "" || c.MD5 != "" || c.SHA1 != ""
+}
+
+func (c Checksum) IsZero() bool {
+	return c.Size == 0 && !c.hasHashes()
+}
+
+type ChecksumError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+type Request struct {
+	URL                string
+	Location           string
+	DisplayName        string
+	Headers            map[string]string
+	Timeout            time.Duration
+	InsecureSkipVerify bool
+	Checksum           Checksum
+}
+
+type Failure struct {
+	Request Request
+	Err     error
+}
+
+type Result struct {
+	Completed []Request
+	Failed    []Failure
+}
+
+// PartialDir holds in-progress downloads. Unlike fileutil.TempDir it is not cleared
+// on exit, so interrupted downloads can be resumed on the next attempt.
+func PartialDir() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "grout-partial")
+	}
+	return filepath.Join(wd, ".partial")
+}
+
+// PartialPath returns where the in-progress copy of location is staged.
+func PartialPath(location string) string {
+	sum := sha1.Sum([]byte(location))
+	return filepath.Join(PartialDir(), fmt.Sprintf("%x_%s.part", sum[:6], filepath.Base(location)))
+}
+
+// DiscardPartial removes any staged data for location.
+func DiscardPartial(location string) error {
+	err := os.Remove(PartialPath(location))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Fetch downloads req.URL to req.Location. Data is staged in PartialDir and resumed with
+// a Range request if a previous attempt was interrupted. The file is only moved to
+// req.Location once it matches req.Checksum; a mismatch returns a *ChecksumError and
+// discards the staged data so the next attempt starts over.
+func Fetch(ctx context.Context, req Request, progress *atomic.Float64) error {
+	return fetch(ctx, req, progress, true)
+}
+
+func fetch(ctx context.Context, req Request, progress *atomic.Float64, allowRestart bool) error {
+	partial := PartialPath(req.Location)
+	if err := os.MkdirAll(filepath.Dir(partial), 0755); err != nil {
+		return fmt.Errorf("failed to create partial directory: %w", err)
+	}
+
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", req.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	if offset > 0 {
+		httpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := newHTTPClient(req).Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	restart := func() error {
+		resp.Body.Close()
+		if err := os.Remove(partial); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to discard partial download: %w", err)
+		}
+		if !allowRestart {
+			return fmt.Errorf("download could not be resumed")
+		}
+		return fetch(ctx, req, progress, false)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Server ignored the Range header, start over
+		offset = 0
+	case http.StatusPartialContent:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			return restart()
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The staged file is already complete, or larger than the remote file
+		if offset == 0 || req.Checksum.IsZero() {
+			return restart()
+		}
+		if err := Verify(partial, req.Checksum); err != nil {
+			return restart()
+		}
+		return commit(partial, req.Location)
+	default:
+		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	out, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open partial file: %w", err)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+
+	writer := &progressWriter{writer: out, written: offset, total: total, progress: progress}
+	writer.report()
+
+	_, copyErr := io.CopyBuffer(writer, resp.Body, make([]byte, fileutil.DefaultBufferSize))
+	syncErr := out.Sync()
+	closeErr := out.Close()
+
+	if copyErr != nil {
+		return fmt.Errorf("download interrupted: %w", copyErr)
+	}
+	if syncErr != nil {
+		return fmt.Errorf("failed to sync partial file: %w", syncErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close partial file: %w", closeErr)
+	}
+
+	if err := Verify(partial, req.Checksum); err != nil {
+		if errors.Is(err, ErrChecksumMismatch) {
+			os.Remove(partial)
+		}
+		return err
+	}
+
+	return commit(partial, req.Location)
+}
+
+func commit(partial, location string) error {
+	if err := os.MkdirAll(filepath.Dir(location), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := fileutil.MoveFile(partial, location); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+	return nil
+}
+
+// Verify checks the file at path against sum. Hashes are compared case-insensitively.
+func Verify(path string, sum Checksum) error {
+	if sum.Size > 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		if info.Size() != sum.Size {
+			return &ChecksumError{
+				Algorithm: "size",
+				Expected:  strconv.FormatInt(sum.Size, 10),
+				Actual:    strconv.FormatInt(info.Size(), 10),
+			}
+		}
+	}
+
+	if !sum.hasHashes() {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	crcHash := crc32.NewIEEE()
+	md5Hash := md5.New()
+	sha1Hash := sha1.New()
+
+	var writers []io.Writer
+	if sum.CRC32 != "" {
+		writers = append(writers, crcHash)
+	}
+	if sum.MD5 != "" {
+		writers = append(writers, md5Hash)
+	}
+	if sum.SHA1 != "" {
+		writers = append(writers, sha1Hash)
+	}
+
+	if _, err := io.CopyBuffer(io.MultiWriter(writers...), file, make([]byte, fileutil.DefaultBufferSize)); err != nil {
+		return fmt.Errorf("failed to compute hash: %w", err)
+	}
+
+	checks := []struct {
+		algorithm string
+		expected  string
+		hash      hash.Hash
+	}{
+		{"sha1", sum.SHA1, sha1Hash},
+		{"md5", sum.MD5, md5Hash},
+		{"crc32", sum.CRC32, crcHash},
+	}
+
+	for _, c := range checks {
+		if c.expected == "" {
+			continue
+		}
+		actual := fmt.Sprintf("%x", c.hash.Sum(nil))
+		expected := strings.ToLower(c.expected)
+		if c.algorithm == "crc32" {
+			expected = fmt.Sprintf("%08s", expected)
+		}
+		if actual != expected {
+			return &ChecksumError{Algorithm: c.algorithm, Expected: expected, Actual: actual}
+		}
+	}
+
+	return nil
+}
+
+func contentRangeStart(header string) (int64, bool) {
+	// Format: "bytes <start>-<end>/<size>"
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	startStr, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
+func newHTTPClient(req Request) *http.Client {
+	timeout := req.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	client := &http.Client{Timeout: timeout}
+	if req.InsecureSkipVerify {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	return client
+}
+
+type progressWriter struct {
+	writer   io.Writer
+	written  int64
+	total    int64
+	progress *atomic.Float64
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.writer.Write(p)
+	pw.written += int64(n)
+	pw.report()
+	return n, err
+}
+
+func (pw *progressWriter) report() {
+	if pw.progress != nil && pw.total > 0 {
+		pw.progress.Store(float64(pw.written) / float64(pw.total))
+	}
+}
@@ -0,0 +1,97 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newContentServer(content []byte, ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "rom.bin", time.Time{}, bytes.NewReader(content))
+	}))
+}
+
+func TestFetchResumesPartialDownload(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	content := bytes.Repeat([]byte("grout"), 4096)
+	var ranges []string
+	server := newContentServer(content, &ranges)
+	defer server.Close()
+
+	location := filepath.Join(t.TempDir(), "roms", "game.bin")
+	partial := PartialPath(location)
+	if err := os.MkdirAll(filepath.Dir(partial), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(partial, content[:1000], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := Request{
+		URL:      server.URL,
+		Location: location,
+		Checksum: Checksum{
+			Size: int64(len(content)),
+			SHA1: fmt.Sprintf("%X", sha1.Sum(content)),
+		},
+	}
+
+	if err := Fetch(context.Background(), req, nil); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("Range headers = %v, want [bytes=1000-]", ranges)
+	}
+
+	got, err := os.ReadFile(location)
+	if err != nil {
+		t.Fatalf("reading downloaded file: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("downloaded content does not match source")
+	}
+
+	if _, err := os.Stat(partial); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("partial file still present after commit")
+	}
+}
+
+func TestFetchChecksumMismatch(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	content := []byte("not the rom you are looking for")
+	var ranges []string
+	server := newContentServer(content, &ranges)
+	defer server.Close()
+
+	location := filepath.Join(t.TempDir(), "game.bin")
+	req := Request{
+		URL:      server.URL,
+		Location: location,
+		Checksum: Checksum{CRC32: "deadbeef"},
+	}
+
+	err := Fetch(context.Background(), req, nil)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Fetch() error = %v, want ErrChecksumMismatch", err)
+	}
+
+	if _, err := os.Stat(location); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file was committed despite checksum mismatch")
+	}
+	if _, err := os.Stat(PartialPath(location)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("partial file kept after checksum mismatch")
+	}
+}
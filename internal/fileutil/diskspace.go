@@ -0,0 +1,64 @@
+package fileutil
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// DiskSpace describes the filesystem a path lives on.
+type DiskSpace struct {
+	Free  uint64
+	Total uint64
+	// FilesystemID identifies the mount, so paths on the same card can be grouped together
+	FilesystemID string
+}
+
+// GetDiskSpace reports the space on the filesystem holding path. The path doesn't have to
+// exist yet; its nearest existing parent is used, so ROM folders that are only created by
+// the download itself can still be checked.
+func GetDiskSpace(path string) (DiskSpace, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return DiskSpace{}, fmt.Errorf("no existing parent for %s", path)
+		}
+		dir = parent
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return DiskSpace{}, fmt.Errorf("failed to stat filesystem of %s: %w", dir, err)
+	}
+
+	return DiskSpace{
+		Free:         uint64(stat.Bavail) * uint64(stat.Bsize),
+		Total:        uint64(stat.Blocks) * uint64(stat.Bsize),
+		FilesystemID: fmt.Sprint(stat.Fsid),
+	}, nil
+}
+
+// DirSize returns the combined size of all files below path. Unreadable entries are skipped.
+func DirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -74,6 +74,20 @@ func CopyFile(src, dest string) error {
 	return nil
 }
 
+// MoveFile renames src to dest, falling back to copy and delete when they are on different filesystems.
+func MoveFile(src, dest string) error {
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+
+	if err := CopyFile(src, dest); err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	return os.Remove(src)
+}
+
 func DeleteFile(path string) error {
 	return os.Remove(path)
 }
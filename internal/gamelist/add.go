@@ -6,6 +6,7 @@ import (
 	"grout/internal/stringutil"
 	"grout/romm"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -113,3 +114,34 @@ func AddRomGamesToGamelist(entry []RomGameEntry) error {
 
 	return nil
 }
+
+// RemoveRomGamesFromGamelist drops the entries for fileNames from the gamelist.xml in romDirectory.
+// A missing gamelist is not an error.
+func RemoveRomGamesFromGamelist(romDirectory string, fileNames []string) error {
+	gamelistPath := filepath.Join(romDirectory, "gamelist.xml")
+	if !fileutil.FileExists(gamelistPath) {
+		return nil
+	}
+
+	data, err := os.ReadFile(gamelistPath)
+	if err != nil {
+		return err
+	}
+
+	gl := New()
+	if err := gl.Parse(data); err != nil {
+		return err
+	}
+
+	if gl.RemoveGamesByPath(fileNames) == 0 {
+		return nil
+	}
+
+	if err := gl.Save(gamelistPath); err != nil {
+		gaba.GetLogger().Error("Unable to save gamelist.xml file", "error", err)
+		return err
+	}
+	gaba.GetLogger().Debug("Removed games from gamelist.xml file", "path", gamelistPath, "files", fileNames)
+
+	return nil
+}
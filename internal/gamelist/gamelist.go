@@ -1,6 +1,9 @@
 package gamelist
 
 import (
+	"path/filepath"
+	"slices"
+
 	"github.com/beevik/etree"
 )
 
@@ -23,6 +26,8 @@ const (
 	ThumbnailElement   = "thumbnail"
 	LangElement        = "lang"
 	RegionElement      = "region"
+	LastPlayedElement  = "lastplayed"
+	GameTimeElement    = "gametime"
 )
 
 type GameList struct {
@@ -122,3 +127,25 @@ func (gl *GameList) AdddOrUpdateEntry(name string, info map[string]string) {
 	}
 
 }
+
+// RemoveGamesByPath removes every game whose path element points at one of fileNames.
+// Paths are compared by base name so both relative ("./game.zip") and absolute entries match.
+func (gl *GameList) RemoveGamesByPath(fileNames []string) int {
+	root := gl.document.SelectElement(GameListElement)
+	if root == nil {
+		return 0
+	}
+
+	removed := 0
+	for _, game := range root.SelectElements(GameElement) {
+		pathElement := game.FindElement(PathElement)
+		if pathElement == nil {
+			continue
+		}
+		if slices.Contains(fileNames, filepath.Base(pathElement.Text())) {
+			root.RemoveChild(game)
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,68 @@
+package gamelist
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// esTimeFormat is how EmulationStation writes dates, in local time.
+const esTimeFormat = "20060102T150405"
+
+// PlayedGame is a game EmulationStation has recorded being played.
+type PlayedGame struct {
+	Path       string // Absolute path of the game
+	LastPlayed time.Time
+	PlayTime   time.Duration // Zero if the ES build doesn't keep it
+}
+
+// ReadPlayedGames returns the games of the gamelist.xml in romDirectory that have been
+// played. A missing gamelist has none.
+func ReadPlayedGames(romDirectory string) ([]PlayedGame, error) {
+	data, err := os.ReadFile(filepath.Join(romDirectory, "gamelist.xml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	gl := New()
+	if err := gl.Parse(data); err != nil {
+		return nil, err
+	}
+
+	root := gl.document.SelectElement(GameListElement)
+	if root == nil {
+		return nil, nil
+	}
+
+	var played []PlayedGame
+	for _, game := range root.SelectElements(GameElement) {
+		pathElement := game.FindElement(PathElement)
+		lastPlayedElement := game.FindElement(LastPlayedElement)
+		if pathElement == nil || lastPlayedElement == nil {
+			continue
+		}
+
+		lastPlayed, err := time.ParseInLocation(esTimeFormat, lastPlayedElement.Text(), time.Local)
+		if err != nil {
+			continue
+		}
+
+		path := pathElement.Text()
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(romDirectory, path)
+		}
+
+		entry := PlayedGame{Path: path, LastPlayed: lastPlayed}
+		if gameTime := game.FindElement(GameTimeElement); gameTime != nil {
+			if seconds, err := strconv.Atoi(gameTime.Text()); err == nil {
+				entry.PlayTime = time.Duration(seconds) * time.Second
+			}
+		}
+		played = append(played, entry)
+	}
+	return played, nil
+}
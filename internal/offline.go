@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"errors"
+	"grout/romm"
+	"sync/atomic"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// ErrOffline is returned for anything that needs RomM while it can't be reached.
+var ErrOffline = errors.New("RomM is offline")
+
+var offline atomic.Bool
+
+// IsOffline reports whether RomM couldn't be reached the last time it was tried. While
+// offline, browsing runs from the cache and downloads and save syncs wait in their queues.
+func IsOffline() bool {
+	return offline.Load()
+}
+
+func SetOffline(value bool) {
+	if offline.Swap(value) != value {
+		gaba.GetLogger().Info("Offline mode changed", "offline", value)
+	}
+}
+
+// CheckConnectivity asks host for its heartbeat and updates the offline state. Only a
+// server that can't be reached counts as offline; one answering with an error doesn't.
+// Returns whether host is online.
+func CheckConnectivity(host romm.Host) bool {
+	_, err := romm.NewClientFromHost(host, ValidationTimeout).GetHeartbeat()
+	SetOffline(romm.IsUnreachable(err))
+	return !IsOffline()
+}
+
+// NoteRequestError switches to offline mode if err shows RomM couldn't be reached.
+// Returns whether it did.
+func NoteRequestError(err error) bool {
+	if !romm.IsUnreachable(err) {
+		return false
+	}
+	SetOffline(true)
+	return true
+}
@@ -22,6 +22,11 @@ func StripExtension(filename string) string {
 	return strings.TrimSuffix(filename, filepath.Ext(filename))
 }
 
+// ShellQuote quotes s for a POSIX shell, so it is taken literally whatever it contains.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func FormatBytes(bytes int64) string {
 	const unit int64 = 1024
 	if bytes < unit {
@@ -1,13 +1,14 @@
 package romm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 )
 
 func (c *Client) ValidateConnection() error {
-	req, err := http.NewRequest("GET", c.baseURL+endpointHeartbeat, nil)
+	req, err := c.newRequest(context.Background(), "GET", c.baseURL+endpointHeartbeat, nil)
 	if err != nil {
 		return ClassifyError(fmt.Errorf("failed to create validation request: %w", err))
 	}
@@ -77,7 +78,7 @@ func (c *Client) tryAlternateProtocol(originalScheme string, isSuccess func(resp
 		return nil
 	}
 
-	testReq, err := http.NewRequest("GET", switchedURL+endpointHeartbeat, nil)
+	testReq, err := c.newRequest(context.Background(), "GET", switchedURL+endpointHeartbeat, nil)
 	if err != nil {
 		return nil
 	}
@@ -97,61 +98,3 @@ func (c *Client) tryAlternateProtocol(originalScheme string, isSuccess func(resp
 	}
 	return nil
 }
-
-func (c *Client) Login(username, password string) error {
-	req, err := http.NewRequest("POST", c.baseURL+endpointLogin, nil)
-	if err != nil {
-		return ClassifyError(fmt.Errorf("failed to create login request: %w", err))
-	}
-
-	req.SetBasicAuth(username, password)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return ClassifyError(fmt.Errorf("failed to login: %w", err))
-	}
-	defer resp.Body.Close()
-
-	switch {
-	case resp.StatusCode >= 200 && resp.StatusCode < 300:
-		return nil
-	case resp.StatusCode == 401:
-		return &AuthError{
-			StatusCode: 401,
-			Message:    "Invalid username or password",
-			Err:        ErrUnauthorized,
-		}
-	case resp.StatusCode == 403:
-		return &AuthError{
-			StatusCode: 403,
-			Message:    "Access forbidden",
-			Err:        ErrForbidden,
-		}
-	case resp.StatusCode >= 500:
-		return &AuthError{
-			StatusCode: resp.StatusCode,
-			Message:    "Server error",
-			Err:        ErrServerError,
-		}
-	case resp.StatusCode == 405:
-		if switchedURL := switchProtocol(c.baseURL); switchedURL != c.baseURL {
-			testClient := NewClient(switchedURL, WithTimeout(c.httpClient.Timeout))
-			if testReq, testErr := http.NewRequest("POST", switchedURL+endpointLogin, nil); testErr == nil {
-				testReq.SetBasicAuth(username, password)
-				if testResp, testRespErr := testClient.httpClient.Do(testReq); testRespErr == nil {
-					defer testResp.Body.Close()
-					if testResp.StatusCode != 405 && testResp.StatusCode < 500 {
-						return &ProtocolError{
-							RequestedProtocol: req.URL.Scheme,
-							CorrectProtocol:   testReq.URL.Scheme,
-							Err:               ErrWrongProtocol,
-						}
-					}
-				}
-			}
-		}
-		return fmt.Errorf("login failed with status: %d", resp.StatusCode)
-	default:
-		return fmt.Errorf("login failed with status: %d", resp.StatusCode)
-	}
-}
@@ -2,19 +2,43 @@ package romm
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/sonh/qs"
 )
 
 const (
 	DefaultClientTimeout = 30 * time.Second
+
+	dialTimeout         = 10 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+	idleConnTimeout     = 90 * time.Second
+	maxIdleConnsPerHost = 8
+
+	maxAttempts    = 3
+	retryBaseDelay = 500 * time.Millisecond
+)
+
+// Every client shares one of two transports, so connections to a host are pooled and
+// reused however many clients there are.
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[bool]*http.Transport)
+
+	clientsMu sync.Mutex
+	clients   = make(map[string]*Client)
 )
 
 type Client struct {
@@ -22,6 +46,7 @@ type Client struct {
 	httpClient *http.Client
 	username   string
 	password   string
+	session    *session
 }
 
 type queryParam interface {
@@ -45,19 +70,43 @@ func WithBasicAuth(username, password string) ClientOption {
 
 func WithInsecureSkipVerify(skip bool) ClientOption {
 	return func(c *Client) {
-		if skip {
-			c.httpClient.Transport = &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
-		}
+		c.httpClient.Transport = sharedTransport(skip)
+	}
+}
+
+func sharedTransport(insecureSkipVerify bool) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if t, ok := transports[insecureSkipVerify]; ok {
+		return t
 	}
+
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	if insecureSkipVerify {
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	transports[insecureSkipVerify] = t
+	return t
 }
 
 func NewClient(baseURL string, opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
-			Timeout: DefaultClientTimeout,
+			Timeout:   DefaultClientTimeout,
+			Transport: sharedTransport(false),
 		},
 	}
 
@@ -68,18 +117,165 @@ func NewClient(baseURL string, opts ...ClientOption) *Client {
 	return c
 }
 
+// NewClientFromHost returns the client for host, which is shared by every caller asking
+// for the same host and timeout.
 func NewClientFromHost(host Host, timeout ...time.Duration) *Client {
 	opts := []ClientOption{
-		WithBasicAuth(host.Username, host.Password),
 		WithInsecureSkipVerify(host.InsecureSkipVerify),
 	}
 	if len(timeout) > 0 {
 		opts = append(opts, WithTimeout(timeout[0]))
 	}
-	return NewClient(host.URL(), opts...)
+
+	s := sessionFor(host)
+	if host.Password != "" {
+		// Configs that predate token authentication keep working until migrated
+		opts = append(opts, WithBasicAuth(host.Username, host.Password))
+		c := NewClient(host.URL(), opts...)
+		c.session = s
+		return c
+	}
+
+	key := fmt.Sprintf("%s|%t|%v", sessionKey(host), host.InsecureSkipVerify, timeout)
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if c, ok := clients[key]; ok {
+		return c
+	}
+	c := NewClient(host.URL(), opts...)
+	c.session = s
+	clients[key] = c
+	return c
+}
+
+// newRequest creates a request that is cancelled, retries included, once ctx is done.
+func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, method, url, body)
+}
+
+// authorize sets the Authorization header on req and returns the token used, if any.
+func (c *Client) authorize(req *http.Request) Token {
+	if c.session != nil {
+		token := c.session.current()
+		if token.expired() && token.RefreshToken != "" {
+			if refreshed, err := c.session.refresh(c, token); err == nil {
+				token = refreshed
+			}
+		}
+		if token.AccessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+			return token
+		}
+	}
+
+	if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return Token{}
+}
+
+// do sends req with authentication. GETs that time out, hit a server error or are rate
+// limited are tried again with exponential backoff. Heartbeats aren't, since they check
+// whether the server can be reached at all, and neither are timeouts of clients with a
+// longer timeout than the default, which would keep the caller waiting several times that.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || strings.HasSuffix(req.URL.Path, endpointHeartbeat) {
+		return c.doOnce(req)
+	}
+
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doOnce(req.Clone(req.Context()))
+		if attempt == maxAttempts || req.Context().Err() != nil || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		if isTimeout(err) && c.httpClient.Timeout > DefaultClientTimeout {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		gabagool.GetLogger().Debug("Retrying RomM request", "path", req.URL.Path, "attempt", attempt, "delay", delay, "error", err)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
 }
 
-func (c *Client) doRequest(method string, path string, queryParams queryParam, body interface{}, result interface{}) error {
+// shouldRetry reports whether a failed attempt might succeed if tried again.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		if isTimeout(err) {
+			return true
+		}
+		// A pooled connection closed by the server while idle
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// doOnce sends req with authentication. A 401 triggers a single token refresh and
+// retry, as long as the request body can be replayed.
+func (c *Client) doOnce(req *http.Request) (*http.Response, error) {
+	token := c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || token.RefreshToken == "" {
+		return resp, err
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	refreshed, refreshErr := c.session.refresh(c, token)
+	if refreshErr != nil {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	retry.Header.Set("Authorization", "Bearer "+refreshed.AccessToken)
+
+	resp.Body.Close()
+	return c.httpClient.Do(retry)
+}
+
+func (c *Client) doRequest(ctx context.Context, method string, path string, queryParams queryParam, body interface{}, result interface{}) error {
+	return c.doRequestStream(ctx, method, path, queryParams, body, func(r io.Reader) error {
+		if result == nil {
+			return nil
+		}
+		return json.NewDecoder(r).Decode(result)
+	})
+}
+
+// doRequestStream sends a request like doRequest, but hands the response body to decode
+// rather than decoding it all at once, so large responses can be read a piece at a time.
+func (c *Client) doRequestStream(ctx context.Context, method string, path string, queryParams queryParam, body interface{}, decode func(io.Reader) error) error {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -91,7 +287,7 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 
 	u := c.baseURL + path
 
-	req, err := http.NewRequest(method, u, reqBody)
+	req, err := c.newRequest(ctx, method, u, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -107,23 +303,18 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return newAPIError(resp)
 	}
 
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+	if resp.StatusCode != http.StatusNoContent {
+		if err := decode(resp.Body); err != nil {
 			return fmt.Errorf("failed to decode response: %w", err)
 		}
 	}
@@ -131,7 +322,7 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 	return nil
 }
 
-func (c *Client) doRequestRaw(method, path string, body interface{}) ([]byte, error) {
+func (c *Client) doRequestRaw(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -143,7 +334,7 @@ func (c *Client) doRequestRaw(method, path string, body interface{}) ([]byte, er
 
 	fullURL := c.baseURL + strings.ReplaceAll(path, " ", "%20")
 
-	req, err := http.NewRequest(method, fullURL, reqBody)
+	req, err := c.newRequest(ctx, method, fullURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -152,11 +343,7 @@ func (c *Client) doRequestRaw(method, path string, body interface{}) ([]byte, er
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -168,25 +355,21 @@ func (c *Client) doRequestRaw(method, path string, body interface{}) ([]byte, er
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
 	return bodyBytes, nil
 }
 
-func (c *Client) doMultipartRequest(method, path string, queryParams queryParam, body io.Reader, contentType string, result interface{}) error {
+func (c *Client) doMultipartRequest(ctx context.Context, method, path string, queryParams queryParam, body io.Reader, contentType string, result interface{}) error {
 	u := c.baseURL + path
-	req, err := http.NewRequest(method, u, body)
+	req, err := c.newRequest(ctx, method, u, body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", contentType)
 
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
-
 	if queryParams != nil && queryParams.Valid() {
 		values, err := qs.NewEncoder().Values(queryParams)
 		if err == nil {
@@ -194,15 +377,14 @@ func (c *Client) doMultipartRequest(method, path string, queryParams queryParam,
 		}
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return newAPIError(resp)
 	}
 
 	if result != nil && resp.StatusCode != http.StatusNoContent {
@@ -0,0 +1,138 @@
+package romm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "timeout", err: fmt.Errorf("request failed: %w", timeoutError{}), want: true},
+		{name: "eof", err: io.EOF, want: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: true},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, want: false},
+		{name: "ok", status: http.StatusOK, want: false},
+		{name: "not found", status: http.StatusNotFound, want: false},
+		{name: "unauthorized", status: http.StatusUnauthorized, want: false},
+		{name: "too many requests", status: http.StatusTooManyRequests, want: true},
+		{name: "internal server error", status: http.StatusInternalServerError, want: true},
+		{name: "not implemented", status: http.StatusNotImplemented, want: false},
+		{name: "bad gateway", status: http.StatusBadGateway, want: true},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := shouldRetry(resp, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newStatusServer answers with statuses in turn, then with the last one, and counts the
+// requests it gets.
+func newStatusServer(hits *atomic.Int32, statuses ...int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"id": 1, "fs_slug": "gba"}]`)
+	}))
+}
+
+func TestGetRetriesUntilSuccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		wantHits int32
+		wantErr  bool
+	}{
+		{name: "bad gateway", statuses: []int{http.StatusBadGateway, http.StatusOK}, wantHits: 2},
+		{name: "too many requests", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantHits: 2},
+		{name: "gives up", statuses: []int{http.StatusServiceUnavailable}, wantHits: maxAttempts, wantErr: true},
+		{name: "not found", statuses: []int{http.StatusNotFound}, wantHits: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := newStatusServer(&hits, tt.statuses...)
+			defer server.Close()
+
+			platforms, err := NewClient(server.URL).GetPlatforms()
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.statuses[len(tt.statuses)-1] {
+					t.Errorf("GetPlatforms() error = %v, want status %d", err, tt.statuses[len(tt.statuses)-1])
+				}
+			} else if err != nil || len(platforms) != 1 {
+				t.Errorf("GetPlatforms() = %v, %v, want one platform", platforms, err)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server got %d requests, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestPostIsNotRetried(t *testing.T) {
+	var hits atomic.Int32
+	server := newStatusServer(&hits, http.StatusBadGateway, http.StatusOK)
+	defer server.Close()
+
+	err := NewClient(server.URL).doRequest(context.Background(), "POST", endpointCollections, nil, map[string]string{}, nil)
+	if err == nil {
+		t.Error("doRequest() error = nil, want the bad gateway")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	var hits atomic.Int32
+	server := newStatusServer(&hits, http.StatusBadGateway, http.StatusOK)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(retryBaseDelay/10, cancel)
+
+	_, err := NewClient(server.URL).GetPlatformsContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetPlatformsContext() error = %v, want context.Canceled", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
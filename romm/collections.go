@@ -1,7 +1,10 @@
 package romm
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"time"
 )
 
@@ -61,6 +64,11 @@ func (q GetCollectionsQuery) Valid() bool {
 }
 
 func (c *Client) GetCollections(query ...GetCollectionsQuery) ([]Collection, error) {
+	return c.GetCollectionsContext(context.Background(), query...)
+}
+
+// GetCollectionsContext is GetCollections with a context that cancels the request.
+func (c *Client) GetCollectionsContext(ctx context.Context, query ...GetCollectionsQuery) ([]Collection, error) {
 	var collections []Collection
 
 	var q GetCollectionsQuery
@@ -68,18 +76,90 @@ func (c *Client) GetCollections(query ...GetCollectionsQuery) ([]Collection, err
 		q = query[0]
 	}
 
-	err := c.doRequest("GET", endpointCollections, q, nil, &collections)
+	err := c.doRequest(ctx, "GET", endpointCollections, q, nil, &collections)
 	return collections, err
 }
 
 func (c *Client) GetCollection(id int) (Collection, error) {
 	var collection Collection
 	path := fmt.Sprintf(endpointCollectionByID, id)
-	err := c.doRequest("GET", path, nil, nil, &collection)
+	err := c.doRequest(context.Background(), "GET", path, nil, nil, &collection)
 	return collection, err
 }
 
+type createCollectionQuery struct {
+	IsPublic   bool `qs:"is_public"`
+	IsFavorite bool `qs:"is_favorite"`
+}
+
+func (q createCollectionQuery) Valid() bool {
+	return true
+}
+
+type updateCollectionQuery struct {
+	IsPublic bool `qs:"is_public"`
+}
+
+func (q updateCollectionQuery) Valid() bool {
+	return true
+}
+
+// CreateCollection creates an empty collection for the user with collection's name,
+// description and visibility. RomM keeps one favourites collection per user, which is
+// the one created when IsFavorite is set.
+func (c *Client) CreateCollection(collection Collection) (Collection, error) {
+	body, contentType, err := multipartValues(url.Values{
+		"name":        {collection.Name},
+		"description": {collection.Description},
+	})
+	if err != nil {
+		return Collection{}, err
+	}
+
+	var created Collection
+	query := createCollectionQuery{IsPublic: collection.IsPublic, IsFavorite: collection.IsFavorite}
+	err = c.doMultipartRequest(context.Background(), "POST", endpointCollections, query, body, contentType, &created)
+	return created, err
+}
+
+// UpdateCollection replaces the name, description, visibility and games of the collection
+// with collection's ID by those in collection.
+func (c *Client) UpdateCollection(collection Collection) (Collection, error) {
+	romIDs := collection.ROMIDs
+	if romIDs == nil {
+		romIDs = []int{}
+	}
+	ids, err := json.Marshal(romIDs)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	body, contentType, err := multipartValues(url.Values{
+		"name":        {collection.Name},
+		"description": {collection.Description},
+		"rom_ids":     {string(ids)},
+	})
+	if err != nil {
+		return Collection{}, err
+	}
+
+	var updated Collection
+	path := fmt.Sprintf(endpointCollectionByID, collection.ID)
+	err = c.doMultipartRequest(context.Background(), "PUT", path, updateCollectionQuery{IsPublic: collection.IsPublic}, body, contentType, &updated)
+	return updated, err
+}
+
+func (c *Client) DeleteCollection(id int) error {
+	path := fmt.Sprintf(endpointCollectionByID, id)
+	return c.doRequest(context.Background(), "DELETE", path, nil, nil, nil)
+}
+
 func (c *Client) GetSmartCollections(query ...GetCollectionsQuery) ([]Collection, error) {
+	return c.GetSmartCollectionsContext(context.Background(), query...)
+}
+
+// GetSmartCollectionsContext is GetSmartCollections with a context that cancels the request.
+func (c *Client) GetSmartCollectionsContext(ctx context.Context, query ...GetCollectionsQuery) ([]Collection, error) {
 	var collections []Collection
 
 	var q GetCollectionsQuery
@@ -87,13 +167,18 @@ func (c *Client) GetSmartCollections(query ...GetCollectionsQuery) ([]Collection
 		q = query[0]
 	}
 
-	err := c.doRequest("GET", endpointSmartCollections, q, nil, &collections)
+	err := c.doRequest(ctx, "GET", endpointSmartCollections, q, nil, &collections)
 	return collections, err
 }
 
 func (c *Client) GetVirtualCollections() ([]VirtualCollection, error) {
+	return c.GetVirtualCollectionsContext(context.Background())
+}
+
+// GetVirtualCollectionsContext is GetVirtualCollections with a context that cancels the request.
+func (c *Client) GetVirtualCollectionsContext(ctx context.Context) ([]VirtualCollection, error) {
 	var collections []VirtualCollection
-	err := c.doRequest("GET", endpointVirtualCollections, VirtualCollectionsQuery{Type: "collection"}, nil, &collections)
+	err := c.doRequest(ctx, "GET", endpointVirtualCollections, VirtualCollectionsQuery{Type: "collection"}, nil, &collections)
 	return collections, err
 }
 
@@ -0,0 +1,323 @@
+package romm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	mdnsAddress    = "224.0.0.251:5353"
+	mdnsService    = "_http._tcp.local."
+	mdnsUnicastBit = 1 << 15
+
+	discoveryDialTimeout  = 400 * time.Millisecond
+	discoveryProbeTimeout = 2 * time.Second
+	discoveryWorkers      = 128
+)
+
+// discoveryPorts are the ports RomM is commonly served on: behind a reverse proxy, or
+// straight from its container.
+var discoveryPorts = []struct {
+	scheme string
+	port   int
+}{
+	{"http", 80},
+	{"http", 8080},
+	{"https", 443},
+}
+
+// DiscoveredServer is a RomM server found on the local network.
+type DiscoveredServer struct {
+	Host    Host
+	Name    string // The name announced over mDNS, if any
+	Version string
+}
+
+type discoveryCandidate struct {
+	scheme string
+	ip     net.IP
+	port   int
+	name   string
+}
+
+func (c discoveryCandidate) host() Host {
+	host := Host{RootURI: fmt.Sprintf("%s://%s", c.scheme, c.ip)}
+	if (c.scheme == "http" && c.port != 80) || (c.scheme == "https" && c.port != 443) {
+		host.Port = c.port
+	}
+	return host
+}
+
+// DiscoverServers looks for RomM servers on the local network until ctx is done. Hosts
+// announcing _http._tcp over mDNS are tried, along with every address of the device's
+// own subnets (at most a /24 each) on the common RomM ports. Only candidates answering
+// the heartbeat with a RomM version are returned.
+func DiscoverServers(ctx context.Context) []DiscoveredServer {
+	logger := gabagool.GetLogger()
+	candidates := make(chan discoveryCandidate)
+
+	var sources sync.WaitGroup
+	sources.Add(2)
+	go func() {
+		defer sources.Done()
+		if err := browseMDNS(ctx, candidates); err != nil {
+			logger.Debug("Discovery: mDNS browse failed", "error", err)
+		}
+	}()
+	go func() {
+		defer sources.Done()
+		scanSubnets(ctx, candidates)
+	}()
+	go func() {
+		sources.Wait()
+		close(candidates)
+	}()
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	names := make(map[string]string)
+	var found []DiscoveredServer
+
+	var workers sync.WaitGroup
+	for range discoveryWorkers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for c := range candidates {
+				url := c.host().URL()
+
+				mu.Lock()
+				if c.name != "" {
+					names[url] = c.name
+				}
+				probed := seen[url]
+				seen[url] = true
+				mu.Unlock()
+
+				if probed {
+					continue
+				}
+
+				version, ok := probeRomm(ctx, c)
+				if !ok {
+					continue
+				}
+
+				logger.Debug("Discovery: Found RomM", "url", url, "version", version)
+				mu.Lock()
+				found = append(found, DiscoveredServer{Host: c.host(), Version: version})
+				mu.Unlock()
+			}
+		}()
+	}
+	workers.Wait()
+
+	for i := range found {
+		found[i].Name = names[found[i].Host.URL()]
+	}
+	slices.SortFunc(found, func(a, b DiscoveredServer) int {
+		return strings.Compare(a.Host.URL(), b.Host.URL())
+	})
+	return found
+}
+
+// probeRomm checks a candidate is a RomM server, returning its version.
+func probeRomm(ctx context.Context, c discoveryCandidate) (string, bool) {
+	address := net.JoinHostPort(c.ip.String(), fmt.Sprint(c.port))
+	dialer := net.Dialer{Timeout: discoveryDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", false
+	}
+	conn.Close()
+
+	// Self-signed certificates are common at home, and are dealt with at login
+	client := NewClient(c.host().URL(), WithTimeout(discoveryProbeTimeout), WithInsecureSkipVerify(true))
+	heartbeat, err := client.GetHeartbeat()
+	if err != nil || heartbeat.System.Version == "" {
+		return "", false
+	}
+	return heartbeat.System.Version, true
+}
+
+// browseMDNS asks for _http._tcp services over mDNS and sends each one announced as a
+// candidate. The query asks for unicast replies, so no multicast socket is needed.
+func browseMDNS(ctx context.Context, candidates chan<- discoveryCandidate) error {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	query, err := mdnsQuery()
+	if err != nil {
+		return err
+	}
+	dest, err := net.ResolveUDPAddr("udp4", mdnsAddress)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.WriteToUDP(query, dest); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, c := range parseMDNSResponse(buf[:n], src.IP) {
+			select {
+			case candidates <- c:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+func mdnsQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(mdnsService)
+	if err != nil {
+		return nil, err
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{
+		Name:  name,
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET | mdnsUnicastBit,
+	}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// parseMDNSResponse reads the services in an mDNS reply. Each SRV record gives the port,
+// and its target's A record the address, falling back to the address the reply came from.
+func parseMDNSResponse(packet []byte, from net.IP) []discoveryCandidate {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(packet); err != nil {
+		return nil
+	}
+
+	type service struct {
+		name   string
+		target string
+		port   int
+	}
+	var services []service
+	addresses := make(map[string]net.IP)
+
+	// Responders put records in any section
+	records := slices.Concat(msg.Answers, msg.Authorities, msg.Additionals)
+	for _, r := range records {
+		switch body := r.Body.(type) {
+		case *dnsmessage.SRVResource:
+			instance, _, _ := strings.Cut(r.Header.Name.String(), "._http._tcp")
+			services = append(services, service{
+				name:   instance,
+				target: strings.ToLower(body.Target.String()),
+				port:   int(body.Port),
+			})
+		case *dnsmessage.AResource:
+			addresses[strings.ToLower(r.Header.Name.String())] = net.IP(body.A[:])
+		}
+	}
+
+	var candidates []discoveryCandidate
+	for _, s := range services {
+		ip := addresses[s.target]
+		if ip == nil {
+			ip = from
+		}
+		scheme := "http"
+		if s.port == 443 {
+			scheme = "https"
+		}
+		candidates = append(candidates, discoveryCandidate{scheme: scheme, ip: ip, port: s.port, name: s.name})
+	}
+	return candidates
+}
+
+// scanSubnets sends every address of the device's own IPv4 subnets as a candidate on each
+// of the common RomM ports. Larger subnets are narrowed to the /24 around the device.
+func scanSubnets(ctx context.Context, candidates chan<- discoveryCandidate) {
+	for _, subnet := range localSubnets() {
+		base := subnet.IP.To4()
+		for i := 1; i < 255; i++ {
+			ip := net.IPv4(base[0], base[1], base[2], byte(i)).To4()
+			if ip.Equal(subnet.self) {
+				continue
+			}
+			for _, p := range discoveryPorts {
+				select {
+				case candidates <- discoveryCandidate{scheme: p.scheme, ip: ip, port: p.port}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+type localSubnet struct {
+	net.IPNet
+	self net.IP
+}
+
+func localSubnets() []localSubnet {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var subnets []localSubnet
+	seen := make(map[string]bool)
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+
+			ip := ipNet.IP.To4()
+			mask := net.CIDRMask(24, 32)
+			subnet := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+			if seen[subnet.String()] {
+				continue
+			}
+			seen[subnet.String()] = true
+			subnets = append(subnets, localSubnet{IPNet: subnet, self: ip})
+		}
+	}
+	return subnets
+}
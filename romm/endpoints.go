@@ -2,9 +2,12 @@ package romm
 
 const (
 	endpointHeartbeat = "/api/heartbeat"
-	endpointLogin     = "/api/login"
+	endpointToken     = "/api/token"
+	endpointAuthMeta  = "/.well-known/oauth-authorization-server"
 	endpointConfig    = "/api/config"
 
+	endpointCurrentUser = "/api/users/me"
+
 	endpointPlatforms    = "/api/platforms"
 	endpointPlatformByID = "/api/platforms/%d"
 
@@ -12,6 +15,7 @@ const (
 	endpointRomByID      = "/api/roms/%d"
 	endpointRomsDownload = "/api/roms/download"
 	endpointRomsByHash   = "/api/roms/by-hash"
+	endpointRomProps     = "/api/roms/%d/props"
 
 	endpointCollections        = "/api/collections"
 	endpointCollectionByID     = "/api/collections/%d"
@@ -20,5 +24,7 @@ const (
 
 	endpointFirmware = "/api/firmware"
 
-	endpointSaves = "/api/saves"
+	endpointSaves       = "/api/saves"
+	endpointStates      = "/api/states"
+	endpointScreenshots = "/api/screenshots"
 )
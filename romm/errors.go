@@ -1,9 +1,12 @@
 package romm
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"syscall"
@@ -19,8 +22,42 @@ var (
 	ErrUnauthorized      = errors.New("invalid credentials")
 	ErrForbidden         = errors.New("access forbidden")
 	ErrServerError       = errors.New("server error")
+	ErrNotFound          = errors.New("not found")
 )
 
+// maxErrorBodySize caps how much of an error response is kept for APIError.
+const maxErrorBodySize = 4096
+
+// APIError is a response from RomM with a status outside 2xx. It unwraps to the sentinel
+// error matching its status, so errors.Is(err, ErrNotFound) and the like work.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: status %d, body: %s", e.StatusCode, e.Body)
+}
+
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode >= 500:
+		return ErrServerError
+	}
+	return nil
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+}
+
 type AuthError struct {
 	StatusCode int
 	Message    string
@@ -100,3 +137,24 @@ func ClassifyError(err error) error {
 
 	return err
 }
+
+// IsUnreachable reports whether err means RomM couldn't be reached at all, rather than
+// the server answering with an error.
+func IsUnreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	classified := ClassifyError(err)
+	switch {
+	case errors.Is(classified, ErrInvalidHostname),
+		errors.Is(classified, ErrConnectionRefused),
+		errors.Is(classified, ErrTimeout):
+		return true
+	case errors.Is(classified, ErrWrongProtocol):
+		return false
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -1,5 +1,7 @@
 package romm
 
+import "context"
+
 type HeartbeatResponse struct {
 	System struct {
 		Version string `json:"VERSION"`
@@ -8,6 +10,6 @@ type HeartbeatResponse struct {
 
 func (c *Client) GetHeartbeat() (HeartbeatResponse, error) {
 	var heartbeat HeartbeatResponse
-	err := c.doRequest("GET", endpointHeartbeat, nil, nil, &heartbeat)
+	err := c.doRequest(context.Background(), "GET", endpointHeartbeat, nil, nil, &heartbeat)
 	return heartbeat, err
 }
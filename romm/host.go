@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Host struct {
@@ -11,9 +12,16 @@ type Host struct {
 	RootURI     string `json:"root_uri,omitempty"`
 	Port        int    `json:"port,omitempty"`
 
-	Username           string `json:"username,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password is only populated by the login screen and by configs written before
+	// token authentication. It is cleared once exchanged for a token pair.
 	Password           string `json:"password,omitempty"`
 	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+
+	AccessToken    string    `json:"access_token,omitempty"`
+	RefreshToken   string    `json:"refresh_token,omitempty"`
+	TokenExpiresAt time.Time `json:"token_expires_at,omitempty"`
+	TokenIssuedAt  time.Time `json:"token_issued_at,omitempty"`
 }
 
 func (h Host) ToLoggable() map[string]any {
@@ -24,6 +32,10 @@ func (h Host) ToLoggable() map[string]any {
 		"username":             h.Username,
 		"password":             strings.Repeat("*", len(h.Password)),
 		"insecure_skip_verify": h.InsecureSkipVerify,
+		"has_access_token":     h.AccessToken != "",
+		"has_refresh_token":    h.RefreshToken != "",
+		"token_expires_at":     h.TokenExpiresAt,
+		"token_issued_at":      h.TokenIssuedAt,
 	}
 
 	return temp
@@ -36,7 +48,41 @@ func (h Host) URL() string {
 	return h.RootURI
 }
 
-func (h Host) BasicAuthHeader() string {
-	auth := h.Username + ":" + h.Password
-	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+func (h Host) Token() Token {
+	return Token{
+		AccessToken:  h.AccessToken,
+		RefreshToken: h.RefreshToken,
+		ExpiresAt:    h.TokenExpiresAt,
+		IssuedAt:     h.TokenIssuedAt,
+	}
+}
+
+// WithToken returns a copy of the host carrying the given token pair and no password.
+func (h Host) WithToken(token Token) Host {
+	h.AccessToken = token.AccessToken
+	h.RefreshToken = token.RefreshToken
+	h.TokenExpiresAt = token.ExpiresAt
+	h.TokenIssuedAt = token.IssuedAt
+	h.Password = ""
+	return h
+}
+
+// AuthHeader returns an Authorization header value for requests made outside of
+// Client, such as downloads. The access token is refreshed first if it has expired.
+// The header is reused until the token is about to expire, so calling this for every
+// request doesn't reach out to RomM.
+func (h Host) AuthHeader() string {
+	if header, ok := sessionFor(h).authHeader(); ok {
+		return header
+	}
+
+	token, err := NewClientFromHost(h, DefaultClientTimeout).EnsureToken()
+	if err == nil && token.AccessToken != "" {
+		return "Bearer " + token.AccessToken
+	}
+	if h.Password != "" {
+		auth := h.Username + ":" + h.Password
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+	}
+	return ""
 }
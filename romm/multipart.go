@@ -0,0 +1,76 @@
+package romm
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type multipartFile struct {
+	Field string
+	Path  string
+}
+
+// multipartFiles builds a multipart form holding each file under its field name. Files
+// with an empty path are left out so optional attachments can be passed unconditionally.
+func multipartFiles(files ...multipartFile) (*bytes.Buffer, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, f := range files {
+		if f.Path == "" {
+			continue
+		}
+		if err := writeMultipartFile(writer, f); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, writer.FormDataContentType(), nil
+}
+
+func writeMultipartFile(writer *multipart.Writer, f multipartFile) error {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile(f.Field, filepath.Base(f.Path))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, file)
+	return err
+}
+
+// multipartValues builds a multipart form holding each value under its field name, for
+// endpoints that read form fields instead of a JSON body.
+func multipartValues(values url.Values) (*bytes.Buffer, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, field := range slices.Sorted(maps.Keys(values)) {
+		for _, value := range values[field] {
+			if err := writer.WriteField(field, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, writer.FormDataContentType(), nil
+}
@@ -0,0 +1,251 @@
+package romm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	pairingClientID        = "grout"
+	pairingGrantType       = "urn:ietf:params:oauth:grant-type:device_code"
+	defaultPairingInterval = 5 * time.Second
+)
+
+var (
+	ErrPairingUnsupported = errors.New("server does not support device pairing")
+	ErrPairingExpired     = errors.New("pairing code expired")
+	ErrPairingDenied      = errors.New("pairing was denied")
+)
+
+// Pairing is a pending device pairing. The user opens VerificationURI on another device,
+// signs in to RomM and confirms UserCode, after which PollPairing receives a token pair.
+type Pairing struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURI string
+	ExpiresAt       time.Time
+	Interval        time.Duration
+
+	tokenPath string
+}
+
+// authServerMetadata is the part of the OAuth authorization server metadata (RFC 8414)
+// that advertises device pairing.
+type authServerMetadata struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+type pairingResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type oauthErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// pairingPaths returns the paths to start a pairing at and to collect its token from, as
+// advertised in the server's authorization server metadata. Only the paths are used, as a
+// server behind a proxy may advertise an address the device can't reach. Returns
+// ErrPairingUnsupported if the server doesn't advertise device pairing.
+func (c *Client) pairingPaths() (string, string, error) {
+	var meta authServerMetadata
+	if err := c.doRequest(context.Background(), "GET", endpointAuthMeta, nil, nil, &meta); err != nil {
+		// Servers without the metadata answer with an error, or with a page of their web app
+		var apiErr *APIError
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 || errors.As(err, &syntaxErr) {
+			return "", "", ErrPairingUnsupported
+		}
+		return "", "", err
+	}
+
+	devicePath := endpointPath(meta.DeviceAuthorizationEndpoint)
+	if devicePath == "" {
+		return "", "", ErrPairingUnsupported
+	}
+	tokenPath := endpointPath(meta.TokenEndpoint)
+	if tokenPath == "" {
+		tokenPath = endpointToken
+	}
+	return devicePath, tokenPath, nil
+}
+
+func endpointPath(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Path == "" {
+		return ""
+	}
+	return u.RequestURI()
+}
+
+// SupportsPairing reports whether the server advertises device pairing. Servers that
+// don't can only be logged in to with a username and password.
+func (c *Client) SupportsPairing() bool {
+	_, _, err := c.pairingPaths()
+	return err == nil
+}
+
+// StartPairing asks RomM for a device code, following the OAuth device authorization
+// grant so Grout can log in without the password being typed on the device. Returns
+// ErrPairingUnsupported if the server doesn't advertise device pairing.
+func (c *Client) StartPairing() (Pairing, error) {
+	devicePath, tokenPath, err := c.pairingPaths()
+	if err != nil {
+		return Pairing{}, err
+	}
+
+	form := url.Values{
+		"client_id": {pairingClientID},
+		"scope":     {strings.Join(tokenScopes, " ")},
+	}
+	req, err := c.newRequest(context.Background(), "POST", c.baseURL+devicePath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Pairing{}, ClassifyError(fmt.Errorf("failed to create pairing request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Pairing{}, ClassifyError(fmt.Errorf("failed to request pairing: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == 404, resp.StatusCode == 405:
+		return Pairing{}, ErrPairingUnsupported
+	case resp.StatusCode >= 500:
+		return Pairing{}, &AuthError{
+			StatusCode: resp.StatusCode,
+			Message:    "Server error",
+			Err:        ErrServerError,
+		}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return Pairing{}, newAPIError(resp)
+	}
+
+	var res pairingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return Pairing{}, fmt.Errorf("failed to decode pairing response: %w", err)
+	}
+	if res.DeviceCode == "" || res.UserCode == "" {
+		return Pairing{}, fmt.Errorf("pairing response did not include a device code")
+	}
+
+	pairing := Pairing{
+		DeviceCode:      res.DeviceCode,
+		UserCode:        res.UserCode,
+		VerificationURI: res.VerificationURIComplete,
+		ExpiresAt:       time.Now().Add(time.Duration(res.ExpiresIn) * time.Second),
+		Interval:        time.Duration(res.Interval) * time.Second,
+		tokenPath:       tokenPath,
+	}
+	if pairing.VerificationURI == "" {
+		pairing.VerificationURI = res.VerificationURI
+	}
+	if pairing.Interval <= 0 {
+		pairing.Interval = defaultPairingInterval
+	}
+	return pairing, nil
+}
+
+// PollPairing waits for the user to confirm the pairing and returns the token pair it
+// grants. Returns ErrPairingExpired or ErrPairingDenied if it won't be confirmed, and
+// ctx's error if ctx is done first.
+func (c *Client) PollPairing(ctx context.Context, pairing Pairing) (Token, error) {
+	interval := pairing.Interval
+
+	for {
+		select {
+		case <-ctx.Done():
+			return Token{}, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if !pairing.ExpiresAt.IsZero() && time.Now().After(pairing.ExpiresAt) {
+			return Token{}, ErrPairingExpired
+		}
+
+		token, pending, err := c.requestPairedToken(ctx, pairing)
+		switch {
+		case err != nil:
+			return Token{}, err
+		case pending == "slow_down":
+			interval += defaultPairingInterval
+		case pending == "":
+			if c.session != nil {
+				c.session.mu.Lock()
+				c.session.token = token
+				c.session.host = c.session.host.WithToken(token)
+				c.session.mu.Unlock()
+			}
+			return token, nil
+		}
+	}
+}
+
+// requestPairedToken asks for the token of a pairing once. A pairing that is still
+// waiting on the user returns the OAuth error code saying so instead of an error.
+func (c *Client) requestPairedToken(ctx context.Context, pairing Pairing) (Token, string, error) {
+	form := url.Values{
+		"grant_type":  {pairingGrantType},
+		"device_code": {pairing.DeviceCode},
+		"client_id":   {pairingClientID},
+	}
+	tokenPath := pairing.tokenPath
+	if tokenPath == "" {
+		tokenPath = endpointToken
+	}
+	req, err := c.newRequest(ctx, "POST", c.baseURL+tokenPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, "", ClassifyError(fmt.Errorf("failed to create token request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Token{}, "", ClassifyError(fmt.Errorf("failed to request token: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		var res tokenResponse
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			return Token{}, "", fmt.Errorf("failed to decode token response: %w", err)
+		}
+		token, err := res.token()
+		return token, "", err
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	var oauthErr oauthErrorResponse
+	_ = json.Unmarshal(body, &oauthErr)
+	switch oauthErr.Error {
+	case "authorization_pending", "slow_down":
+		return Token{}, oauthErr.Error, nil
+	case "expired_token":
+		return Token{}, "", ErrPairingExpired
+	case "access_denied":
+		return Token{}, "", ErrPairingDenied
+	}
+
+	if resp.StatusCode >= 500 {
+		return Token{}, "", &AuthError{
+			StatusCode: resp.StatusCode,
+			Message:    "Server error",
+			Err:        ErrServerError,
+		}
+	}
+	return Token{}, "", &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+}
@@ -1,6 +1,7 @@
 package romm
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -42,6 +43,11 @@ func (q GetPlatformsQuery) Valid() bool {
 }
 
 func (c *Client) GetPlatforms(query ...GetPlatformsQuery) ([]Platform, error) {
+	return c.GetPlatformsContext(context.Background(), query...)
+}
+
+// GetPlatformsContext is GetPlatforms with a context that cancels the request.
+func (c *Client) GetPlatformsContext(ctx context.Context, query ...GetPlatformsQuery) ([]Platform, error) {
 	var platforms []Platform
 
 	var q GetPlatformsQuery
@@ -49,14 +55,14 @@ func (c *Client) GetPlatforms(query ...GetPlatformsQuery) ([]Platform, error) {
 		q = query[0]
 	}
 
-	err := c.doRequest("GET", endpointPlatforms, q, nil, &platforms)
+	err := c.doRequest(ctx, "GET", endpointPlatforms, q, nil, &platforms)
 	return platforms, err
 }
 
 func (c *Client) GetPlatform(id int) (Platform, error) {
 	var platform Platform
 	path := fmt.Sprintf(endpointPlatformByID, id)
-	err := c.doRequest("GET", path, nil, nil, &platform)
+	err := c.doRequest(context.Background(), "GET", path, nil, nil, &platform)
 	return platform, err
 }
 
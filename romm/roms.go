@@ -1,9 +1,12 @@
 package romm
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"grout/internal/artutil"
 	"grout/internal/fileutil"
+	"io"
 	"net/url"
 	"path/filepath"
 	"slices"
@@ -78,15 +81,6 @@ type Rom struct {
 	ScreenScraperMetadata ScreenScrapper `json:"ss_metadata,omitempty"`
 }
 
-type Screenshot struct {
-	ID       int    `json:"id,omitempty"`
-	RomID    int    `json:"rom_id,omitempty"`
-	FileName string `json:"file_name,omitempty"`
-	FilePath string `json:"file_path,omitempty"`
-	URLPath  string `json:"url_path,omitempty"`
-	Order    int    `json:"order,omitempty"`
-}
-
 type RomMetadata struct {
 	RomID            int      `json:"rom_id,omitempty"`
 	Genres           []string `json:"genres,omitempty"`
@@ -152,23 +146,126 @@ func (q DownloadRomsQuery) Valid() bool {
 
 func (c *Client) GetRoms(query GetRomsQuery) (PaginatedRoms, error) {
 	var result PaginatedRoms
-	err := c.doRequest("GET", endpointRoms, query, nil, &result)
+	err := c.doRequest(context.Background(), "GET", endpointRoms, query, nil, &result)
 	return result, err
 }
+
+// StreamRoms requests a page of ROMs like GetRoms, but decodes the ROMs one at a time and
+// hands each to fn, so a page is never held in memory. Stops at the first error fn
+// returns, which is returned as is. Returns the total number of ROMs matching query.
+func (c *Client) StreamRoms(query GetRomsQuery, fn func(Rom) error) (int, error) {
+	return c.StreamRomsContext(context.Background(), query, fn)
+}
+
+// StreamRomsContext is StreamRoms with a context that cancels the request.
+func (c *Client) StreamRomsContext(ctx context.Context, query GetRomsQuery, fn func(Rom) error) (int, error) {
+	var total int
+	var fnErr error
+	err := c.doRequestStream(ctx, "GET", endpointRoms, query, nil, func(r io.Reader) error {
+		return decodeRomPage(r, &total, func(rom Rom) error {
+			fnErr = fn(rom)
+			return fnErr
+		})
+	})
+	if fnErr != nil {
+		return total, fnErr
+	}
+	return total, err
+}
+
+// decodeRomPage reads a paginated ROM response token by token, calling fn for each item.
+func decodeRomPage(r io.Reader, total *int, fn func(Rom) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case "items":
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				var rom Rom
+				if err := dec.Decode(&rom); err != nil {
+					return err
+				}
+				if err := fn(rom); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		case "total":
+			if err := dec.Decode(total); err != nil {
+				return err
+			}
+		default:
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return err
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != want {
+		return fmt.Errorf("expected %v in response, got %v", want, token)
+	}
+	return nil
+}
+
 func (c *Client) GetRomByHash(query GetRomByHashQuery) (Rom, error) {
+	return c.GetRomByHashContext(context.Background(), query)
+}
+
+// GetRomByHashContext is GetRomByHash with a context that cancels the request.
+func (c *Client) GetRomByHashContext(ctx context.Context, query GetRomByHashQuery) (Rom, error) {
 	var rom Rom
-	err := c.doRequest("GET", endpointRomsByHash, query, nil, &rom)
+	err := c.doRequest(ctx, "GET", endpointRomsByHash, query, nil, &rom)
 	return rom, err
 }
 func (c *Client) GetRom(id int) (Rom, error) {
 	var rom Rom
 	path := fmt.Sprintf(endpointRomByID, id)
-	err := c.doRequest("GET", path, nil, nil, &rom)
+	err := c.doRequest(context.Background(), "GET", path, nil, nil, &rom)
 	return rom, err
 }
+
+type romPropsRequest struct {
+	Data             map[string]any `json:"data"`
+	UpdateLastPlayed bool           `json:"update_last_played"`
+}
+
+// UpdateLastPlayed marks the ROM as played by the signed-in user. RomM stamps the time
+// itself when the request arrives, and keeps no play time.
+func (c *Client) UpdateLastPlayed(romID int) error {
+	return c.UpdateLastPlayedContext(context.Background(), romID)
+}
+
+// UpdateLastPlayedContext is UpdateLastPlayed with a context that cancels the request.
+func (c *Client) UpdateLastPlayedContext(ctx context.Context, romID int) error {
+	path := fmt.Sprintf(endpointRomProps, romID)
+	return c.doRequest(ctx, "PUT", path, nil, romPropsRequest{Data: map[string]any{}, UpdateLastPlayed: true}, nil)
+}
+
 func (c *Client) DownloadRoms(romIDs []int) ([]byte, error) {
 	if len(romIDs) == 0 {
-		return c.doRequestRaw("GET", endpointRomsDownload, nil)
+		return c.doRequestRaw(context.Background(), "GET", endpointRomsDownload, nil)
 	}
 
 	ids := ""
@@ -186,7 +283,7 @@ func (c *Client) DownloadRoms(romIDs []int) ([]byte, error) {
 	}
 
 	path := endpointRomsDownload + "?" + values.Encode()
-	return c.doRequestRaw("GET", path, nil)
+	return c.doRequestRaw(context.Background(), "GET", path, nil)
 }
 
 func (r Rom) GetGamePage(host Host) string {
@@ -1,46 +1,27 @@
 package romm
 
 import (
-	"bytes"
-	"io"
-	"mime/multipart"
-	"os"
-	"path/filepath"
+	"context"
 	"time"
 )
 
 type Save struct {
-	ID             int       `json:"id"`
-	RomID          int       `json:"rom_id"`
-	UserID         int       `json:"user_id"`
-	FileName       string    `json:"file_name"`
-	FileNameNoTags string    `json:"file_name_no_tags"`
-	FileNameNoExt  string    `json:"file_name_no_ext"`
-	FileExtension  string    `json:"file_extension"`
-	FilePath       string    `json:"file_path"`
-	FileSizeBytes  int64     `json:"file_size_bytes"`
-	FullPath       string    `json:"full_path"`
-	DownloadPath   string    `json:"download_path"`
-	MissingFromFs  bool      `json:"missing_from_fs"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	Emulator       string    `json:"emulator"`
-	Screenshot     struct {
-		ID             int       `json:"id"`
-		RomID          int       `json:"rom_id"`
-		UserID         int       `json:"user_id"`
-		FileName       string    `json:"file_name"`
-		FileNameNoTags string    `json:"file_name_no_tags"`
-		FileNameNoExt  string    `json:"file_name_no_ext"`
-		FileExtension  string    `json:"file_extension"`
-		FilePath       string    `json:"file_path"`
-		FileSizeBytes  int64     `json:"file_size_bytes"`
-		FullPath       string    `json:"full_path"`
-		DownloadPath   string    `json:"download_path"`
-		MissingFromFs  bool      `json:"missing_from_fs"`
-		CreatedAt      time.Time `json:"created_at"`
-		UpdatedAt      time.Time `json:"updated_at"`
-	} `json:"Screenshot"`
+	ID             int         `json:"id"`
+	RomID          int         `json:"rom_id"`
+	UserID         int         `json:"user_id"`
+	FileName       string      `json:"file_name"`
+	FileNameNoTags string      `json:"file_name_no_tags"`
+	FileNameNoExt  string      `json:"file_name_no_ext"`
+	FileExtension  string      `json:"file_extension"`
+	FilePath       string      `json:"file_path"`
+	FileSizeBytes  int64       `json:"file_size_bytes"`
+	FullPath       string      `json:"full_path"`
+	DownloadPath   string      `json:"download_path"`
+	MissingFromFs  bool        `json:"missing_from_fs"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	Emulator       string      `json:"emulator"`
+	Screenshot     *Screenshot `json:"screenshot"`
 }
 
 type SaveQuery struct {
@@ -54,40 +35,28 @@ func (sq SaveQuery) Valid() bool {
 }
 
 func (c *Client) GetSaves(query SaveQuery) ([]Save, error) {
+	return c.GetSavesContext(context.Background(), query)
+}
+
+// GetSavesContext is GetSaves with a context that cancels the request.
+func (c *Client) GetSavesContext(ctx context.Context, query SaveQuery) ([]Save, error) {
 	var saves []Save
-	err := c.doRequest("GET", endpointSaves, query, nil, &saves)
+	err := c.doRequest(ctx, "GET", endpointSaves, query, nil, &saves)
 	return saves, err
 }
 
 func (c *Client) DownloadSave(downloadPath string) ([]byte, error) {
-	return c.doRequestRaw("GET", downloadPath, nil)
+	return c.doRequestRaw(context.Background(), "GET", downloadPath, nil)
 }
 
 func (c *Client) UploadSave(romID int, savePath string, emulator string) (Save, error) {
-	file, err := os.Open(savePath)
-	if err != nil {
-		return Save{}, err
-	}
-	defer file.Close()
-
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	part, err := writer.CreateFormFile("saveFile", filepath.Base(savePath))
+	body, contentType, err := multipartFiles(multipartFile{Field: "saveFile", Path: savePath})
 	if err != nil {
 		return Save{}, err
 	}
 
-	if _, err := io.Copy(part, file); err != nil {
-		return Save{}, err
-	}
-
-	if err := writer.Close(); err != nil {
-		return Save{}, err
-	}
-
 	var res Save
-	err = c.doMultipartRequest("POST", endpointSaves, SaveQuery{RomID: romID, Emulator: emulator}, &buf, writer.FormDataContentType(), &res)
+	err = c.doMultipartRequest(context.Background(), "POST", endpointSaves, SaveQuery{RomID: romID, Emulator: emulator}, body, contentType, &res)
 	if err != nil {
 		return Save{}, err
 	}
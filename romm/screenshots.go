@@ -0,0 +1,53 @@
+package romm
+
+import (
+	"context"
+	"time"
+)
+
+// Screenshot is a user screenshot of a game, either uploaded on its own or attached to a save or state.
+type Screenshot struct {
+	ID             int       `json:"id,omitempty"`
+	RomID          int       `json:"rom_id,omitempty"`
+	UserID         int       `json:"user_id,omitempty"`
+	FileName       string    `json:"file_name,omitempty"`
+	FileNameNoTags string    `json:"file_name_no_tags,omitempty"`
+	FileNameNoExt  string    `json:"file_name_no_ext,omitempty"`
+	FileExtension  string    `json:"file_extension,omitempty"`
+	FilePath       string    `json:"file_path,omitempty"`
+	FileSizeBytes  int64     `json:"file_size_bytes,omitempty"`
+	FullPath       string    `json:"full_path,omitempty"`
+	DownloadPath   string    `json:"download_path,omitempty"`
+	URLPath        string    `json:"url_path,omitempty"`
+	Order          int       `json:"order,omitempty"`
+	MissingFromFs  bool      `json:"missing_from_fs,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+type ScreenshotQuery struct {
+	RomID int `qs:"rom_id"`
+}
+
+func (sq ScreenshotQuery) Valid() bool {
+	return sq.RomID != 0
+}
+
+func (c *Client) DownloadScreenshot(downloadPath string) ([]byte, error) {
+	return c.doRequestRaw(context.Background(), "GET", downloadPath, nil)
+}
+
+func (c *Client) UploadScreenshot(romID int, screenshotPath string) (Screenshot, error) {
+	body, contentType, err := multipartFiles(multipartFile{Field: "screenshotFile", Path: screenshotPath})
+	if err != nil {
+		return Screenshot{}, err
+	}
+
+	var res Screenshot
+	err = c.doMultipartRequest(context.Background(), "POST", endpointScreenshots, ScreenshotQuery{RomID: romID}, body, contentType, &res)
+	if err != nil {
+		return Screenshot{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,70 @@
+package romm
+
+import (
+	"context"
+	"time"
+)
+
+type State struct {
+	ID             int         `json:"id"`
+	RomID          int         `json:"rom_id"`
+	UserID         int         `json:"user_id"`
+	FileName       string      `json:"file_name"`
+	FileNameNoTags string      `json:"file_name_no_tags"`
+	FileNameNoExt  string      `json:"file_name_no_ext"`
+	FileExtension  string      `json:"file_extension"`
+	FilePath       string      `json:"file_path"`
+	FileSizeBytes  int64       `json:"file_size_bytes"`
+	FullPath       string      `json:"full_path"`
+	DownloadPath   string      `json:"download_path"`
+	MissingFromFs  bool        `json:"missing_from_fs"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	Emulator       string      `json:"emulator"`
+	Screenshot     *Screenshot `json:"screenshot"`
+}
+
+type StateQuery struct {
+	RomID      int    `qs:"rom_id"`
+	Emulator   string `qs:"emulator"`
+	PlatformID int    `qs:"platform_id"`
+}
+
+func (sq StateQuery) Valid() bool {
+	return sq.RomID != 0 || sq.PlatformID != 0
+}
+
+func (c *Client) GetStates(query StateQuery) ([]State, error) {
+	return c.GetStatesContext(context.Background(), query)
+}
+
+// GetStatesContext is GetStates with a context that cancels the request.
+func (c *Client) GetStatesContext(ctx context.Context, query StateQuery) ([]State, error) {
+	var states []State
+	err := c.doRequest(ctx, "GET", endpointStates, query, nil, &states)
+	return states, err
+}
+
+func (c *Client) DownloadState(downloadPath string) ([]byte, error) {
+	return c.doRequestRaw(context.Background(), "GET", downloadPath, nil)
+}
+
+// UploadState uploads a save state. screenshotPath is optional; when set the thumbnail
+// the emulator took of the state is attached to it.
+func (c *Client) UploadState(romID int, statePath, screenshotPath, emulator string) (State, error) {
+	body, contentType, err := multipartFiles(
+		multipartFile{Field: "stateFile", Path: statePath},
+		multipartFile{Field: "screenshotFile", Path: screenshotPath},
+	)
+	if err != nil {
+		return State{}, err
+	}
+
+	var res State
+	err = c.doMultipartRequest(context.Background(), "POST", endpointStates, StateQuery{RomID: romID, Emulator: emulator}, body, contentType, &res)
+	if err != nil {
+		return State{}, err
+	}
+
+	return res, nil
+}
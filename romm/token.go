@@ -0,0 +1,308 @@
+package romm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenScopes are the OAuth scopes requested when exchanging credentials.
+// Every RomM role (including viewers) is granted these, so the request never
+// fails with an insufficient scope error.
+var tokenScopes = []string{
+	"me.read", "me.write",
+	"roms.read", "roms.user.read", "roms.user.write",
+	"platforms.read",
+	"assets.read", "assets.write",
+	"firmware.read",
+	"collections.read", "collections.write",
+}
+
+// tokenExpiryLeeway refreshes access tokens slightly before they expire so a
+// request is never sent with a token that dies in flight.
+const tokenExpiryLeeway = 30 * time.Second
+
+// refreshRetryInterval is how long AuthHeader keeps handing out an expired token after
+// a refresh failed, rather than asking RomM again for every download.
+const refreshRetryInterval = 30 * time.Second
+
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time // Zero if the token never expires
+	IssuedAt     time.Time // Zero for tokens stored before it was recorded
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	Expires      int    `json:"expires"`
+}
+
+func (res tokenResponse) token() (Token, error) {
+	if res.AccessToken == "" {
+		return Token{}, fmt.Errorf("token response did not include an access token")
+	}
+
+	token := Token{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		IssuedAt:     time.Now(),
+	}
+	if res.Expires > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(res.Expires) * time.Second)
+	}
+	return token, nil
+}
+
+func (t Token) IsZero() bool {
+	return t.AccessToken == "" && t.RefreshToken == ""
+}
+
+func (t Token) expired() bool {
+	if t.AccessToken == "" {
+		return true
+	}
+	return !t.ExpiresAt.IsZero() && time.Until(t.ExpiresAt) < tokenExpiryLeeway
+}
+
+// newerThan reports whether t was issued after other. Tokens stored without an issue
+// time are compared by expiry instead, where a token that never expires is the newest.
+func (t Token) newerThan(other Token) bool {
+	if t.AccessToken == other.AccessToken {
+		return false
+	}
+	if other.AccessToken == "" {
+		return true
+	}
+	if !t.IssuedAt.IsZero() && !other.IssuedAt.IsZero() {
+		return t.IssuedAt.After(other.IssuedAt)
+	}
+	if !t.IssuedAt.IsZero() || !other.IssuedAt.IsZero() {
+		// Only tokens issued since issue times were recorded carry one
+		return !t.IssuedAt.IsZero()
+	}
+	if t.ExpiresAt.IsZero() || other.ExpiresAt.IsZero() {
+		return t.ExpiresAt.IsZero() && !other.ExpiresAt.IsZero()
+	}
+	return t.ExpiresAt.After(other.ExpiresAt)
+}
+
+// session holds the live token pair for a host. Clients for the same host share
+// a session so a refresh performed by one is picked up by all the others.
+type session struct {
+	mu              sync.Mutex
+	host            Host
+	token           Token
+	refreshFailedAt time.Time
+}
+
+var (
+	sessionsMu     sync.Mutex
+	sessions       = make(map[string]*session)
+	onTokenRefresh func(Host)
+)
+
+// SetTokenRefreshHandler registers a callback invoked with the updated host
+// whenever an access token is refreshed, so the caller can persist it.
+func SetTokenRefreshHandler(handler func(Host)) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	onTokenRefresh = handler
+}
+
+func sessionKey(host Host) string {
+	return host.URL() + "|" + host.Username
+}
+
+func sessionFor(host Host) *session {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	key := sessionKey(host)
+	s, ok := sessions[key]
+	if !ok {
+		s = &session{host: host, token: host.Token()}
+		sessions[key] = s
+		return s
+	}
+
+	// A host carrying a newer token than the session (e.g. after logging in
+	// again) replaces the stale pair.
+	s.mu.Lock()
+	if t := host.Token(); !t.IsZero() && t.newerThan(s.token) {
+		s.token = t
+		s.host = host
+	}
+	s.mu.Unlock()
+
+	return s
+}
+
+func (s *session) current() Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// authHeader returns the header for the session's token while it is valid, or while a
+// failed refresh is waiting to be retried. False if the token has to be refreshed.
+func (s *session) authHeader() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.AccessToken == "" {
+		return "", false
+	}
+	if !s.token.expired() || time.Since(s.refreshFailedAt) < refreshRetryInterval {
+		return "Bearer " + s.token.AccessToken, true
+	}
+	return "", false
+}
+
+// refresh exchanges the refresh token for a new access token. If another client
+// already refreshed since stale was issued, the newer token is reused instead.
+func (s *session) refresh(c *Client, stale Token) (Token, error) {
+	s.mu.Lock()
+
+	if s.token.AccessToken != stale.AccessToken && !s.token.expired() {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+
+	if s.token.RefreshToken == "" {
+		s.mu.Unlock()
+		return Token{}, &AuthError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "No refresh token available",
+			Err:        ErrUnauthorized,
+		}
+	}
+
+	token, err := c.requestToken(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.token.RefreshToken},
+	})
+	if err != nil {
+		s.refreshFailedAt = time.Now()
+		s.mu.Unlock()
+		return Token{}, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = s.token.RefreshToken
+	}
+
+	s.token = token
+	s.host = s.host.WithToken(token)
+	host := s.host
+	s.mu.Unlock()
+
+	sessionsMu.Lock()
+	handler := onTokenRefresh
+	sessionsMu.Unlock()
+	if handler != nil {
+		handler(host)
+	}
+
+	return token, nil
+}
+
+// Login exchanges a username and password for an access/refresh token pair.
+// The password is only sent for this request and is never retained.
+func (c *Client) Login(username, password string) (Token, error) {
+	token, err := c.requestToken(url.Values{
+		"grant_type": {"password"},
+		"username":   {username},
+		"password":   {password},
+		"scope":      {strings.Join(tokenScopes, " ")},
+	})
+	if err != nil {
+		return Token{}, err
+	}
+
+	if c.session != nil {
+		c.session.mu.Lock()
+		c.session.token = token
+		c.session.host = c.session.host.WithToken(token)
+		c.session.mu.Unlock()
+	}
+
+	return token, nil
+}
+
+// EnsureToken returns a valid access token, refreshing it first if it has expired.
+func (c *Client) EnsureToken() (Token, error) {
+	if c.session == nil {
+		return Token{}, &AuthError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "No session for client",
+			Err:        ErrUnauthorized,
+		}
+	}
+
+	token := c.session.current()
+	if !token.expired() {
+		return token, nil
+	}
+
+	return c.session.refresh(c, token)
+}
+
+func (c *Client) requestToken(form url.Values) (Token, error) {
+	req, err := c.newRequest(context.Background(), "POST", c.baseURL+endpointToken, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, ClassifyError(fmt.Errorf("failed to create token request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Token{}, ClassifyError(fmt.Errorf("failed to request token: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		var res tokenResponse
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			return Token{}, fmt.Errorf("failed to decode token response: %w", err)
+		}
+		return res.token()
+	case resp.StatusCode == 400, resp.StatusCode == 401:
+		return Token{}, &AuthError{
+			StatusCode: resp.StatusCode,
+			Message:    "Invalid username or password",
+			Err:        ErrUnauthorized,
+		}
+	case resp.StatusCode == 403:
+		return Token{}, &AuthError{
+			StatusCode: 403,
+			Message:    "Access forbidden",
+			Err:        ErrForbidden,
+		}
+	case resp.StatusCode >= 500:
+		return Token{}, &AuthError{
+			StatusCode: resp.StatusCode,
+			Message:    "Server error",
+			Err:        ErrServerError,
+		}
+	case resp.StatusCode == 405:
+		if protocolErr := c.tryAlternateProtocol(req.URL.Scheme, func(r *http.Response) bool {
+			return r.StatusCode >= 200 && r.StatusCode < 300
+		}); protocolErr != nil {
+			return Token{}, protocolErr
+		}
+		return Token{}, fmt.Errorf("token request failed with status: %d", resp.StatusCode)
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return Token{}, fmt.Errorf("token request failed with status: %d, body: %s", resp.StatusCode, string(body))
+	}
+}
@@ -0,0 +1,15 @@
+package romm
+
+import "context"
+
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (c *Client) GetCurrentUser() (User, error) {
+	var user User
+	err := c.doRequest(context.Background(), "GET", endpointCurrentUser, nil, nil, &user)
+	return user, err
+}
@@ -1,8 +1,10 @@
 package sync
 
 import (
+	"context"
 	"grout/internal"
 	"grout/romm"
+	gosync "sync"
 	"sync/atomic"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
@@ -16,6 +18,8 @@ type AutoSync struct {
 	running    atomic.Bool
 	done       chan struct{}
 	showButton atomic.Bool
+	mu         gosync.Mutex
+	cancel     context.CancelFunc
 }
 
 func NewAutoSync(host romm.Host, config *internal.Config) *AutoSync {
@@ -34,9 +38,14 @@ func (a *AutoSync) Icon() gaba.StatusBarIcon {
 }
 
 func (a *AutoSync) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.mu.Lock()
+	a.cancel = cancel
+	a.mu.Unlock()
+
 	a.running.Store(true)
 	a.done = make(chan struct{}) // Reinitialize channel for reuse
-	go a.run()
+	go a.run(ctx)
 }
 
 func (a *AutoSync) IsRunning() bool {
@@ -47,6 +56,20 @@ func (a *AutoSync) Wait() {
 	<-a.done
 }
 
+// Stop cancels the sync in progress and waits for it to return. A save being transferred
+// finishes first, so none is left half written; the rest wait for the next sync.
+func (a *AutoSync) Stop() {
+	a.mu.Lock()
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.mu.Unlock()
+
+	if a.running.Load() {
+		a.Wait()
+	}
+}
+
 func (a *AutoSync) ShowButton() *atomic.Bool {
 	return &a.showButton
 }
@@ -65,7 +88,7 @@ func (a *AutoSync) Host() romm.Host {
 	return a.host
 }
 
-func (a *AutoSync) run() {
+func (a *AutoSync) run(ctx context.Context) {
 	logger := gaba.GetLogger()
 	defer func() {
 		if r := recover(); r != nil {
@@ -76,17 +99,51 @@ func (a *AutoSync) run() {
 		close(a.done)
 	}()
 
+	cancelled := func() bool {
+		if ctx.Err() == nil {
+			return false
+		}
+		logger.Info("AutoSync: Cancelled, leaving the remaining saves for the next sync")
+		a.icon.SetText(icons.CloudRefresh)
+		return true
+	}
+
+	// Saves stay where they are until RomM is back, which triggers another sync
+	if internal.IsOffline() {
+		logger.Debug("AutoSync: Offline, leaving saves for later")
+		return
+	}
+
 	a.icon.SetText(icons.CloudRefresh)
 	logger.Debug("AutoSync: Starting save sync scan")
 
-	syncs, unmatched, _, err := FindSaveSyncs(a.host, a.config)
+	scan := ScanRoms(a.config)
+	syncs, unmatched, _, err := FindSaveSyncsFromScan(ctx, a.host, a.config, scan)
+	if cancelled() {
+		return
+	}
 	if err != nil {
+		if internal.NoteRequestError(err) {
+			logger.Info("AutoSync: RomM unreachable, leaving saves for later", "error", err)
+			return
+		}
 		logger.Error("AutoSync: Failed to find save syncs", "error", err)
 		a.icon.SetText(icons.CloudAlert)
 		return
 	}
 
-	if len(syncs) == 0 {
+	var stateSyncs []StateSync
+	if a.config.SyncSaveStates {
+		stateSyncs, err = FindStateSyncs(ctx, a.host, a.config, scan)
+		if cancelled() {
+			return
+		}
+		if err != nil {
+			logger.Error("AutoSync: Failed to find save state syncs", "error", err)
+		}
+	}
+
+	if len(syncs) == 0 && len(stateSyncs) == 0 {
 		if len(unmatched) > 0 {
 			a.icon.SetText(icons.CloudAlert)
 			logger.Debug("AutoSync: No syncs needed but has unmatched saves", "unmatched", len(unmatched))
@@ -100,8 +157,12 @@ func (a *AutoSync) run() {
 	logger.Debug("AutoSync: Found syncs", "count", len(syncs))
 
 	hadError := false
+	conflicts := 0
 
 	for i := range syncs {
+		if cancelled() {
+			return
+		}
 		s := &syncs[i]
 
 		switch s.Action {
@@ -111,11 +172,16 @@ func (a *AutoSync) run() {
 		case Download:
 			a.icon.SetText(icons.CloudDownload)
 			logger.Debug("AutoSync: Downloading", "game", s.GameBase)
-		case Skip:
+		case Conflict:
+			// Conflicts need a decision, so they wait for a manual sync
+			logger.Info("AutoSync: Save changed on both sides, skipping", "game", s.GameBase)
+			conflicts++
+			continue
+		case Skip, Excluded:
 			continue
 		}
 
-		result := s.Execute(a.host, a.config)
+		result := s.Execute(a.config)
 		if !result.Success {
 			logger.Error("AutoSync: Sync failed", "game", s.GameBase, "error", result.Error)
 			hadError = true
@@ -124,10 +190,39 @@ func (a *AutoSync) run() {
 		}
 	}
 
-	if hadError || len(unmatched) > 0 {
+	for i := range stateSyncs {
+		if cancelled() {
+			return
+		}
+		s := &stateSyncs[i]
+
+		switch s.Action {
+		case Upload:
+			a.icon.SetText(icons.CloudUpload)
+		case Download:
+			a.icon.SetText(icons.CloudDownload)
+		case Conflict:
+			logger.Info("AutoSync: Save state changed on both sides, skipping", "game", s.BaseName, "slot", s.Slot)
+			conflicts++
+			continue
+		default:
+			continue
+		}
+		logger.Debug("AutoSync: Syncing save state", "game", s.BaseName, "slot", s.Slot, "action", s.Action)
+
+		result := s.Execute(a.config)
+		if !result.Success {
+			logger.Error("AutoSync: Save state sync failed", "game", s.BaseName, "error", result.Error)
+			hadError = true
+		}
+	}
+
+	if hadError || conflicts > 0 || len(unmatched) > 0 {
 		a.icon.SetText(icons.CloudAlert)
 		if hadError {
 			logger.Debug("AutoSync: Completed with errors")
+		} else if conflicts > 0 {
+			logger.Debug("AutoSync: Completed with save conflicts", "conflicts", conflicts)
 		} else {
 			logger.Debug("AutoSync: Completed with unmatched saves", "unmatched", len(unmatched))
 		}
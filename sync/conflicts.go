@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// compareWithLastSync decides what to do with a save or save state that exists on both
+// sides using the state recorded when it was last synced. A local file only counts as
+// changed if its contents differ, so touching it without playing doesn't cause an upload.
+// RomM only ever adds saves and states, so a newer ID means another device uploaded since.
+// Returns false if the local file at path couldn't be read.
+func compareWithLastSync(state cache.SaveSyncState, path string, modified time.Time, remoteID int) (Action, bool) {
+	localChanged := false
+	if !modified.Truncate(time.Second).Equal(state.LocalModifiedAt.Truncate(time.Second)) {
+		hash, err := fileutil.ComputeSHA1(path)
+		if err != nil {
+			gaba.GetLogger().Warn("Unable to hash local save", "path", path, "error", err)
+			return Skip, false
+		}
+		localChanged = hash != state.LocalHash
+	}
+	remoteChanged := remoteID > state.RemoteSaveID
+
+	gaba.GetLogger().Debug("Comparing save with last sync",
+		"path", path,
+		"localChanged", localChanged,
+		"remoteChanged", remoteChanged,
+		"remoteSaveID", remoteID,
+		"lastSyncedSaveID", state.RemoteSaveID,
+		"lastSyncedAt", state.SyncedAt.Format(time.RFC3339))
+
+	switch {
+	case localChanged && remoteChanged:
+		return Conflict, true
+	case localChanged:
+		return Upload, true
+	case remoteChanged:
+		return Download, true
+	default:
+		return Skip, true
+	}
+}
+
+// recordSyncState remembers the local save or state at path and the RomM copy it now
+// matches.
+func recordSyncState(cm *cache.Manager, romID int, baseName, path string, remoteSaveID int) {
+	logger := gaba.GetLogger()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		logger.Warn("Unable to record save sync state", "path", path, "error", err)
+		return
+	}
+
+	hash, err := fileutil.ComputeSHA1(path)
+	if err != nil {
+		logger.Warn("Unable to record save sync state", "path", path, "error", err)
+		return
+	}
+
+	err = cm.SetSaveSyncState(cache.SaveSyncState{
+		RomID:           romID,
+		BaseName:        baseName,
+		LocalHash:       hash,
+		LocalModifiedAt: info.ModTime(),
+		RemoteSaveID:    remoteSaveID,
+	})
+	if err != nil {
+		logger.Warn("Unable to record save sync state", "path", path, "error", err)
+	}
+}
+
+// RemoteTime returns when the RomM save was made, falling back to when it was uploaded.
+func (s SaveSync) RemoteTime() time.Time {
+	if t, ok := extractSaveTimestamp(s.Remote.FileNameNoExt); ok {
+		return t
+	}
+	return s.Remote.UpdatedAt
+}
+
+// keepBoth resolves a conflict without overwriting either save. The local save stays in
+// use and the RomM save is downloaded into the .backup folder, where it can be restored
+// from Save History. Both are then recorded as synced so the conflict isn't raised again.
+func (s *SaveSync) keepBoth(cm *cache.Manager, config *internal.Config) (string, error) {
+	if s.Local == nil {
+		return "", fmt.Errorf("cannot keep both: no local save file")
+	}
+
+	rc := romm.NewClientFromHost(s.Host, config.ApiTimeout)
+	data, err := rc.DownloadSave(s.Remote.DownloadPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to download save: %w", err)
+	}
+
+	remoteCopy := LocalSave{
+		FSSlug:       s.FSSlug,
+		Path:         filepath.Join(filepath.Dir(s.Local.Path), s.GameBase+normalizeExt(s.Remote.FileExtension)),
+		LastModified: s.RemoteTime(),
+	}
+	destPath := filepath.Join(filepath.Dir(s.Local.Path), ".backup", remoteCopy.timestampedFilename())
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write save file: %w", err)
+	}
+
+	recordSyncState(cm, s.RomID, s.GameBase, s.Local.Path, s.Remote.ID)
+	return destPath, nil
+}
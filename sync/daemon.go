@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"grout/cfw"
+	"grout/internal"
+	"grout/romm"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// saveDaemonDebounce is how long the save folders have to stay quiet before changed saves
+// are uploaded. Emulators write saves in bursts, and a game exiting writes it once more.
+const saveDaemonDebounce = 10 * time.Second
+
+// SaveDaemon uploads saves to RomM shortly after a game writes them, without Grout's UI
+// being open. It only ever uploads: saves that would be downloaded, conflicts and
+// unmatched saves are left for the next sync from Grout.
+type SaveDaemon struct {
+	host     romm.Host
+	config   *internal.Config
+	debounce time.Duration
+}
+
+func NewSaveDaemon(host romm.Host, config *internal.Config) *SaveDaemon {
+	return &SaveDaemon{
+		host:     host,
+		config:   config,
+		debounce: saveDaemonDebounce,
+	}
+}
+
+// Run watches the save folders of every mapped platform until ctx is cancelled. Saves
+// still waiting for the debounce when it is cancelled are uploaded before returning.
+func (d *SaveDaemon) Run(ctx context.Context) error {
+	logger := gaba.GetLogger()
+
+	watcher, err := newSaveWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.close()
+
+	watched := 0
+	for _, dir := range d.saveDirectories() {
+		if err := watcher.add(dir); err != nil {
+			logger.Warn("SaveDaemon: Unable to watch save folder", "dir", dir, "error", err)
+			continue
+		}
+		watched++
+	}
+	if watched == 0 {
+		return errors.New("no save folders to watch")
+	}
+	logger.Info("SaveDaemon: Watching save folders", "count", watched)
+
+	changes := make(chan string, 64)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- watcher.run(ctx, func(path string) {
+			select {
+			case changes <- path:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(d.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(pending) > 0 {
+				d.upload(context.WithoutCancel(ctx), pending)
+			}
+			return nil
+
+		case err := <-watchErr:
+			if err != nil {
+				return err
+			}
+
+		case path := <-changes:
+			name := filepath.Base(path)
+			if strings.HasPrefix(name, ".") || isStateFile(name) {
+				continue
+			}
+			logger.Debug("SaveDaemon: Save written", "path", path)
+			pending[path] = true
+			timer.Reset(d.debounce)
+
+		case <-timer.C:
+			d.upload(ctx, pending)
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// saveDirectories lists the save folders of the mapped platforms on this device.
+func (d *SaveDaemon) saveDirectories() []string {
+	basePath := cfw.BaseSavePath()
+	seen := make(map[string]bool)
+	var dirs []string
+
+	for fsSlug := range d.config.DirectoryMappings {
+		if d.config.IsSyncExcluded(fsSlug, 0) {
+			continue
+		}
+		for _, folder := range cfw.EmulatorFoldersForFSSlug(d.config.ResolveFSSlug(fsSlug)) {
+			dir := filepath.Join(basePath, folder)
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// upload runs the regular save sync for the ROMs whose saves were written and uploads
+// the ones that are newer than RomM.
+func (d *SaveDaemon) upload(ctx context.Context, paths map[string]bool) {
+	logger := gaba.GetLogger()
+
+	scan := make(LocalRomScan)
+	for fsSlug, roms := range ScanRoms(d.config) {
+		for _, rom := range roms {
+			if rom.SaveFile != nil && paths[rom.SaveFile.Path] {
+				scan[fsSlug] = append(scan[fsSlug], rom)
+			}
+		}
+	}
+	if len(scan) == 0 {
+		logger.Debug("SaveDaemon: Written files are not saves of any local ROM", "count", len(paths))
+		return
+	}
+
+	syncs, _, _, err := FindSaveSyncsFromScan(ctx, d.host, d.config, scan)
+	if err != nil {
+		logger.Error("SaveDaemon: Failed to find save syncs", "error", err)
+		return
+	}
+
+	for i := range syncs {
+		s := &syncs[i]
+		if s.Action != Upload {
+			logger.Info("SaveDaemon: Leaving save for the next sync", "game", s.GameBase, "action", s.Action)
+			continue
+		}
+
+		result := s.Execute(d.config)
+		if !result.Success {
+			logger.Error("SaveDaemon: Upload failed", "game", s.GameBase, "error", result.Error)
+			continue
+		}
+		logger.Info("SaveDaemon: Uploaded save", "game", s.GameBase)
+	}
+}
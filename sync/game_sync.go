@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrRomNotFound   = errors.New("not a ROM of a mapped platform")
+	ErrRomNotMatched = errors.New("ROM not found in RomM")
+	ErrRomOtherHost  = errors.New("ROM was downloaded from a host that is no longer configured")
+)
+
+// FindGameSaveSync works out how the save of the single ROM at romPath needs to sync, for
+// CFW launch hooks. The ROM is matched to RomM by filename and then by hash. Fuzzy title
+// matches need confirming in Grout, so they are not used. A game downloaded from another
+// server than host syncs with that server. Returns nil if the save is in sync.
+func FindGameSaveSync(ctx context.Context, host romm.Host, config *internal.Config, romPath string) (*SaveSync, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+
+	fsSlug, rom, ok := findLocalRom(ScanRoms(config), romPath)
+	if !ok {
+		return nil, ErrRomNotFound
+	}
+	host, ok = config.SaveHost(fsSlug, rom.FileName, host)
+	if !ok {
+		return nil, ErrRomOtherHost
+	}
+
+	// A hash match is remembered as a filename mapping, which the sync below picks up
+	var romID int
+	withHostCache(host, config, func(cm *cache.Manager) {
+		if romID, _ = lookupRomID(cm, &rom); romID == 0 {
+			rc := romm.NewClientFromHost(host, config.ApiTimeout)
+			romID, _ = lookupRomByHash(ctx, cm, rc, &rom, nil)
+		}
+	})
+	if romID == 0 {
+		return nil, ErrRomNotMatched
+	}
+
+	syncs, _, _, err := FindSaveSyncsFromScan(ctx, host, config, LocalRomScan{fsSlug: {rom}})
+	if err != nil {
+		return nil, err
+	}
+	if len(syncs) == 0 {
+		return nil, nil
+	}
+	return &syncs[0], nil
+}
+
+// findLocalRom finds the scanned ROM at romPath. Launchers don't always use the same mount
+// point Grout scans (muOS launches from the SD card rather than the union folder), so a ROM
+// in a folder with the same name also matches.
+func findLocalRom(scan LocalRomScan, romPath string) (string, LocalRomFile, bool) {
+	romPath, err := filepath.Abs(romPath)
+	if err != nil {
+		return "", LocalRomFile{}, false
+	}
+
+	var fallbackSlug string
+	var fallback *LocalRomFile
+	for fsSlug, roms := range scan {
+		for i := range roms {
+			rom := &roms[i]
+			// Multi-file games are scanned as their folder
+			if rom.FilePath == romPath || strings.HasPrefix(romPath, rom.FilePath+string(filepath.Separator)) {
+				return fsSlug, *rom, true
+			}
+			if fallback == nil && sameFolderAndName(rom.FilePath, romPath) {
+				fallbackSlug, fallback = fsSlug, rom
+			}
+		}
+	}
+
+	if fallback != nil {
+		return fallbackSlug, *fallback, true
+	}
+	return "", LocalRomFile{}, false
+}
+
+func sameFolderAndName(a, b string) bool {
+	return filepath.Base(a) == filepath.Base(b) &&
+		filepath.Base(filepath.Dir(a)) == filepath.Base(filepath.Dir(b))
+}
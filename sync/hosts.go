@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// hostScan is the part of a scan whose saves sync with one server. origin holds the index
+// each ROM has in the full scan.
+type hostScan struct {
+	host   romm.Host
+	scan   LocalRomScan
+	origin map[string][]int
+}
+
+// splitScanByHost groups the ROMs in scan by the server their saves sync with. Games
+// downloaded from another server than host sync with that one, the rest with host, which
+// always comes first. ROMs from a server that is no longer configured are left out.
+func splitScanByHost(host romm.Host, config *internal.Config, scan LocalRomScan) []*hostScan {
+	groups := []*hostScan{{host: host, scan: LocalRomScan{}, origin: map[string][]int{}}}
+	byURL := map[string]*hostScan{host.URL(): groups[0]}
+
+	for fsSlug, roms := range scan {
+		for i, rom := range roms {
+			romHost, ok := config.SaveHost(fsSlug, rom.FileName, host)
+			if !ok {
+				gaba.GetLogger().Debug("Save syncs with a host that is no longer configured", "romFile", rom.FileName, "fsSlug", fsSlug)
+				continue
+			}
+
+			group, ok := byURL[romHost.URL()]
+			if !ok {
+				group = &hostScan{host: romHost, scan: LocalRomScan{}, origin: map[string][]int{}}
+				byURL[romHost.URL()] = group
+				groups = append(groups, group)
+			}
+			group.scan[fsSlug] = append(group.scan[fsSlug], rom)
+			group.origin[fsSlug] = append(group.origin[fsSlug], i)
+		}
+	}
+
+	return groups
+}
+
+// copyBack writes the matches found for the group's ROMs back to the full scan, where the
+// save state sync picks them up.
+func (g *hostScan) copyBack(scan LocalRomScan) {
+	for fsSlug, roms := range g.scan {
+		for i, rom := range roms {
+			scan[fsSlug][g.origin[fsSlug][i]] = rom
+		}
+	}
+}
+
+// withHostCache runs fn with the cache of host, see cache.HostCache. fn gets a nil cache if
+// it can't be opened, which then works as an empty one.
+func withHostCache(host romm.Host, config *internal.Config, fn func(cm *cache.Manager)) {
+	cm, release, err := cache.HostCache(host, config)
+	if err != nil {
+		gaba.GetLogger().Warn("Unable to open cache of host", "host", host.URL(), "error", err)
+	}
+	defer release()
+	fn(cm)
+}
@@ -0,0 +1,250 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// LocalGame is a game found on the device together with everything stored for it.
+type LocalGame struct {
+	FSSlug   string
+	Name     string
+	BaseName string
+	Rom      romm.Rom // Zero if the game couldn't be matched against the cache
+	Platform romm.Platform
+	Paths    []string // ROM files, playlists and multi-disc folders
+	Size     int64
+	Saves    []LocalSave
+}
+
+func (g LocalGame) IsMatched() bool {
+	return g.Rom.ID != 0
+}
+
+// FileName returns the name of the game's ROM file or folder, which is how the config
+// records the server it was downloaded from.
+func (g LocalGame) FileName() string {
+	if len(g.Paths) == 0 {
+		return g.BaseName
+	}
+	return strings.TrimPrefix(filepath.Base(g.Paths[0]), "_")
+}
+
+func (g LocalGame) RomDirectory() string {
+	if len(g.Paths) == 0 {
+		return ""
+	}
+	return filepath.Dir(g.Paths[0])
+}
+
+// ScanLocalGames groups the files found by ScanRoms into games, keyed by platform fs_slug.
+// Files sharing a base name (cue/bin, m3u) belong to the same game. Folders are only
+// included when they are a multi-disc game, either next to its playlist (muOS prefixes
+// the folder with an underscore) or matching a cached ROM, so art and media folders are skipped.
+func ScanLocalGames(config *internal.Config) map[string][]LocalGame {
+	logger := gaba.GetLogger()
+	result := make(map[string][]LocalGame)
+
+	platforms := make(map[string]romm.Platform)
+	if cm := cache.GetCacheManager(); cm != nil {
+		if cached, err := cm.GetPlatforms(); err == nil {
+			for _, p := range cached {
+				platforms[p.FSSlug] = p
+			}
+		}
+	}
+
+	for fsSlug, roms := range ScanRoms(config) {
+		platform, ok := platforms[fsSlug]
+		if !ok {
+			platform = romm.Platform{FSSlug: fsSlug, Name: fsSlug}
+		}
+
+		games := make(map[string]*LocalGame)
+		romDirs := make(map[string]bool)
+
+		for _, rom := range roms {
+			if strings.EqualFold(rom.FileName, "gamelist.xml") {
+				continue
+			}
+			romDirs[filepath.Dir(rom.FilePath)] = true
+
+			info, err := os.Stat(rom.FilePath)
+			if err != nil {
+				continue
+			}
+
+			game := addLocalGamePath(games, fsSlug, platform, rom.baseName(), rom.FilePath, info.Size())
+			if rom.SaveFile != nil && !slices.ContainsFunc(game.Saves, func(s LocalSave) bool { return s.Path == rom.SaveFile.Path }) {
+				game.Saves = append(game.Saves, *rom.SaveFile)
+			}
+		}
+
+		var saveFileMap map[string]*LocalSave
+		artDir := config.GetArtDirectory(platform)
+
+		for romDir := range romDirs {
+			entries, err := os.ReadDir(romDir)
+			if err != nil {
+				continue
+			}
+
+			for _, entry := range entries {
+				if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+					continue
+				}
+
+				dirPath := filepath.Join(romDir, entry.Name())
+				if dirPath == artDir {
+					continue
+				}
+
+				baseName := strings.TrimPrefix(entry.Name(), "_")
+				if _, exists := games[baseName]; !exists {
+					if _, _, found := cache.GetCachedRomIDByFilename(fsSlug, baseName); !found {
+						continue
+					}
+				}
+
+				game := addLocalGamePath(games, fsSlug, platform, baseName, dirPath, fileutil.DirSize(dirPath))
+
+				if len(game.Saves) == 0 {
+					if saveFileMap == nil {
+						saveFileMap = buildSaveFileMap(fsSlug, config)
+					}
+					if save, found := saveFileMap[baseName]; found {
+						game.Saves = append(game.Saves, *save)
+					}
+				}
+			}
+		}
+
+		platformGames := make([]LocalGame, 0, len(games))
+		for _, game := range games {
+			resolveLocalGame(game)
+			platformGames = append(platformGames, *game)
+		}
+
+		slices.SortFunc(platformGames, func(a, b LocalGame) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+
+		if len(platformGames) > 0 {
+			result[fsSlug] = platformGames
+		}
+	}
+
+	logger.Debug("Scanned local games", "platforms", len(result))
+	return result
+}
+
+func addLocalGamePath(games map[string]*LocalGame, fsSlug string, platform romm.Platform, baseName, path string, size int64) *LocalGame {
+	game, exists := games[baseName]
+	if !exists {
+		game = &LocalGame{
+			FSSlug:   fsSlug,
+			Name:     baseName,
+			BaseName: baseName,
+			Platform: platform,
+		}
+		games[baseName] = game
+	}
+
+	game.Paths = append(game.Paths, path)
+	game.Size += size
+	return game
+}
+
+// resolveLocalGame fills in the RomM details of a game from the cache, trying each of
+// its files since only one of them (e.g. the m3u, not the folder) may match.
+func resolveLocalGame(game *LocalGame) {
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return
+	}
+
+	for _, path := range game.Paths {
+		romID, romName, found := cache.GetCachedRomIDByFilename(game.FSSlug, strings.TrimPrefix(filepath.Base(path), "_"))
+		if !found {
+			continue
+		}
+
+		game.Name = romName
+		if roms, err := cm.GetGamesByIDs([]int{romID}); err == nil && len(roms) > 0 {
+			game.Rom = roms[0]
+			if game.Rom.Name != "" {
+				game.Name = game.Rom.Name
+			}
+		} else {
+			game.Rom = romm.Rom{ID: romID, Name: romName}
+		}
+		return
+	}
+}
+
+// DeleteLocalGame removes a game from the device: its files and multi-disc folders, the
+// box art downloaded with it, its gamelist/description entry and cached artwork. Saves
+// are only removed when deleteSaves is set, after being backed up like any overwritten save.
+func DeleteLocalGame(config *internal.Config, game LocalGame, deleteSaves bool) error {
+	logger := gaba.GetLogger()
+
+	var errs []error
+	fileNames := make([]string, 0, len(game.Paths))
+
+	for _, path := range game.Paths {
+		fileNames = append(fileNames, filepath.Base(path))
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", path, err))
+		}
+	}
+
+	artName := game.BaseName
+	if game.Rom.FsNameNoExt != "" {
+		artName = game.Rom.FsNameNoExt
+	}
+
+	if artDir := config.GetArtDirectory(game.Platform); artDir != "" {
+		artPath := filepath.Join(artDir, artName+".png")
+		if err := os.Remove(artPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Failed to remove box art", "path", artPath, "error", err)
+		}
+	}
+
+	if game.IsMatched() {
+		artworkPath := cache.GetArtworkCachePath(game.FSSlug, game.Rom.ID)
+		if err := os.Remove(artworkPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Failed to remove cached artwork", "path", artworkPath, "error", err)
+		}
+	}
+
+	if err := cfw.RemoveGameMetadata(game.RomDirectory(), game.Platform.FSSlug, game.Platform.Name, artName, fileNames); err != nil {
+		logger.Warn("Failed to remove game metadata", "game", game.Name, "error", err)
+	}
+
+	if deleteSaves {
+		for _, save := range game.Saves {
+			if err := save.backup(); err != nil {
+				logger.Warn("Failed to back up save before deleting", "path", save.Path, "error", err)
+				errs = append(errs, fmt.Errorf("failed to back up %s: %w", save.Path, err))
+				continue
+			}
+			if err := os.Remove(save.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", save.Path, err))
+			}
+		}
+	}
+
+	logger.Debug("Deleted local game", "game", game.Name, "paths", game.Paths, "deleteSaves", deleteSaves)
+	return errors.Join(errs...)
+}
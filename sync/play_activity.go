@@ -0,0 +1,155 @@
+package sync
+
+import (
+	"context"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/romm"
+	"path/filepath"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// ScanPlayActivity records in the cache what the CFW knows about the games of host played
+// on the device. Plays are matched to games downloaded with Grout by path or, when the CFW
+// only keeps the name, by file name. Games whose name matches more than one ROM, and games
+// downloaded from another server, are skipped.
+//
+// Plays found the first time the device is scanned are taken as already reported, as
+// RomM can only stamp a play with the time it is told about it.
+func ScanPlayActivity(host romm.Host, config *internal.Config) error {
+	history := cfw.GetPlayHistory()
+	cm := cache.GetCacheManager()
+	if history == nil || cm == nil {
+		return nil
+	}
+
+	byPath := make(map[string]LocalGame)
+	byName := make(map[string][]LocalGame)
+	var romDirs []string
+	seenDirs := make(map[string]bool)
+
+	for _, games := range ScanLocalGames(config) {
+		for _, game := range games {
+			if !game.IsMatched() {
+				continue
+			}
+			if dir := game.RomDirectory(); !seenDirs[dir] {
+				seenDirs[dir] = true
+				romDirs = append(romDirs, dir)
+			}
+			for _, path := range game.Paths {
+				byPath[filepath.Clean(path)] = game
+			}
+			name := strings.ToLower(game.BaseName)
+			byName[name] = append(byName[name], game)
+		}
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	records, err := history.PlayRecords(romDirs)
+	if err != nil {
+		// Whatever could be read is still worth keeping
+		gaba.GetLogger().Warn("Failed to read some of the play history", "error", err)
+	}
+
+	latest := make(map[int]cfw.PlayRecord)
+	for _, record := range records {
+		game, ok := matchPlayRecord(record, byPath, byName)
+		if !ok || !config.SyncsWithHost(game.FSSlug, game.FileName(), host) {
+			continue
+		}
+		if current, seen := latest[game.Rom.ID]; !seen || record.LastPlayed.After(current.LastPlayed) {
+			latest[game.Rom.ID] = record
+		}
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+
+	// Guessing wrong would either lose the plays or report old ones as new
+	hasActivity, err := cm.HasPlayActivity()
+	if err != nil {
+		return err
+	}
+	firstScan := !hasActivity
+
+	for romID, record := range latest {
+		activity := cache.PlayActivity{RomID: romID, LastPlayed: record.LastPlayed, PlayTime: record.PlayTime}
+		if firstScan {
+			activity.ReportedLastPlayed = record.LastPlayed
+		}
+		if err := cm.SavePlayActivity(activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchPlayRecord(record cfw.PlayRecord, byPath map[string]LocalGame, byName map[string][]LocalGame) (LocalGame, bool) {
+	name := record.Path
+	dir := ""
+	if filepath.IsAbs(record.Path) {
+		if game, ok := byPath[filepath.Clean(record.Path)]; ok {
+			return game, true
+		}
+		name = strings.TrimSuffix(filepath.Base(record.Path), filepath.Ext(record.Path))
+		dir = filepath.Dir(record.Path)
+	}
+
+	candidates := byName[strings.ToLower(strings.TrimPrefix(name, "_"))]
+	for _, game := range candidates {
+		if dir != "" && game.RomDirectory() == dir {
+			return game, true
+		}
+	}
+
+	// The same ROM can be on the device twice, anything else is a guess
+	for _, game := range candidates {
+		if game.Rom.ID != candidates[0].Rom.ID {
+			return LocalGame{}, false
+		}
+	}
+	if len(candidates) == 0 {
+		return LocalGame{}, false
+	}
+	return candidates[0], true
+}
+
+// ReportPlayActivity tells RomM about the games played since they were last reported.
+// Stops at the first failure, or once ctx is cancelled, the rest are reported next time.
+func ReportPlayActivity(ctx context.Context, host romm.Host, config *internal.Config) error {
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return nil
+	}
+
+	activity, err := cm.GetPlayActivity()
+	if err != nil {
+		return err
+	}
+
+	rc := romm.NewClientFromHost(host, config.ApiTimeout)
+	reported := 0
+	for _, a := range activity {
+		if !a.NeedsReport() {
+			continue
+		}
+		if err := rc.UpdateLastPlayedContext(ctx, a.RomID); err != nil {
+			return err
+		}
+		if err := cm.MarkPlayActivityReported(a); err != nil {
+			return err
+		}
+		reported++
+	}
+
+	if reported > 0 {
+		gaba.GetLogger().Info("Reported play activity to RomM", "count", reported)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
 	"grout/internal/fileutil"
@@ -79,7 +80,7 @@ func (lrf LocalRomFile) baseName() string {
 	return strings.TrimSuffix(lrf.FileName, filepath.Ext(lrf.FileName))
 }
 
-func (lrf LocalRomFile) syncAction() Action {
+func (lrf LocalRomFile) syncAction(cm *cache.Manager) Action {
 	logger := gaba.GetLogger()
 	hasLocal := lrf.SaveFile != nil
 	baseName := lrf.baseName()
@@ -95,12 +96,20 @@ func (lrf LocalRomFile) syncAction() Action {
 		return Download
 	}
 
-	// Both local and remote exist - compare timestamps
+	remoteSave := lrf.lastRemoteSaveForBaseName(baseName)
+
+	// Both local and remote exist - if they've been synced before, check which side changed since
+	if state, found, _ := cm.GetSaveSyncState(lrf.RomID, baseName); found {
+		if action, ok := compareWithLastSync(state, lrf.SaveFile.Path, lrf.SaveFile.LastModified, remoteSave.ID); ok {
+			return action
+		}
+	}
+
+	// Never synced from this device - compare timestamps
 	// Use the timestamp embedded in the remote save's filename (original save time)
 	// rather than UpdatedAt (when uploaded to RomM) to avoid precision issues
 	localTimeRaw := lrf.SaveFile.LastModified
 	localTime := localTimeRaw.Truncate(time.Second)
-	remoteSave := lrf.lastRemoteSaveForBaseName(baseName)
 
 	remoteTime, parsedFromFilename := extractSaveTimestamp(remoteSave.FileNameNoExt)
 	remoteTimeSource := "filename"
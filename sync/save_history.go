@@ -0,0 +1,217 @@
+package sync
+
+import (
+	"fmt"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// backupNamePattern matches the name of a local backup written by LocalSave.backup
+// Group 1: save base name, Group 2: timestamp
+var backupNamePattern = regexp.MustCompile(`^(.*) \[(\d{4}-\d{2}-\d{2} \d{2}-\d{2}-\d{2})\]$`)
+
+type SaveVersionSource string
+
+const (
+	SaveVersionLocal  SaveVersionSource = "local"
+	SaveVersionRemote SaveVersionSource = "remote"
+)
+
+// SaveVersion is an older copy of a game's save, either a local backup or a save uploaded to RomM.
+type SaveVersion struct {
+	Source   SaveVersionSource
+	Path     string // Backup file, local versions only
+	Remote   romm.Save
+	BaseName string
+	Ext      string
+	Emulator string
+	Size     int64
+	Time     time.Time
+}
+
+// SaveHistory is everything known about the saves of a single game.
+type SaveHistory struct {
+	Current  *LocalSave // Nil if the game has no save on the device
+	Versions []SaveVersion
+}
+
+// saveBaseNames returns the names a game's save can be stored under, one per ROM file.
+func saveBaseNames(game romm.Rom) []string {
+	names := []string{game.FsNameNoExt}
+	for _, f := range game.Files {
+		name := strings.TrimSuffix(f.FileName, filepath.Ext(f.FileName))
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetSaveHistory lists the current save of a game along with its local backups and the
+// versions kept by RomM, newest first. Local versions are returned even if RomM can't be
+// reached, in which case the error is returned as well.
+func GetSaveHistory(host romm.Host, config *internal.Config, game romm.Rom) (SaveHistory, error) {
+	logger := gaba.GetLogger()
+	history := SaveHistory{}
+	baseNames := saveBaseNames(game)
+
+	for _, save := range findSaveFiles(game.PlatformFSSlug, config) {
+		baseName := strings.TrimSuffix(filepath.Base(save.Path), filepath.Ext(save.Path))
+		if slices.Contains(baseNames, baseName) {
+			current := save
+			history.Current = &current
+			break
+		}
+	}
+
+	basePath := cfw.BaseSavePath()
+	for _, folder := range cfw.EmulatorFoldersForFSSlug(config.ResolveFSSlug(game.PlatformFSSlug)) {
+		history.Versions = append(history.Versions, findSaveBackups(filepath.Join(basePath, folder), folder, baseNames)...)
+	}
+
+	var remoteErr error
+	rc := romm.NewClientFromHost(host, config.ApiTimeout)
+	saves, err := rc.GetSaves(romm.SaveQuery{RomID: game.ID})
+	if err != nil {
+		logger.Warn("Failed to fetch remote saves for history", "game", game.Name, "error", err)
+		remoteErr = fmt.Errorf("failed to fetch remote saves: %w", err)
+	}
+
+	for _, save := range saves {
+		savedAt := save.UpdatedAt
+		if t, ok := extractSaveTimestamp(save.FileNameNoExt); ok {
+			savedAt = t
+		}
+		history.Versions = append(history.Versions, SaveVersion{
+			Source:   SaveVersionRemote,
+			Remote:   save,
+			BaseName: extractSaveBaseName(save.FileNameNoExt),
+			Ext:      normalizeExt(save.FileExtension),
+			Emulator: save.Emulator,
+			Size:     save.FileSizeBytes,
+			Time:     savedAt,
+		})
+	}
+
+	slices.SortFunc(history.Versions, func(a, b SaveVersion) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	logger.Debug("Loaded save history", "game", game.Name, "versions", len(history.Versions), "hasCurrent", history.Current != nil)
+	return history, remoteErr
+}
+
+// findSaveBackups lists the backups in the .backup folder of saveDir belonging to one of baseNames.
+func findSaveBackups(saveDir, emulator string, baseNames []string) []SaveVersion {
+	backupDir := filepath.Join(saveDir, ".backup")
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return nil
+	}
+
+	var versions []SaveVersion
+	for _, entry := range fileutil.FilterVisibleFiles(entries) {
+		if isStateFile(entry.Name()) {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		matches := backupNamePattern.FindStringSubmatch(strings.TrimSuffix(entry.Name(), ext))
+		if len(matches) < 3 || !slices.Contains(baseNames, matches[1]) {
+			continue
+		}
+
+		savedAt, err := time.ParseInLocation(backupTimestampFormat, matches[2], time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, SaveVersion{
+			Source:   SaveVersionLocal,
+			Path:     filepath.Join(backupDir, entry.Name()),
+			BaseName: matches[1],
+			Ext:      ext,
+			Emulator: emulator,
+			Size:     info.Size(),
+			Time:     savedAt,
+		})
+	}
+	return versions
+}
+
+// RestoreSaveVersion replaces the current save of a game with an older version, backing
+// up the current save first. The restored file is stamped with the current time so the
+// next sync treats it as the newest save and uploads it instead of overwriting it.
+func RestoreSaveVersion(host romm.Host, config *internal.Config, game romm.Rom, current *LocalSave, version SaveVersion) (string, error) {
+	logger := gaba.GetLogger()
+
+	var destDir string
+	baseName := version.BaseName
+	switch {
+	case current != nil:
+		destDir = filepath.Dir(current.Path)
+		baseName = strings.TrimSuffix(filepath.Base(current.Path), filepath.Ext(current.Path))
+	case version.Source == SaveVersionLocal:
+		destDir = filepath.Dir(filepath.Dir(version.Path))
+	default:
+		dir, err := ResolveSavePath(game.PlatformFSSlug, game.ID, config)
+		if err != nil {
+			return "", fmt.Errorf("cannot determine save location: %w", err)
+		}
+		destDir = dir
+	}
+	destPath := filepath.Join(destDir, baseName+version.Ext)
+
+	if current != nil {
+		if err := current.backup(); err != nil {
+			return "", fmt.Errorf("failed to back up current save: %w", err)
+		}
+	}
+
+	switch version.Source {
+	case SaveVersionLocal:
+		if err := fileutil.CopyFile(version.Path, destPath); err != nil {
+			return "", fmt.Errorf("failed to restore backup: %w", err)
+		}
+	case SaveVersionRemote:
+		rc := romm.NewClientFromHost(host, config.ApiTimeout)
+		data, err := rc.DownloadSave(version.Remote.DownloadPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to download save: %w", err)
+		}
+		if err := os.WriteFile(destPath, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write save file: %w", err)
+		}
+	}
+
+	// A version with a different extension leaves the current save behind, which would
+	// otherwise win the next sync
+	if current != nil && current.Path != destPath {
+		if err := os.Remove(current.Path); err != nil && !os.IsNotExist(err) {
+			logger.Warn("Failed to remove replaced save", "path", current.Path, "error", err)
+		}
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(destPath, now, now); err != nil {
+		logger.Warn("Failed to update restored save timestamp", "path", destPath, "error", err)
+	}
+
+	logger.Debug("Restored save version", "game", game.Name, "source", version.Source, "time", version.Time, "path", destPath)
+	return destPath, nil
+}
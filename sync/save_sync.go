@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"grout/cache"
@@ -28,6 +29,8 @@ type SaveSync struct {
 	Local    *LocalSave
 	Remote   romm.Save
 	Action   Action
+	// Host is the server the save syncs with
+	Host romm.Host
 }
 
 type Action string
@@ -36,6 +39,13 @@ const (
 	Download Action = "DOWNLOAD"
 	Upload   Action = "UPLOAD"
 	Skip     Action = "SKIP"
+	// Conflict is a save changed both locally and in RomM since the last sync. It is left
+	// alone until resolved to Upload, Download or KeepBoth.
+	Conflict Action = "CONFLICT"
+	KeepBoth Action = "KEEP_BOTH"
+	// Excluded is a save that would have synced but whose game or platform is excluded
+	// from save sync. It is reported and never touched.
+	Excluded Action = "EXCLUDED"
 )
 
 type Result struct {
@@ -47,6 +57,7 @@ type Result struct {
 	Err            error
 	FilePath       string
 	UnmatchedSaves []UnmatchedSave
+	StateSlot      string // Set for save states, "0" to "9" or "auto"
 }
 
 type UnmatchedSave struct {
@@ -71,6 +82,7 @@ type PendingFuzzyMatch struct {
 	MatchedRomID  int
 	MatchedName   string
 	Similarity    float64
+	Host          romm.Host
 }
 
 // MatchAttemptResult tracks diagnostic info from ROM matching attempts
@@ -84,7 +96,7 @@ type MatchAttemptResult struct {
 	MatchesAttempted  []string
 }
 
-func (s *SaveSync) Execute(host romm.Host, config *internal.Config) Result {
+func (s *SaveSync) Execute(config *internal.Config) Result {
 	logger := gaba.GetLogger()
 
 	displayName := s.RomName
@@ -105,10 +117,21 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) Result {
 		"romName", s.RomName,
 		"romID", s.RomID)
 
-	var err error
+	switch s.Action {
+	case Skip, Conflict, Excluded:
+		result.Success = true
+		return result
+	}
+
+	cm, release, err := cache.HostCache(s.Host, config)
+	if err != nil {
+		logger.Warn("Unable to open cache of host", "host", s.Host.URL(), "error", err)
+	}
+	defer release()
+
 	switch s.Action {
 	case Upload:
-		result.FilePath, err = s.upload(host, config)
+		result.FilePath, err = s.upload(cm, config)
 		logger.Debug("Upload complete", "filePath", result.FilePath, "err", err)
 	case Download:
 		if s.Local != nil {
@@ -118,10 +141,9 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) Result {
 				return result
 			}
 		}
-		result.FilePath, err = s.download(host, config)
-	case Skip:
-		result.Success = true
-		return result
+		result.FilePath, err = s.download(cm, config)
+	case KeepBoth:
+		result.FilePath, err = s.keepBoth(cm, config)
 	}
 
 	if err != nil {
@@ -134,7 +156,7 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) Result {
 	return result
 }
 
-func (s *SaveSync) download(host romm.Host, config *internal.Config) (string, error) {
+func (s *SaveSync) download(cm *cache.Manager, config *internal.Config) (string, error) {
 	logger := gaba.GetLogger()
 	if config == nil {
 		return "", fmt.Errorf("config is nil")
@@ -142,7 +164,7 @@ func (s *SaveSync) download(host romm.Host, config *internal.Config) (string, er
 	if s.RomID == 0 && s.Local == nil {
 		return "", ErrOrphanRom
 	}
-	rc := romm.NewClientFromHost(host, config.ApiTimeout)
+	rc := romm.NewClientFromHost(s.Host, config.ApiTimeout)
 
 	logger.Debug("Downloading save",
 		"saveID", s.Remote.ID,
@@ -205,10 +227,12 @@ func (s *SaveSync) download(host romm.Host, config *internal.Config) (string, er
 		"mtimeSource", mtimeSource,
 		"remoteUpdatedAt", s.Remote.UpdatedAt)
 
+	recordSyncState(cm, s.RomID, s.GameBase, destPath, s.Remote.ID)
+
 	return destPath, nil
 }
 
-func (s *SaveSync) upload(host romm.Host, config *internal.Config) (string, error) {
+func (s *SaveSync) upload(cm *cache.Manager, config *internal.Config) (string, error) {
 	logger := gaba.GetLogger()
 	if s.Local == nil {
 		return "", fmt.Errorf("cannot upload: no local save file")
@@ -226,7 +250,7 @@ func (s *SaveSync) upload(host romm.Host, config *internal.Config) (string, erro
 		"gameBase", s.GameBase,
 		"fsSlug", s.FSSlug)
 
-	rc := romm.NewClientFromHost(host, config.ApiTimeout)
+	rc := romm.NewClientFromHost(s.Host, config.ApiTimeout)
 
 	ext := normalizeExt(filepath.Ext(s.Local.Path))
 
@@ -265,27 +289,28 @@ func (s *SaveSync) upload(host romm.Host, config *internal.Config) (string, erro
 	// Don't modify local mtime after upload - the uploaded filename contains
 	// the original mtime, so keeping the local file unchanged ensures the
 	// next sync comparison will match and skip.
+	recordSyncState(cm, s.RomID, s.GameBase, s.Local.Path, uploadedSave.ID)
 
 	return s.Local.Path, nil
 }
 
-func lookupRomID(romFile *LocalRomFile) (int, string) {
+func lookupRomID(cm *cache.Manager, romFile *LocalRomFile) (int, string) {
 	// Look up from the games cache
-	if romID, romName, found := cache.GetCachedRomIDByFilename(romFile.FSSlug, romFile.FileName); found {
+	if romID, romName, found := cm.GetRomIDByFilename(romFile.FSSlug, romFile.FileName); found {
 		return romID, romName
 	}
 
 	return 0, ""
 }
 
-func lookupRomByHash(rc *romm.Client, romFile *LocalRomFile, matchResult *MatchAttemptResult) (int, string) {
+func lookupRomByHash(ctx context.Context, cm *cache.Manager, rc *romm.Client, romFile *LocalRomFile, matchResult *MatchAttemptResult) (int, string) {
 	logger := gaba.GetLogger()
 
 	if romFile.FilePath == "" {
 		return 0, ""
 	}
 
-	shouldAttempt, nextRetry := cache.ShouldAttemptLookupWithNextRetry(romFile.FSSlug, romFile.FileName)
+	shouldAttempt, nextRetry := cm.ShouldAttemptLookupWithNextRetry(romFile.FSSlug, romFile.FileName)
 	if !shouldAttempt {
 		if matchResult != nil {
 			matchResult.CooldownActive = true
@@ -308,22 +333,22 @@ func lookupRomByHash(rc *romm.Client, romFile *LocalRomFile, matchResult *MatchA
 		matchResult.CRC32Hash = crcHash
 	}
 
-	rom, err := rc.GetRomByHash(romm.GetRomByHashQuery{CrcHash: crcHash})
+	rom, err := rc.GetRomByHashContext(ctx, romm.GetRomByHashQuery{CrcHash: crcHash})
 	if err == nil && rom.ID > 0 {
 		logger.Info("Found ROM by CRC32 hash",
 			"file", romFile.FileName,
 			"crc", crcHash,
 			"romID", rom.ID,
 			"romName", rom.Name)
-		_ = cache.SaveFilenameMapping(romFile.FSSlug, romFile.FileName, rom.ID, rom.Name)
-		_ = cache.ClearFailedLookup(romFile.FSSlug, romFile.FileName)
+		_ = cm.SaveFilenameMapping(romFile.FSSlug, romFile.FileName, rom.ID, rom.Name)
+		_ = cm.ClearFailedLookup(romFile.FSSlug, romFile.FileName)
 		return rom.ID, rom.Name
 	}
 
 	sha1Hash, err := fileutil.ComputeSHA1(romFile.FilePath)
 	if err != nil {
 		logger.Debug("Failed to compute SHA1 hash", "file", romFile.FileName, "error", err)
-		_ = cache.RecordFailedLookup(romFile.FSSlug, romFile.FileName)
+		_ = cm.RecordFailedLookup(romFile.FSSlug, romFile.FileName)
 		return 0, ""
 	}
 
@@ -331,15 +356,15 @@ func lookupRomByHash(rc *romm.Client, romFile *LocalRomFile, matchResult *MatchA
 		matchResult.SHA1Hash = sha1Hash
 	}
 
-	rom, err = rc.GetRomByHash(romm.GetRomByHashQuery{Sha1Hash: sha1Hash})
+	rom, err = rc.GetRomByHashContext(ctx, romm.GetRomByHashQuery{Sha1Hash: sha1Hash})
 	if err == nil && rom.ID > 0 {
 		logger.Info("Found ROM by SHA1 hash",
 			"file", romFile.FileName,
 			"sha1", sha1Hash,
 			"romID", rom.ID,
 			"romName", rom.Name)
-		_ = cache.SaveFilenameMapping(romFile.FSSlug, romFile.FileName, rom.ID, rom.Name)
-		_ = cache.ClearFailedLookup(romFile.FSSlug, romFile.FileName)
+		_ = cm.SaveFilenameMapping(romFile.FSSlug, romFile.FileName, rom.ID, rom.Name)
+		_ = cm.ClearFailedLookup(romFile.FSSlug, romFile.FileName)
 		return rom.ID, rom.Name
 	}
 
@@ -349,7 +374,7 @@ func lookupRomByHash(rc *romm.Client, romFile *LocalRomFile, matchResult *MatchA
 
 const FuzzyMatchThreshold = 0.80
 
-func lookupRomByFuzzyTitle(romFile *LocalRomFile, matchResult *MatchAttemptResult) *PendingFuzzyMatch {
+func lookupRomByFuzzyTitle(cm *cache.Manager, romFile *LocalRomFile, matchResult *MatchAttemptResult) *PendingFuzzyMatch {
 	logger := gaba.GetLogger()
 
 	if romFile.FSSlug == "" || romFile.FileName == "" {
@@ -360,7 +385,7 @@ func lookupRomByFuzzyTitle(romFile *LocalRomFile, matchResult *MatchAttemptResul
 		matchResult.MatchesAttempted = append(matchResult.MatchesAttempted, "fuzzy")
 	}
 
-	games, err := cache.GetGamesForPlatform(romFile.FSSlug)
+	games, err := cm.GetGamesForPlatform(romFile.FSSlug)
 	if err != nil || len(games) == 0 {
 		return nil
 	}
@@ -418,37 +443,61 @@ func lookupRomByFuzzyTitle(romFile *LocalRomFile, matchResult *MatchAttemptResul
 	return bestMatch
 }
 
-func FindSaveSyncs(host romm.Host, config *internal.Config) ([]SaveSync, []UnmatchedSave, []PendingFuzzyMatch, error) {
-	return FindSaveSyncsFromScan(host, config, ScanRoms(config))
+func FindSaveSyncs(ctx context.Context, host romm.Host, config *internal.Config) ([]SaveSync, []UnmatchedSave, []PendingFuzzyMatch, error) {
+	return FindSaveSyncsFromScan(ctx, host, config, ScanRoms(config))
 }
 
-func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal LocalRomScan) ([]SaveSync, []UnmatchedSave, []PendingFuzzyMatch, error) {
-	logger := gaba.GetLogger()
+// FindSaveSyncsFromScan works out what to do with the saves of the scanned ROMs. Games
+// downloaded from another server than host are synced with that server, using its cache. If
+// ctx is done before it finishes, the requests in flight are aborted and ctx's error returned.
+func FindSaveSyncsFromScan(ctx context.Context, host romm.Host, config *internal.Config, scanLocal LocalRomScan) ([]SaveSync, []UnmatchedSave, []PendingFuzzyMatch, error) {
 	if config == nil {
 		return nil, nil, nil, fmt.Errorf("config is nil")
 	}
-	rc := romm.NewClientFromHost(host, config.ApiTimeout)
 
-	logger.Debug("FindSaveSyncs: Scanned local ROMs", "platformCount", len(scanLocal))
+	var syncs []SaveSync
+	var unmatched []UnmatchedSave
+	var pendingFuzzy []PendingFuzzyMatch
 
-	cm := cache.GetCacheManager()
-	var platforms []romm.Platform
-	var err error
+	for i, group := range splitScanByHost(host, config, scanLocal) {
+		var groupSyncs []SaveSync
+		var groupUnmatched []UnmatchedSave
+		var groupFuzzy []PendingFuzzyMatch
+		var err error
 
-	if cm != nil {
-		platforms, err = cm.GetPlatforms()
-	}
-	if err != nil || len(platforms) == 0 {
-		platforms, err = rc.GetPlatforms()
+		withHostCache(group.host, config, func(cm *cache.Manager) {
+			groupSyncs, groupUnmatched, groupFuzzy, err = findHostSaveSyncs(ctx, group.host, cm, config, group.scan)
+		})
+		group.copyBack(scanLocal)
+
+		// Another server being unreachable doesn't hold up the saves of the one browsed
+		if err != nil && (i == 0 || ctx.Err() != nil) {
+			return nil, nil, nil, err
+		}
 		if err != nil {
-			logger.Error("FindSaveSyncs: Could not retrieve platforms", "error", err)
-			return []SaveSync{}, nil, nil, err
+			gaba.GetLogger().Warn("FindSaveSyncs: Could not sync saves with host", "host", group.host.URL(), "error", err)
+			continue
 		}
+
+		syncs = append(syncs, groupSyncs...)
+		unmatched = append(unmatched, groupUnmatched...)
+		pendingFuzzy = append(pendingFuzzy, groupFuzzy...)
 	}
 
-	fsSlugToPlatformID := make(map[string]int)
-	for _, p := range platforms {
-		fsSlugToPlatformID[p.FSSlug] = p.ID
+	return syncs, unmatched, pendingFuzzy, nil
+}
+
+// findHostSaveSyncs works out what to do with the saves of scanned ROMs that sync with host.
+func findHostSaveSyncs(ctx context.Context, host romm.Host, cm *cache.Manager, config *internal.Config, scanLocal LocalRomScan) ([]SaveSync, []UnmatchedSave, []PendingFuzzyMatch, error) {
+	logger := gaba.GetLogger()
+	rc := romm.NewClientFromHost(host, config.ApiTimeout)
+
+	logger.Debug("FindSaveSyncs: Scanned local ROMs", "platformCount", len(scanLocal))
+
+	fsSlugToPlatformID, err := platformIDsByFSSlug(ctx, cm, rc)
+	if err != nil {
+		logger.Error("FindSaveSyncs: Could not retrieve platforms", "error", err)
+		return []SaveSync{}, nil, nil, err
 	}
 
 	type platformFetchResult struct {
@@ -481,7 +530,7 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 				fsSlug: fsSlug,
 			}
 
-			platformSaves, err := rc.GetSaves(romm.SaveQuery{PlatformID: platformID})
+			platformSaves, err := rc.GetSavesContext(ctx, romm.SaveQuery{PlatformID: platformID})
 			if err != nil {
 				logger.Warn("FindSaveSyncs: Could not retrieve saves for platform", "fsSlug", fsSlug, "error", err)
 				result.hasError = true
@@ -511,6 +560,11 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 		}
 	}
 
+	// Saves missing from a cancelled fetch would look like they need uploading
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
 	var unmatched []UnmatchedSave
 	var pendingFuzzy []PendingFuzzyMatch
 	for fsSlug, localRoms := range scanLocal {
@@ -521,23 +575,34 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 				continue
 			}
 
+			platformExcluded := config.IsSyncExcluded(fsSlug, 0)
+
 			// Track match attempts for diagnostics
 			matchResult := &MatchAttemptResult{}
 
-			romID, romName := lookupRomID(romFile)
+			romID, romName := lookupRomID(cm, romFile)
 			if romID > 0 {
 				matchResult.MatchesAttempted = append(matchResult.MatchesAttempted, "filename")
 			}
 
+			// Hash and fuzzy lookups are only worth it for saves that will be synced
+			if romID == 0 && platformExcluded {
+				continue
+			}
+
 			if romID == 0 && romFile.SaveFile != nil {
 				matchResult.MatchesAttempted = append(matchResult.MatchesAttempted, "filename")
-				romID, romName = lookupRomByHash(rc, romFile, matchResult)
+				romID, romName = lookupRomByHash(ctx, cm, rc, romFile, matchResult)
+				if err := ctx.Err(); err != nil {
+					return nil, nil, nil, err
+				}
 			}
 
 			if romID == 0 && romFile.SaveFile != nil {
-				fuzzyMatch := lookupRomByFuzzyTitle(romFile, matchResult)
+				fuzzyMatch := lookupRomByFuzzyTitle(cm, romFile, matchResult)
 				if fuzzyMatch != nil {
 					fuzzyMatch.SavePath = romFile.SaveFile.Path
+					fuzzyMatch.Host = host
 					pendingFuzzy = append(pendingFuzzy, *fuzzyMatch)
 					romFile.PendingFuzzyMatch = true // Mark to skip in sync building
 					logger.Info("Fuzzy match candidate found",
@@ -545,7 +610,7 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 						"matched", fuzzyMatch.MatchedName,
 						"similarity", fmt.Sprintf("%.0f%%", fuzzyMatch.Similarity*100))
 				} else {
-					if err := cache.RecordFailedLookup(romFile.FSSlug, romFile.FileName); err != nil {
+					if err := cm.RecordFailedLookup(romFile.FSSlug, romFile.FileName); err != nil {
 						logger.Warn("Failed to record failed lookup",
 							"file", romFile.FileName,
 							"fsSlug", romFile.FSSlug,
@@ -629,9 +694,21 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 				"romID", r.RomID,
 				"hasLocalSave", r.SaveFile != nil,
 				"remoteSaveCount", len(r.RemoteSaves))
-			action := r.syncAction()
-			if action == Upload || action == Download {
-				baseName := strings.TrimSuffix(r.FileName, filepath.Ext(r.FileName))
+			action := r.syncAction(cm)
+			baseName := strings.TrimSuffix(r.FileName, filepath.Ext(r.FileName))
+
+			// Saves already in step with RomM become the baseline for spotting conflicts later
+			if action == Skip && r.SaveFile != nil && len(r.RemoteSaves) > 0 {
+				if _, found, _ := cm.GetSaveSyncState(r.RomID, baseName); !found {
+					recordSyncState(cm, r.RomID, baseName, r.SaveFile.Path, r.lastRemoteSaveForBaseName(baseName).ID)
+				}
+			}
+
+			if action == Upload || action == Download || action == Conflict {
+				if config.IsSyncExcluded(fsSlug, r.RomID) {
+					logger.Debug("Save sync excluded", "romName", r.RomName, "romID", r.RomID, "fsSlug", fsSlug, "action", action)
+					action = Excluded
+				}
 
 				var key string
 				if r.SaveFile != nil {
@@ -652,6 +729,7 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 					Local:    r.SaveFile,
 					Remote:   r.lastRemoteSaveForBaseName(baseName),
 					Action:   action,
+					Host:     host,
 				}
 			}
 		}
@@ -696,6 +774,23 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 	return syncs, unmatched, pendingFuzzy, nil
 }
 
+// platformIDsByFSSlug maps fs_slugs to RomM platform IDs, from the cache if it has been populated.
+func platformIDsByFSSlug(ctx context.Context, cm *cache.Manager, rc *romm.Client) (map[string]int, error) {
+	platforms, err := cm.GetPlatforms()
+	if err != nil || len(platforms) == 0 {
+		platforms, err = rc.GetPlatformsContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fsSlugToPlatformID := make(map[string]int, len(platforms))
+	for _, p := range platforms {
+		fsSlugToPlatformID[p.FSSlug] = p.ID
+	}
+	return fsSlugToPlatformID, nil
+}
+
 func normalizeExt(ext string) string {
 	if ext != "" && !strings.HasPrefix(ext, ".") {
 		return "." + ext
@@ -24,20 +24,19 @@ var validSaveExtensions = map[string]bool{
 	".sav":  true,
 	// Nintendo DS
 	"dsv": true,
-	// Save states
-	".state": true,
-	".sta":   true,
-	".ss":    true,
-	".ss0":   true,
-	".ss1":   true,
-	".ss2":   true,
-	".ss3":   true,
-	".ss4":   true,
-	".ss5":   true,
-	".ss6":   true,
-	".ss7":   true,
-	".ss8":   true,
-	".ss9":   true,
+	// Standalone emulator states. RetroArch states are synced separately, see states.go
+	".sta": true,
+	".ss":  true,
+	".ss0": true,
+	".ss1": true,
+	".ss2": true,
+	".ss3": true,
+	".ss4": true,
+	".ss5": true,
+	".ss6": true,
+	".ss7": true,
+	".ss8": true,
+	".ss9": true,
 	// EEPROM/Flash/N64
 	".eep": true,
 	".fla": true,
@@ -50,8 +49,6 @@ var validSaveExtensions = map[string]bool{
 	".vmp": true,
 	// Real-time clock
 	".rtc": true,
-	// RetroArch
-	".auto": true,
 }
 
 type LocalSave struct {
@@ -198,6 +195,10 @@ func findSaveFiles(fsSlug string, config *internal.Config) []LocalSave {
 				if cfw.GetCFW() == cfw.ROCKNIX && !validSaveExtensions[ext] {
 					continue
 				}
+				// Some CFWs keep states next to the saves; they are synced on their own
+				if isStateFile(entry.Name()) {
+					continue
+				}
 
 				savePath := filepath.Join(sd, entry.Name())
 
@@ -0,0 +1,468 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	gosync "sync"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// stateFilePattern matches save state files and splits off the slot suffix
+// RetroArch: "Game.state", "Game.state1" ... "Game.state9", "Game.state.auto"
+// NextUI:    "Game.gba.st0" ... "Game.gba.st9"
+// Group 1: base name, Group 2: slot suffix
+var stateFilePattern = regexp.MustCompile(`^(.+?)(\.state(?:\d+|\.auto)?|\.st\d)$`)
+
+// remoteStatePattern splits the name of a state uploaded by Grout into its base name,
+// timestamp and slot suffix, e.g. "Game [2024-01-02 15-04-05-000Z].state1"
+var remoteStatePattern = regexp.MustCompile(`^(.+ \[\d{4}-\d{2}-\d{2} \d{2}-\d{2}-\d{2}-\d{3}Z?\])(\..+)$`)
+
+// stateThumbnailExt is the extension RetroArch appends to a state for its thumbnail
+const stateThumbnailExt = ".png"
+
+// LocalState is a save state on the device, one per slot.
+type LocalState struct {
+	FSSlug       string
+	Path         string
+	BaseName     string
+	Slot         string // Slot suffix, e.g. ".state1"
+	LastModified time.Time
+}
+
+// Thumbnail returns the screenshot the emulator took of the state, or an empty string.
+func (ls LocalState) Thumbnail() string {
+	path := ls.Path + stateThumbnailExt
+	if fileutil.FileExists(path) {
+		return path
+	}
+	return ""
+}
+
+func (ls LocalState) backup() error {
+	lm := ls.LastModified.Format(backupTimestampFormat)
+	dest := filepath.Join(filepath.Dir(ls.Path), ".backup", fmt.Sprintf("%s [%s]%s", ls.BaseName, lm, ls.Slot))
+	return fileutil.CopyFile(ls.Path, dest)
+}
+
+// isStateFile reports whether name is a save state or a state thumbnail rather than a save.
+func isStateFile(name string) bool {
+	return stateFilePattern.MatchString(strings.TrimSuffix(name, stateThumbnailExt))
+}
+
+// StateSlotLabel turns a slot suffix into the slot shown to the user: "0" to "9" or "auto".
+func StateSlotLabel(slot string) string {
+	switch {
+	case slot == ".state":
+		return "0"
+	case strings.HasSuffix(slot, ".auto"):
+		return "auto"
+	case strings.HasPrefix(slot, ".state"):
+		return strings.TrimPrefix(slot, ".state")
+	default:
+		return strings.TrimPrefix(slot, ".st")
+	}
+}
+
+type StateSync struct {
+	RomID    int
+	RomName  string
+	FSSlug   string
+	BaseName string
+	Slot     string
+	Local    *LocalState
+	Remote   romm.State
+	Action   Action
+	// Host is the server the save state syncs with
+	Host romm.Host
+}
+
+// RemoteTime returns when the RomM state was made, falling back to when it was uploaded.
+func (s StateSync) RemoteTime() time.Time {
+	if matches := remoteStatePattern.FindStringSubmatch(s.Remote.FileName); len(matches) == 3 {
+		if t, ok := extractSaveTimestamp(matches[1]); ok {
+			return t
+		}
+	}
+	return s.Remote.UpdatedAt
+}
+
+// syncKey is what the slot's last sync is recorded under: its file name, which never
+// matches the base name a save is recorded under.
+func (s StateSync) syncKey() string {
+	return s.BaseName + s.Slot
+}
+
+func (s *StateSync) Execute(config *internal.Config) Result {
+	logger := gaba.GetLogger()
+
+	result := Result{
+		GameName:       s.BaseName,
+		RomDisplayName: strings.TrimSuffix(s.RomName, filepath.Ext(s.RomName)),
+		Action:         s.Action,
+		StateSlot:      StateSlotLabel(s.Slot),
+	}
+
+	if s.Action != Upload && s.Action != Download {
+		result.Success = true
+		return result
+	}
+
+	cm, release, err := cache.HostCache(s.Host, config)
+	if err != nil {
+		logger.Warn("Unable to open cache of host", "host", s.Host.URL(), "error", err)
+	}
+	defer release()
+
+	switch s.Action {
+	case Upload:
+		result.FilePath, err = s.upload(cm, config)
+	case Download:
+		if s.Local != nil {
+			if err := s.Local.backup(); err != nil {
+				result.Err = err
+				result.Error = err.Error()
+				return result
+			}
+		}
+		result.FilePath, err = s.download(cm, config)
+	}
+
+	if err != nil {
+		logger.Error("Unable to sync save state", "game", s.BaseName, "slot", s.Slot, "error", err)
+		result.Err = err
+		result.Error = err.Error()
+	} else {
+		result.Success = true
+	}
+
+	return result
+}
+
+func (s *StateSync) upload(cm *cache.Manager, config *internal.Config) (string, error) {
+	if s.Local == nil {
+		return "", fmt.Errorf("cannot upload: no local save state")
+	}
+
+	info, err := os.Stat(s.Local.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file info: %w", err)
+	}
+	timestamp := info.ModTime().UTC().Format("[2006-01-02 15-04-05-000Z]")
+
+	tmp := filepath.Join(fileutil.TempDir(), "uploads", s.BaseName+" "+timestamp+s.Slot)
+	if err := fileutil.CopyFile(s.Local.Path, tmp); err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	emulator := filepath.Base(filepath.Dir(s.Local.Path))
+	rc := romm.NewClientFromHost(s.Host, config.ApiTimeout)
+	uploaded, err := rc.UploadState(s.RomID, tmp, s.Local.Thumbnail(), emulator)
+	if err != nil {
+		return "", err
+	}
+
+	gaba.GetLogger().Debug("Save state uploaded", "stateID", uploaded.ID, "path", s.Local.Path, "hasScreenshot", uploaded.Screenshot != nil)
+	recordSyncState(cm, s.RomID, s.syncKey(), s.Local.Path, uploaded.ID)
+	return s.Local.Path, nil
+}
+
+func (s *StateSync) download(cm *cache.Manager, config *internal.Config) (string, error) {
+	logger := gaba.GetLogger()
+
+	var destDir string
+	if s.Local != nil {
+		destDir = filepath.Dir(s.Local.Path)
+	} else {
+		saveDir, err := ResolveSavePath(s.FSSlug, s.RomID, config)
+		if err != nil {
+			return "", fmt.Errorf("cannot determine state location: %w", err)
+		}
+		emulatorFolder, err := filepath.Rel(cfw.BaseSavePath(), saveDir)
+		if err == nil {
+			destDir = cfw.StateDirectory(emulatorFolder)
+		}
+		if destDir == "" {
+			return "", fmt.Errorf("no state folder for %s", s.FSSlug)
+		}
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create state directory: %w", err)
+		}
+	}
+
+	rc := romm.NewClientFromHost(s.Host, config.ApiTimeout)
+	data, err := rc.DownloadState(s.Remote.DownloadPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to download save state: %w", err)
+	}
+
+	destPath := filepath.Join(destDir, s.BaseName+s.Slot)
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write save state: %w", err)
+	}
+
+	remoteTime := s.RemoteTime()
+	if err := os.Chtimes(destPath, remoteTime, remoteTime); err != nil {
+		return "", fmt.Errorf("failed to update file timestamp: %w", err)
+	}
+
+	recordSyncState(cm, s.RomID, s.syncKey(), destPath, s.Remote.ID)
+
+	// Only RetroArch shows thumbnails next to its states
+	if s.Remote.Screenshot != nil && s.Remote.Screenshot.DownloadPath != "" && strings.HasPrefix(s.Slot, ".state") {
+		if thumbnail, err := rc.DownloadScreenshot(s.Remote.Screenshot.DownloadPath); err != nil {
+			logger.Warn("Failed to download state thumbnail", "game", s.BaseName, "error", err)
+		} else if err := os.WriteFile(destPath+stateThumbnailExt, thumbnail, 0644); err != nil {
+			logger.Warn("Failed to write state thumbnail", "path", destPath+stateThumbnailExt, "error", err)
+		}
+	}
+
+	logger.Debug("Downloaded save state", "path", destPath, "slot", s.Slot)
+	return destPath, nil
+}
+
+// findStateFiles lists the save states in every state folder of a platform.
+func findStateFiles(fsSlug string, config *internal.Config) []LocalState {
+	effectiveFSSlug := fsSlug
+	if config != nil {
+		effectiveFSSlug = config.ResolveFSSlug(fsSlug)
+	}
+
+	var states []LocalState
+	seen := make(map[string]bool)
+
+	for _, folder := range cfw.EmulatorFoldersForFSSlug(effectiveFSSlug) {
+		dir := cfw.StateDirectory(folder)
+		if dir == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range fileutil.FilterVisibleFiles(entries) {
+			matches := stateFilePattern.FindStringSubmatch(entry.Name())
+			if len(matches) < 3 {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			states = append(states, LocalState{
+				FSSlug:       fsSlug,
+				Path:         filepath.Join(dir, entry.Name()),
+				BaseName:     matches[1],
+				Slot:         matches[2],
+				LastModified: info.ModTime(),
+			})
+		}
+	}
+
+	return states
+}
+
+// FindStateSyncs works out which save states to upload or download for the ROMs in scan.
+// Each slot is synced on its own. Like saves, a slot synced before goes by which side
+// changed since and is a Conflict if both did, otherwise the most recent copy wins. A local
+// state about to be replaced is backed up first. Like saves, the states of games downloaded from another
+// server than host are synced with that server.
+func FindStateSyncs(ctx context.Context, host romm.Host, config *internal.Config, scan LocalRomScan) ([]StateSync, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+
+	var syncs []StateSync
+	for i, group := range splitScanByHost(host, config, scan) {
+		var groupSyncs []StateSync
+		var err error
+
+		withHostCache(group.host, config, func(cm *cache.Manager) {
+			groupSyncs, err = findHostStateSyncs(ctx, group.host, cm, config, group.scan)
+		})
+
+		if err != nil && (i == 0 || ctx.Err() != nil) {
+			return nil, err
+		}
+		if err != nil {
+			gaba.GetLogger().Warn("FindStateSyncs: Could not sync save states with host", "host", group.host.URL(), "error", err)
+			continue
+		}
+		syncs = append(syncs, groupSyncs...)
+	}
+
+	gaba.GetLogger().Debug("FindStateSyncs: Found state syncs", "count", len(syncs))
+	return syncs, nil
+}
+
+// findHostStateSyncs works out which save states of scanned ROMs that sync with host to
+// upload or download.
+func findHostStateSyncs(ctx context.Context, host romm.Host, cm *cache.Manager, config *internal.Config, scan LocalRomScan) ([]StateSync, error) {
+	logger := gaba.GetLogger()
+	rc := romm.NewClientFromHost(host, config.ApiTimeout)
+
+	fsSlugToPlatformID, err := platformIDsByFSSlug(ctx, cm, rc)
+	if err != nil {
+		return nil, err
+	}
+
+	type platformStates struct {
+		fsSlug string
+		states []romm.State
+	}
+
+	resultChan := make(chan platformStates, len(scan))
+	var wg gosync.WaitGroup
+
+	for fsSlug := range scan {
+		var platformID int
+		for _, alias := range cfw.GetPlatformAliases(fsSlug) {
+			if id, ok := fsSlugToPlatformID[alias]; ok {
+				platformID = id
+				break
+			}
+		}
+		if platformID == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(fsSlug string, platformID int) {
+			defer wg.Done()
+			states, err := rc.GetStatesContext(ctx, romm.StateQuery{PlatformID: platformID})
+			if err != nil {
+				logger.Warn("FindStateSyncs: Could not retrieve states for platform", "fsSlug", fsSlug, "error", err)
+				return
+			}
+			resultChan <- platformStates{fsSlug: fsSlug, states: states}
+		}(fsSlug, platformID)
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	statesByRomID := make(map[int][]romm.State)
+	for result := range resultChan {
+		for _, st := range result.states {
+			statesByRomID[st.RomID] = append(statesByRomID[st.RomID], st)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var syncs []StateSync
+	for fsSlug, roms := range scan {
+		localStates := findStateFiles(fsSlug, config)
+
+		for i := range roms {
+			rom := &roms[i]
+			romID, romName := rom.RomID, rom.RomName
+			if romID == 0 {
+				romID, romName = lookupRomID(cm, rom)
+			}
+			if romID == 0 || config.IsSyncExcluded(fsSlug, romID) {
+				continue
+			}
+
+			slots := make(map[string]*StateSync)
+			baseName := rom.baseName()
+
+			for j := range localStates {
+				ls := &localStates[j]
+				// NextUI names states after the full ROM file name
+				if ls.BaseName != baseName && ls.BaseName != rom.FileName {
+					continue
+				}
+				slots[ls.Slot] = &StateSync{RomID: romID, RomName: romName, FSSlug: fsSlug, BaseName: ls.BaseName, Slot: ls.Slot, Local: ls, Host: host}
+			}
+
+			for _, remote := range statesByRomID[romID] {
+				matches := remoteStatePattern.FindStringSubmatch(remote.FileName)
+				if len(matches) < 3 {
+					continue
+				}
+				remoteBase := extractSaveBaseName(matches[1])
+				if remoteBase != baseName && remoteBase != rom.FileName {
+					continue
+				}
+
+				slot := matches[2]
+				candidate := StateSync{Remote: remote}
+				existing, ok := slots[slot]
+				if !ok {
+					slots[slot] = &StateSync{RomID: romID, RomName: romName, FSSlug: fsSlug, BaseName: remoteBase, Slot: slot, Remote: remote, Host: host}
+					continue
+				}
+				if existing.Remote.ID == 0 || candidate.RemoteTime().After(existing.RemoteTime()) {
+					existing.Remote = remote
+				}
+			}
+
+			for _, s := range slots {
+				s.Action = s.syncAction(cm)
+				// States already in step with RomM become the baseline for spotting conflicts later
+				if s.Action == Skip && s.Local != nil && s.Remote.ID != 0 {
+					if _, found, _ := cm.GetSaveSyncState(romID, s.syncKey()); !found {
+						recordSyncState(cm, romID, s.syncKey(), s.Local.Path, s.Remote.ID)
+					}
+				}
+				if s.Action != Skip {
+					syncs = append(syncs, *s)
+				}
+			}
+		}
+	}
+
+	return syncs, nil
+}
+
+func (s StateSync) syncAction(cm *cache.Manager) Action {
+	switch {
+	case s.Local == nil && s.Remote.ID == 0:
+		return Skip
+	case s.Remote.ID == 0:
+		return Upload
+	case s.Local == nil:
+		return Download
+	}
+
+	// Both sides have the slot - if it has been synced before, check which side changed since
+	if state, found, _ := cm.GetSaveSyncState(s.RomID, s.syncKey()); found {
+		if action, ok := compareWithLastSync(state, s.Local.Path, s.Local.LastModified, s.Remote.ID); ok {
+			return action
+		}
+	}
+
+	// Never synced from this device - the most recent copy wins
+
+	localTime := s.Local.LastModified.Truncate(time.Second)
+	remoteTime := s.RemoteTime().Truncate(time.Second)
+
+	switch localTime.Compare(remoteTime) {
+	case 1:
+		return Upload
+	case -1:
+		return Download
+	default:
+		return Skip
+	}
+}
@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// PlatformStorage is the space a single mapped platform takes up on the device.
+type PlatformStorage struct {
+	FSSlug  string
+	Name    string
+	Roms    int64
+	BoxArt  int64
+	Saves   int64
+	Artwork int64 // Grout's own artwork cache, not the box art shown by the CFW
+}
+
+func (p PlatformStorage) Total() int64 {
+	return p.Roms + p.BoxArt + p.Saves + p.Artwork
+}
+
+// StorageUsage summarises what Grout manages on the device.
+type StorageUsage struct {
+	Platforms []PlatformStorage
+	BIOS      int64
+	Artwork   int64
+	Cache     int64 // Everything under the cache directory, artwork included
+	Disk      fileutil.DiskSpace
+}
+
+// CalculateStorageUsage measures the ROM, box art, save and artwork cache folders of every
+// mapped platform along with the BIOS folder and the free space left on the ROM card.
+func CalculateStorageUsage(config *internal.Config) StorageUsage {
+	logger := gaba.GetLogger()
+	usage := StorageUsage{}
+
+	names := make(map[string]string)
+	if cm := cache.GetCacheManager(); cm != nil {
+		if platforms, err := cm.GetPlatforms(); err == nil {
+			for _, p := range platforms {
+				names[p.FSSlug] = p.Name
+			}
+		}
+	}
+
+	for fsSlug := range config.DirectoryMappings {
+		platform := romm.Platform{FSSlug: fsSlug, Name: names[fsSlug]}
+		if platform.Name == "" {
+			platform.Name = fsSlug
+		}
+
+		romDir := config.GetPlatformRomDirectory(platform)
+		artDir := config.GetArtDirectory(platform)
+
+		ps := PlatformStorage{
+			FSSlug:  fsSlug,
+			Name:    platform.Name,
+			Roms:    fileutil.DirSize(romDir),
+			Artwork: fileutil.DirSize(filepath.Join(cache.GetArtworkCacheDir(), fsSlug)),
+		}
+
+		if artDir != "" {
+			ps.BoxArt = fileutil.DirSize(artDir)
+			// Some CFWs keep box art in a hidden folder inside the ROM folder
+			if strings.HasPrefix(artDir, romDir+string(filepath.Separator)) {
+				ps.Roms -= ps.BoxArt
+			}
+		}
+
+		for _, save := range findSaveFiles(fsSlug, config) {
+			if info, err := os.Stat(save.Path); err == nil {
+				ps.Saves += info.Size()
+			}
+		}
+
+		usage.Platforms = append(usage.Platforms, ps)
+	}
+
+	slices.SortFunc(usage.Platforms, func(a, b PlatformStorage) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	if biosDir := cfw.GetBIOSDirectory(); biosDir != "" {
+		usage.BIOS = fileutil.DirSize(biosDir)
+	}
+	usage.Artwork = fileutil.DirSize(cache.GetArtworkCacheDir())
+	usage.Cache = fileutil.DirSize(cache.GetCacheDir())
+
+	disk, err := fileutil.GetDiskSpace(cfw.GetRomDirectory())
+	if err != nil {
+		logger.Warn("Failed to read free space", "error", err)
+	}
+	usage.Disk = disk
+
+	logger.Debug("Calculated storage usage", "platforms", len(usage.Platforms), "free", disk.Free)
+	return usage
+}
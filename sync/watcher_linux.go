@@ -0,0 +1,92 @@
+//go:build linux
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// saveWatchMask catches saves written in place as well as ones written to a temporary
+// file and renamed over the old save, which is how most emulators write them.
+const saveWatchMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO
+
+// saveWatcher reports files written to a set of directories using inotify.
+// Subdirectories, such as the .backup folders, are not watched.
+type saveWatcher struct {
+	// fd adds the watches, as file.Fd would switch the file to blocking mode and take it off
+	// the runtime poller, so closing it would no longer stop a read in progress.
+	fd   int
+	file *os.File
+	dirs map[int32]string
+}
+
+func newSaveWatcher() (*saveWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+
+	return &saveWatcher{
+		fd:   fd,
+		file: os.NewFile(uintptr(fd), "inotify"),
+		dirs: make(map[int32]string),
+	}, nil
+}
+
+func (w *saveWatcher) add(dir string) error {
+	wd, err := syscall.InotifyAddWatch(w.fd, dir, saveWatchMask)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	w.dirs[int32(wd)] = dir
+	return nil
+}
+
+// run calls changed with the path of every file written until ctx is cancelled.
+func (w *saveWatcher) run(ctx context.Context, changed func(path string)) error {
+	go func() {
+		<-ctx.Done()
+		w.file.Close()
+	}()
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, os.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to read inotify events: %w", err)
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			nameEnd := nameStart + int(event.Len)
+			offset = nameEnd
+
+			if event.Mask&syscall.IN_Q_OVERFLOW != 0 || event.Len == 0 || nameEnd > n {
+				continue
+			}
+
+			dir, ok := w.dirs[event.Wd]
+			if !ok || event.Mask&syscall.IN_ISDIR != 0 {
+				continue
+			}
+
+			name := string(bytes.TrimRight(buf[nameStart:nameEnd], "\x00"))
+			changed(filepath.Join(dir, name))
+		}
+	}
+}
+
+func (w *saveWatcher) close() error {
+	return w.file.Close()
+}
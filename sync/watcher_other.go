@@ -0,0 +1,21 @@
+//go:build !linux
+
+package sync
+
+import (
+	"context"
+	"errors"
+)
+
+// saveWatcher is only implemented on Linux, where inotify is available.
+type saveWatcher struct{}
+
+func newSaveWatcher() (*saveWatcher, error) {
+	return nil, errors.New("watching save folders is only supported on Linux")
+}
+
+func (w *saveWatcher) add(dir string) error { return nil }
+
+func (w *saveWatcher) run(ctx context.Context, changed func(path string)) error { return nil }
+
+func (w *saveWatcher) close() error { return nil }
@@ -10,6 +10,10 @@ const (
 	PlatformSelectionActionCollections
 	PlatformSelectionActionSettings
 	PlatformSelectionActionSaveSync
+	PlatformSelectionActionDownloadQueue
+	PlatformSelectionActionHosts
+	PlatformSelectionActionDownloadMissing
+	PlatformSelectionActionRecentlyPlayed
 	PlatformSelectionActionQuit
 )
 
@@ -22,6 +26,7 @@ const (
 	GameListActionBack
 	GameListActionClearSearch
 	GameListActionFilters
+	GameListActionCollections
 )
 
 type GameDetailsAction int
@@ -37,6 +42,8 @@ type GameOptionsAction int
 const (
 	GameOptionsActionSaved GameOptionsAction = iota
 	GameOptionsActionShowQR
+	GameOptionsActionSaveHistory
+	GameOptionsActionCollections
 	GameOptionsActionBack
 )
 
@@ -53,6 +60,10 @@ const (
 	CollectionListActionSelected CollectionListAction = iota
 	CollectionListActionSearch
 	CollectionListActionClearSearch
+	CollectionListActionEdit
+	CollectionListActionNew
+	CollectionListActionDownloadMissing
+	CollectionListActionSubscription
 	CollectionListActionBack
 )
 
@@ -74,6 +85,8 @@ const (
 	SettingsActionSaveSync
 	SettingsActionInfo
 	SettingsActionCheckUpdate
+	SettingsActionManageGames
+	SettingsActionStorage
 	SettingsActionBack
 )
 
@@ -88,6 +101,7 @@ type CollectionsSettingsAction int
 
 const (
 	CollectionsSettingsActionSaved CollectionsSettingsAction = iota
+	CollectionsSettingsActionNewCollection
 	CollectionsSettingsActionBack
 )
 
@@ -140,3 +154,63 @@ type UpdateCheckAction int
 const (
 	UpdateCheckActionComplete UpdateCheckAction = iota
 )
+
+type DownloadQueueAction int
+
+const (
+	DownloadQueueActionStart DownloadQueueAction = iota
+	DownloadQueueActionBack
+)
+
+type LocalGamesAction int
+
+const (
+	LocalGamesActionBack LocalGamesAction = iota
+)
+
+type SaveHistoryAction int
+
+const (
+	SaveHistoryActionBack SaveHistoryAction = iota
+)
+
+type CollectionEditorAction int
+
+const (
+	CollectionEditorActionBack CollectionEditorAction = iota
+)
+
+type CollectionSubscriptionAction int
+
+const (
+	CollectionSubscriptionActionBack CollectionSubscriptionAction = iota
+	CollectionSubscriptionActionSubscribed
+)
+
+type GameCollectionsAction int
+
+const (
+	GameCollectionsActionBack GameCollectionsAction = iota
+)
+
+type StorageAction int
+
+const (
+	StorageActionBack StorageAction = iota
+)
+
+type HostSelectionAction int
+
+const (
+	HostSelectionActionSelected HostSelectionAction = iota
+	HostSelectionActionAdd
+	HostSelectionActionRemove
+	HostSelectionActionBack
+)
+
+type RecentlyPlayedAction int
+
+const (
+	RecentlyPlayedActionSelected RecentlyPlayedAction = iota
+	RecentlyPlayedActionBack
+)
@@ -138,6 +138,10 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 		)
 	}
 
+	if cm != nil {
+		allMissingArtwork = cm.LoadFullGames(allMissingArtwork)
+	}
+
 	if len(allMissingArtwork) == 0 {
 		gaba.ConfirmationMessage(
 			i18n.Localize(&goi18n.Message{ID: "artwork_sync_up_to_date", Other: "All artwork is already cached!"}, nil),
@@ -191,7 +195,7 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 	}
 
 	headers := make(map[string]string)
-	headers["Authorization"] = input.Host.BasicAuthHeader()
+	headers["Authorization"] = input.Host.AuthHeader()
 
 	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
 		AutoContinueOnComplete: true,
@@ -0,0 +1,352 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/download"
+	"grout/romm"
+	"sync"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"go.uber.org/atomic"
+)
+
+const (
+	downloadNotificationDuration = 4 * time.Second
+	downloadProgressInterval     = 500 * time.Millisecond
+	downloadNotificationMaxName  = 18
+	downloadRetryBaseDelay       = 2 * time.Second
+)
+
+// BackgroundDownload works through the download queue while the user keeps browsing.
+// Its status bar icon shows the progress of the current game and briefly names each
+// game once it is on disk. The icon is only added while there is something to show.
+type BackgroundDownload struct {
+	host      romm.Host
+	config    *internal.Config
+	icon      *gaba.DynamicStatusBarIcon
+	onDrained func()
+
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	running     bool
+	pending     bool
+	shown       bool
+	cancel      context.CancelFunc
+	notice      *time.Timer
+	noticeUntil time.Time
+}
+
+// NewBackgroundDownload creates a worker for host. onDrained, if set, is called after
+// the queue empties with at least one game downloaded.
+func NewBackgroundDownload(host romm.Host, config *internal.Config, onDrained func()) *BackgroundDownload {
+	return &BackgroundDownload{
+		host:      host,
+		config:    config,
+		icon:      gaba.NewDynamicStatusBarIcon(""),
+		onDrained: onDrained,
+	}
+}
+
+func (b *BackgroundDownload) Icon() gaba.StatusBarIcon {
+	return gaba.StatusBarIcon{
+		Dynamic: b.icon,
+	}
+}
+
+// Trigger starts working through the queue, or makes a running worker pick up
+// newly queued games before it finishes. While offline the queue is left for when
+// RomM is back.
+func (b *BackgroundDownload) Trigger() {
+	if internal.IsOffline() {
+		gaba.GetLogger().Debug("BackgroundDownload: Offline, leaving queue for later")
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = true
+	if b.running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.running = true
+	b.cancel = cancel
+	b.showIcon()
+	b.wg.Add(1)
+	go b.worker(ctx)
+}
+
+func (b *BackgroundDownload) IsRunning() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.running
+}
+
+// Stop interrupts the current download and waits for the worker to exit. The game
+// stays queued and its partial file is kept so the download resumes next time.
+func (b *BackgroundDownload) Stop() {
+	b.mu.Lock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	gaba.GetLogger().Debug("BackgroundDownload: Stopped")
+}
+
+func (b *BackgroundDownload) worker(ctx context.Context) {
+	logger := gaba.GetLogger()
+	downloaded := 0
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("BackgroundDownload: Panic recovered", "panic", r)
+			b.icon.SetText(icons.CloudAlert)
+		}
+
+		b.mu.Lock()
+		b.running = false
+		b.cancel = nil
+		b.hideIconLater()
+		b.mu.Unlock()
+
+		if downloaded > 0 && ctx.Err() == nil && b.onDrained != nil {
+			b.onDrained()
+		}
+		b.wg.Done()
+	}()
+
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		logger.Error("BackgroundDownload: Cache manager not initialized")
+		b.icon.SetText(icons.CloudAlert)
+		return
+	}
+
+	for ctx.Err() == nil && !internal.IsOffline() {
+		b.mu.Lock()
+		b.pending = false
+		b.mu.Unlock()
+
+		queued, err := cm.GetDownloadQueue(cache.DownloadStatusQueued)
+		if err != nil {
+			logger.Error("BackgroundDownload: Failed to load queue", "error", err)
+			b.icon.SetText(icons.CloudAlert)
+			return
+		}
+
+		if len(queued) == 0 {
+			b.mu.Lock()
+			if b.pending {
+				b.mu.Unlock()
+				continue
+			}
+			// Clear running under the same lock as the pending check so a Trigger
+			// racing with the exit always starts a fresh worker.
+			b.running = false
+			b.mu.Unlock()
+			logger.Debug("BackgroundDownload: Queue drained", "downloaded", downloaded)
+			return
+		}
+
+		if b.process(ctx, cm, queued[0], len(queued)) {
+			downloaded++
+		}
+	}
+}
+
+// DownloadGame downloads a single game without any UI: the game file, extracted if the
+// config asks for it, its box art and its gamelist entry. progress follows the game file.
+// Returns false if there was nothing to download.
+func DownloadGame(ctx context.Context, host romm.Host, config internal.Config, platform romm.Platform, game romm.Rom, fileID int, progress *atomic.Float64) (bool, error) {
+	screen := NewDownloadScreen()
+
+	requests, artDownloads, gamelistEntries := screen.buildDownloads(config, host, platform, []romm.Rom{game}, fileID)
+	if len(requests) == 0 {
+		return false, nil
+	}
+
+	headers := map[string]string{"Authorization": host.AuthHeader()}
+	req := requests[0]
+	req.Headers = headers
+	req.InsecureSkipVerify = host.InsecureSkipVerify
+
+	if err := download.Fetch(ctx, req, progress); err != nil {
+		return false, err
+	}
+
+	gamePlatform := resolveGamePlatform(platform, game)
+	if needsExtraction(config, game) {
+		gamePath, err := extractDownload(config, gamePlatform, game, &atomic.Float64{})
+		if err != nil {
+			// A single-file archive that fails to extract is kept as is
+			if game.HasMultipleFiles {
+				return false, err
+			}
+		} else if gamePath != "" && len(gamelistEntries) > 0 {
+			gamelistEntries[0].GamePath = gamePath
+		}
+	}
+
+	if len(artDownloads) > 0 {
+		screen.downloadArt(artDownloads, []romm.Rom{game}, headers, &atomic.Float64{}, host.InsecureSkipVerify)
+	}
+
+	cfw.FillGamesMetadata(gamelistEntries)
+	return true, nil
+}
+
+// process downloads a single queue entry and records the outcome. Returns true if the
+// game was downloaded.
+func (b *BackgroundDownload) process(ctx context.Context, cm *cache.Manager, entry cache.QueuedDownload, remaining int) bool {
+	logger := gaba.GetLogger()
+
+	cm.MarkDownloadActive(entry.ID)
+	logger.Debug("BackgroundDownload: Downloading", "game", entry.Rom.Name, "remaining", remaining)
+
+	progress := &atomic.Float64{}
+	stopProgress := b.showProgress(progress, remaining)
+	downloaded, err := DownloadGame(ctx, b.host, *b.config, entry.Platform, entry.Rom, entry.FileID, progress)
+	stopProgress()
+
+	if ctx.Err() != nil {
+		cm.MarkDownloadQueued(entry.ID)
+		return false
+	}
+
+	if internal.NoteRequestError(err) {
+		logger.Info("BackgroundDownload: RomM unreachable, keeping game queued", "game", entry.Rom.Name, "error", err)
+		cm.MarkDownloadQueued(entry.ID)
+		return false
+	}
+
+	if err != nil {
+		logger.Warn("BackgroundDownload: Download failed", "game", entry.Rom.Name, "error", err)
+		cm.MarkDownloadFailed(entry.ID, err)
+		b.icon.SetText(icons.CloudAlert)
+		if entry.RetryCount+1 < cache.MaxDownloadRetries {
+			waitBeforeRetry(ctx, entry.RetryCount)
+		}
+		return false
+	}
+
+	if !downloaded {
+		cm.MarkDownloadDone(entry.ID)
+		return false
+	}
+
+	if err := cm.MarkDownloadDone(entry.ID); err != nil {
+		logger.Warn("BackgroundDownload: Failed to mark download done", "game", entry.Rom.Name, "error", err)
+	}
+
+	logger.Debug("BackgroundDownload: Downloaded", "game", entry.Rom.Name)
+	b.notify(entry.Rom)
+	return true
+}
+
+// waitBeforeRetry backs off before a failed download is tried again, doubling the delay
+// with each attempt so a struggling server isn't hammered. Returns early once ctx is done.
+func waitBeforeRetry(ctx context.Context, attempt int) {
+	delay := downloadRetryBaseDelay << attempt
+	gaba.GetLogger().Debug("BackgroundDownload: Waiting before retrying", "delay", delay)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+// showProgress mirrors progress on the status bar icon until the returned func is called.
+func (b *BackgroundDownload) showProgress(progress *atomic.Float64, remaining int) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(downloadProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			if !b.showingNotice() {
+				text := fmt.Sprintf("%s %d%%", icons.Download, int(progress.Load()*100))
+				if remaining > 1 {
+					text = fmt.Sprintf("%s %d%% (%d)", icons.Download, int(progress.Load()*100), remaining)
+				}
+				b.icon.SetText(text)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// notify names a game on the status bar once it has landed on disk. The name stays up
+// for downloadNotificationDuration before progress of the next game takes over.
+func (b *BackgroundDownload) notify(game romm.Rom) {
+	name := []rune(game.DisplayName)
+	if len(name) == 0 {
+		name = []rune(game.Name)
+	}
+	if len(name) > downloadNotificationMaxName {
+		name = append(name[:downloadNotificationMaxName-1], '…')
+	}
+
+	b.mu.Lock()
+	b.noticeUntil = time.Now().Add(downloadNotificationDuration)
+	b.mu.Unlock()
+
+	b.icon.SetText(fmt.Sprintf("%s %s", icons.CloudCheck, string(name)))
+}
+
+func (b *BackgroundDownload) showingNotice() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().Before(b.noticeUntil)
+}
+
+// showIcon adds the icon to the status bar if it isn't there already. Callers hold b.mu.
+func (b *BackgroundDownload) showIcon() {
+	if b.notice != nil {
+		b.notice.Stop()
+		b.notice = nil
+	}
+	if !b.shown {
+		b.icon.SetText(icons.Download)
+		AddStatusBarIcon(b.Icon())
+		b.shown = true
+	}
+}
+
+// hideIconLater removes the icon once any final notification has had time to be seen,
+// unless the worker was started again in the meantime. Callers hold b.mu.
+func (b *BackgroundDownload) hideIconLater() {
+	if !b.shown {
+		return
+	}
+
+	b.notice = time.AfterFunc(downloadNotificationDuration, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if b.running || !b.shown {
+			return
+		}
+		RemoveStatusBarIcon(b.Icon())
+		b.icon.SetText("")
+		b.shown = false
+		b.notice = nil
+	})
+}
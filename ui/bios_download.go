@@ -3,13 +3,11 @@ package ui
 import (
 	"fmt"
 	"grout/bios"
-	"grout/cfw"
 	"grout/internal"
 	"grout/internal/fileutil"
 	"grout/romm"
 	"os"
 	"path/filepath"
-	"strings"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
@@ -80,21 +78,6 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 
 	logger.Debug("Fetched firmware from RomM", "count", len(firmwareList), "platform_id", input.Platform.ID)
 
-	// Try to get BIOS metadata to enrich the firmware list (optional)
-	biosFiles := bios.GetFilesForPlatform(input.Platform.FSSlug)
-
-	// Build metadata lookup by filename for enrichment (case-insensitive)
-	biosMetadataByFileName := make(map[string]bios.File)
-	biosMetadataByRelPath := make(map[string]bios.File)
-	for _, biosFile := range biosFiles {
-		biosMetadataByFileName[strings.ToLower(biosFile.FileName)] = biosFile
-		biosMetadataByRelPath[strings.ToLower(biosFile.RelativePath)] = biosFile
-		baseName := filepath.Base(biosFile.RelativePath)
-		if baseName != biosFile.RelativePath {
-			biosMetadataByFileName[strings.ToLower(baseName)] = biosFile
-		}
-	}
-
 	// Create a BIOSFile entry for each firmware, enriching with metadata if available
 	type firmwareWithMetadata struct {
 		firmware romm.Firmware
@@ -105,13 +88,8 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 	for _, fw := range firmwareList {
 		item := firmwareWithMetadata{firmware: fw}
 
-		// Try to find matching metadata (case-insensitive)
-		baseName := filepath.Base(fw.FilePath)
-		if metadata, found := biosMetadataByFileName[strings.ToLower(fw.FileName)]; found {
-			item.metadata = &metadata
-		} else if metadata, found := biosMetadataByRelPath[strings.ToLower(fw.FilePath)]; found {
-			item.metadata = &metadata
-		} else if metadata, found := biosMetadataByFileName[strings.ToLower(baseName)]; found {
+		// Enrich with BIOS metadata when the firmware is a known file (optional)
+		if metadata, found := bios.MatchFirmware(fw, input.Platform.FSSlug); found {
 			item.metadata = &metadata
 		}
 
@@ -131,21 +109,8 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 		var displayText string
 		var shouldSelect bool
 
-		var fileExists bool
-		if item.metadata != nil {
-			fileExists = bios.FileExists(*item.metadata, input.Platform.FSSlug)
-		} else {
-			relativePath := fw.FileName
-			for _, p := range cfw.GetBIOSFilePaths(relativePath, input.Platform.FSSlug) {
-				if fileutil.FileExists(p) {
-					fileExists = true
-					break
-				}
-			}
-		}
-
 		var statusText string
-		if fileExists {
+		if bios.FirmwareExists(fw, input.Platform.FSSlug) {
 			statusText = i18n.Localize(&goi18n.Message{ID: "bios_status_ready", Other: "Ready"}, nil)
 		} else {
 			statusText = i18n.Localize(&goi18n.Message{ID: "bios_status_not_installed", Other: "Missing"}, nil)
@@ -218,7 +183,7 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 	}
 
 	headers := make(map[string]string)
-	headers["Authorization"] = input.Host.BasicAuthHeader()
+	headers["Authorization"] = input.Host.AuthHeader()
 
 	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
 		AutoContinueOnComplete: true,
@@ -240,31 +205,9 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 			continue
 		}
 
-		// Save the file
-		if info.metadata != nil {
-			if err := bios.SaveFile(*info.metadata, input.Platform.FSSlug, data); err != nil {
-				logger.Error("Failed to save BIOS file", "file", info.metadata.FileName, "error", err)
-				continue
-			}
-		} else {
-			relativePath := info.firmware.FileName
-
-			filePaths := cfw.GetBIOSFilePaths(relativePath, input.Platform.FSSlug)
-			var saveErr error
-			for _, filePath := range filePaths {
-				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-					saveErr = err
-					break
-				}
-				if err := os.WriteFile(filePath, data, 0644); err != nil {
-					saveErr = err
-					break
-				}
-			}
-			if saveErr != nil {
-				logger.Error("Failed to save BIOS file", "file", info.firmware.FileName, "error", saveErr)
-				continue
-			}
+		if err := bios.SaveFirmware(info.firmware, input.Platform.FSSlug, data); err != nil {
+			logger.Error("Failed to save BIOS file", "file", info.firmware.FileName, "error", err)
+			continue
 		}
 
 		os.Remove(download.Location)
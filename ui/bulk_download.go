@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"grout/cache"
+	"grout/internal"
+	"grout/internal/stringutil"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// MissingGames returns the games that are neither on the device nor already waiting in
+// the download queue.
+func MissingGames(config internal.Config, games []romm.Rom) []romm.Rom {
+	queued := make(map[int]bool)
+	if cm := cache.GetCacheManager(); cm != nil {
+		if entries, err := cm.GetDownloadQueue(cache.DownloadStatusQueued, cache.DownloadStatusActive); err == nil {
+			for _, entry := range entries {
+				queued[entry.Rom.ID] = true
+			}
+		}
+	}
+
+	missing := make([]romm.Rom, 0, len(games))
+	for _, game := range games {
+		if queued[game.ID] || game.IsDownloaded(config) {
+			continue
+		}
+		missing = append(missing, game)
+	}
+	return missing
+}
+
+// ConfirmBulkDownload asks before downloading every missing game of name, showing how
+// many there are and how much they add up to. Returns true to go ahead.
+func ConfirmBulkDownload(name string, games []romm.Rom, total int) bool {
+	if len(games) == 0 {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "bulk_download_none", Other: "All {{.Total}} games in {{.Name}} are already downloaded."}, map[string]interface{}{
+				"Name":  name,
+				"Total": total,
+			}),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return false
+	}
+
+	var size int64
+	for _, game := range games {
+		size += downloadSize(game, 0)
+	}
+
+	result, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "bulk_download_confirm", Other: "Download {{.Count}} of {{.Total}} games in {{.Name}}?\n{{.Size}} in total."}, map[string]interface{}{
+			"Name":  name,
+			"Count": len(games),
+			"Total": total,
+			"Size":  stringutil.FormatBytes(size),
+		}),
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			FooterDownload(),
+		},
+		gaba.MessageOptions{},
+	)
+	return err == nil && result != nil && result.Confirmed
+}
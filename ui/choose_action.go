@@ -0,0 +1,27 @@
+package ui
+
+import (
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// chooseAction shows a short menu of actions and returns the Metadata of the one picked,
+// or false if none was. A single action is returned without asking.
+func chooseAction(title string, items []gaba.MenuItem) (any, bool) {
+	switch len(items) {
+	case 0:
+		return nil, false
+	case 1:
+		return items[0].Metadata, true
+	}
+
+	options := gaba.DefaultListOptions(title, items)
+	options.UseSmallTitle = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{FooterBack(), FooterSelect()}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil || sel.Action != gaba.ListActionSelected || len(sel.Selected) == 0 {
+		return nil, false
+	}
+	return sel.Items[sel.Selected[0]].Metadata, true
+}
@@ -0,0 +1,348 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+var errCollectionNotOwned = errors.New("collection belongs to another user")
+
+type CollectionEditorInput struct {
+	Config     *internal.Config
+	Host       romm.Host
+	Collection romm.Collection // Leave empty to create a new collection
+}
+
+type CollectionEditorOutput struct {
+	Action CollectionEditorAction
+}
+
+// CollectionEditorScreen creates, renames, shares and deletes the user's own collections
+// in RomM and removes games from them. Games are added from the game list.
+type CollectionEditorScreen struct{}
+
+func NewCollectionEditorScreen() *CollectionEditorScreen {
+	return &CollectionEditorScreen{}
+}
+
+func (s *CollectionEditorScreen) Draw(input CollectionEditorInput) (CollectionEditorOutput, error) {
+	output := CollectionEditorOutput{Action: CollectionEditorActionBack}
+	logger := gaba.GetLogger()
+
+	if internal.IsOffline() {
+		ShowOfflineMessage(&goi18n.Message{ID: "game_collections_offline", Other: "RomM can't be reached right now.\nCollections can be changed once it is back online."})
+		return output, nil
+	}
+
+	collection := input.Collection
+	isNew := collection.ID == 0 && !collection.IsVirtual
+
+	if collection.IsSmart || collection.IsVirtual {
+		s.showMessage(&goi18n.Message{ID: "collection_editor_not_editable", Other: "Smart and virtual collections can only be changed in RomM."})
+		return output, nil
+	}
+
+	if !isNew {
+		fetched, err := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "collection_editor_loading", Other: "Loading collection..."}, nil),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (romm.Collection, error) {
+				return fetchOwnCollection(input.Host, input.Config, collection.ID)
+			},
+		)
+		if errors.Is(err, errCollectionNotOwned) {
+			s.showMessage(&goi18n.Message{ID: "collection_editor_not_owned", Other: "Only your own collections can be changed."})
+			return output, nil
+		}
+		if err != nil {
+			logger.Error("Failed to load collection", "collection", collection.Name, "error", err)
+			internal.NoteRequestError(err)
+			s.showMessage(&goi18n.Message{ID: "game_collections_load_failed", Other: "Unable to load your collections.\nPlease try again later."})
+			return output, nil
+		}
+		collection = fetched
+	}
+
+	title := collection.Name
+	if isNew {
+		title = i18n.Localize(&goi18n.Message{ID: "collection_editor_new_title", Other: "New Collection"}, nil)
+	}
+
+	edited := collection
+	selectedIndex := 0
+	for {
+		items := s.buildMenuItems(edited, isNew)
+
+		result, err := gaba.OptionsList(
+			title,
+			gaba.OptionListSettings{
+				FooterHelpItems:      OptionsListFooter(),
+				InitialSelectedIndex: selectedIndex,
+				StatusBar:            StatusBar(),
+				UseSmallTitle:        true,
+			},
+			items,
+		)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return output, nil
+			}
+			logger.Error("Collection editor error", "error", err)
+			return output, err
+		}
+
+		edited = s.applyItems(edited, result.Items)
+		selectedIndex = result.Selected
+
+		if result.Action == gaba.ListActionSelected && result.Selected >= 0 && result.Selected < len(result.Items) {
+			switch result.Items[result.Selected].Item.Metadata {
+			case collectionEditorGames:
+				edited.ROMIDs = s.editGames(edited)
+				continue
+			case collectionEditorDelete:
+				if s.confirmDelete(edited) && s.deleteCollection(input.Host, input.Config, collection) {
+					return output, nil
+				}
+				continue
+			}
+		}
+
+		edited.Name = strings.TrimSpace(edited.Name)
+		if edited.Name == "" {
+			s.showMessage(&goi18n.Message{ID: "collection_editor_name_required", Other: "Please enter a name for the collection."})
+			continue
+		}
+
+		if s.saveCollection(input.Host, input.Config, edited, isNew) {
+			return output, nil
+		}
+	}
+}
+
+const (
+	collectionEditorName = iota + 1
+	collectionEditorVisibility
+	collectionEditorGames
+	collectionEditorDelete
+)
+
+func (s *CollectionEditorScreen) buildMenuItems(collection romm.Collection, isNew bool) []gaba.ItemWithOptions {
+	var items []gaba.ItemWithOptions
+
+	// RomM names the favourites collection itself
+	if !collection.IsFavorite {
+		items = append(items, gaba.ItemWithOptions{
+			Item: gaba.MenuItem{
+				Text:     i18n.Localize(&goi18n.Message{ID: "collection_editor_name", Other: "Name"}, nil),
+				Metadata: collectionEditorName,
+			},
+			Options: []gaba.Option{{
+				Type:           gaba.OptionTypeKeyboard,
+				DisplayName:    collection.Name,
+				KeyboardPrompt: collection.Name,
+				Value:          collection.Name,
+			}},
+		})
+	}
+
+	items = append(items, gaba.ItemWithOptions{
+		Item: gaba.MenuItem{
+			Text:     i18n.Localize(&goi18n.Message{ID: "collection_editor_visibility", Other: "Visibility"}, nil),
+			Metadata: collectionEditorVisibility,
+		},
+		Options: []gaba.Option{
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_editor_private", Other: "Private"}, nil), Value: false},
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_editor_public", Other: "Public"}, nil), Value: true},
+		},
+		SelectedOption: boolToIndex(collection.IsPublic),
+	})
+
+	if isNew {
+		return items
+	}
+
+	if len(collection.ROMIDs) > 0 {
+		items = append(items, gaba.ItemWithOptions{
+			Item: gaba.MenuItem{
+				Text:     i18n.Localize(&goi18n.Message{ID: "collection_editor_games", Other: "Games ({{.Count}})"}, map[string]interface{}{"Count": len(collection.ROMIDs)}),
+				Metadata: collectionEditorGames,
+			},
+			Options: []gaba.Option{{DisplayName: "", Value: "games", Type: gaba.OptionTypeClickable}},
+		})
+	}
+
+	if !collection.IsFavorite {
+		items = append(items, gaba.ItemWithOptions{
+			Item: gaba.MenuItem{
+				Text:     i18n.Localize(&goi18n.Message{ID: "collection_editor_delete", Other: "Delete Collection"}, nil),
+				Metadata: collectionEditorDelete,
+			},
+			Options: []gaba.Option{{DisplayName: "", Value: "delete", Type: gaba.OptionTypeClickable}},
+		})
+	}
+
+	return items
+}
+
+func (s *CollectionEditorScreen) applyItems(collection romm.Collection, items []gaba.ItemWithOptions) romm.Collection {
+	for _, item := range items {
+		switch item.Item.Metadata {
+		case collectionEditorName:
+			if name, ok := item.Options[item.SelectedOption].Value.(string); ok {
+				collection.Name = name
+			}
+		case collectionEditorVisibility:
+			if public, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				collection.IsPublic = public
+			}
+		}
+	}
+	return collection
+}
+
+// editGames lists the collection's games, all selected, and returns its ROM IDs without
+// those deselected. Games that aren't in the cache can't be listed and are kept.
+func (s *CollectionEditorScreen) editGames(collection romm.Collection) []int {
+	logger := gaba.GetLogger()
+
+	games, err := cache.GetCacheManager().GetGamesByIDs(collection.ROMIDs)
+	if err != nil {
+		logger.Error("Failed to load collection games", "collection", collection.Name, "error", err)
+	}
+	if len(games) == 0 {
+		return collection.ROMIDs
+	}
+
+	games = stringutil.PrepareRomNames(games)
+	slices.SortFunc(games, func(a, b romm.Rom) int {
+		return strings.Compare(strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName))
+	})
+
+	menuItems := make([]gaba.MenuItem, len(games))
+	for i, game := range games {
+		menuItems[i] = gaba.MenuItem{
+			Text:     fmt.Sprintf("%s [%s]", game.DisplayName, game.PlatformDisplayName),
+			Selected: true,
+			Metadata: game,
+		}
+	}
+
+	saveItem := FooterSave()
+	saveItem.IsConfirmButton = true
+
+	options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "collection_editor_games_title", Other: "Deselect games to remove"}, nil), menuItems)
+	options.UseSmallTitle = true
+	options.InitialMultiSelectMode = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{FooterBack(), saveItem}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil || sel.Action != gaba.ListActionSelected {
+		return collection.ROMIDs
+	}
+
+	var removed []int
+	for i, item := range sel.Items {
+		if !slices.Contains(sel.Selected, i) {
+			removed = append(removed, item.Metadata.(romm.Rom).ID)
+		}
+	}
+	return withRomIDs(collection.ROMIDs, removed, false)
+}
+
+func (s *CollectionEditorScreen) confirmDelete(collection romm.Collection) bool {
+	result, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_editor_delete_confirm", Other: "Delete {{.Name}}?\nThe games stay in your library."}, map[string]interface{}{"Name": collection.Name}),
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			footerItem("A", "button_delete", "Delete"),
+		},
+		gaba.MessageOptions{},
+	)
+	return err == nil && result != nil && result.Confirmed
+}
+
+func (s *CollectionEditorScreen) deleteCollection(host romm.Host, config *internal.Config, collection romm.Collection) bool {
+	logger := gaba.GetLogger()
+
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_editor_deleting", Other: "Deleting collection..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			return nil, romm.NewClientFromHost(host, config.ApiTimeout).DeleteCollection(collection.ID)
+		},
+	)
+	if err != nil {
+		logger.Error("Failed to delete collection", "collection", collection.Name, "error", err)
+		internal.NoteRequestError(err)
+		s.showMessage(&goi18n.Message{ID: "collection_editor_delete_failed", Other: "Unable to delete the collection.\nCheck the logs for details."})
+		return false
+	}
+
+	if err := cache.GetCacheManager().DeleteCollection(collection); err != nil {
+		logger.Warn("Unable to remove cached collection", "collection", collection.Name, "error", err)
+	}
+	logger.Info("Deleted collection", "collection", collection.Name)
+	return true
+}
+
+func (s *CollectionEditorScreen) saveCollection(host romm.Host, config *internal.Config, collection romm.Collection, isNew bool) bool {
+	logger := gaba.GetLogger()
+
+	saved, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_editor_saving", Other: "Saving collection..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (romm.Collection, error) {
+			client := romm.NewClientFromHost(host, config.ApiTimeout)
+			if isNew {
+				return client.CreateCollection(collection)
+			}
+			return client.UpdateCollection(collection)
+		},
+	)
+	if err != nil {
+		logger.Error("Failed to save collection", "collection", collection.Name, "error", err)
+		internal.NoteRequestError(err)
+		showCollectionSaveFailed()
+		return false
+	}
+
+	if err := cache.GetCacheManager().SaveCollection(saved); err != nil {
+		logger.Warn("Unable to cache collection", "collection", saved.Name, "error", err)
+	}
+	logger.Info("Saved collection", "collection", saved.Name, "new", isNew)
+	return true
+}
+
+func (s *CollectionEditorScreen) showMessage(message *goi18n.Message) {
+	gaba.ConfirmationMessage(i18n.Localize(message, nil), ContinueFooter(), gaba.MessageOptions{})
+}
+
+// fetchOwnCollection loads the collection with id, as long as it belongs to the user.
+func fetchOwnCollection(host romm.Host, config *internal.Config, id int) (romm.Collection, error) {
+	client := romm.NewClientFromHost(host, config.ApiTimeout)
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		return romm.Collection{}, err
+	}
+
+	collection, err := client.GetCollection(id)
+	if err != nil {
+		return romm.Collection{}, err
+	}
+	if collection.UserID != user.ID {
+		return romm.Collection{}, errCollectionNotOwned
+	}
+	return collection, nil
+}
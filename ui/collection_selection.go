@@ -120,9 +120,17 @@ func (s *CollectionSelectionScreen) Draw(input CollectionSelectionInput) (Collec
 
 	footerItems := []gaba.FooterHelpItem{
 		{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_back", Other: "Back"}, nil)},
-		{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_search", Other: "Search"}, nil)},
-		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_select", Other: "Select"}, nil)},
 	}
+	if !internal.IsKidModeEnabled() {
+		footerItems = append(footerItems,
+			gaba.FooterHelpItem{ButtonName: i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), HelpText: i18n.Localize(&goi18n.Message{ID: "button_more", Other: "More"}, nil)},
+			gaba.FooterHelpItem{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_edit", Other: "Edit"}, nil), Group: gaba.FooterGroupRight},
+		)
+	}
+	footerItems = append(footerItems,
+		gaba.FooterHelpItem{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_search", Other: "Search"}, nil)},
+		gaba.FooterHelpItem{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_select", Other: "Select"}, nil)},
+	)
 
 	title := "Collections"
 	if input.SearchFilter != "" {
@@ -131,41 +139,84 @@ func (s *CollectionSelectionScreen) Draw(input CollectionSelectionInput) (Collec
 
 	options := gaba.DefaultListOptions(title, menuItems)
 	options.ActionButton = buttons.VirtualButtonX
+	if !internal.IsKidModeEnabled() {
+		options.SecondaryActionButton = buttons.VirtualButtonY
+		options.TertiaryActionButton = buttons.VirtualButtonMenu
+	}
 	options.FooterHelpItems = footerItems
 	options.SelectedIndex = input.LastSelectedIndex
 	options.VisibleStartIndex = max(0, input.LastSelectedIndex-input.LastSelectedPosition)
 	options.StatusBar = StatusBar()
 
-	sel, err := gaba.List(options)
-	if err != nil {
-		if errors.Is(err, gaba.ErrCancelled) {
-			if input.SearchFilter != "" {
-				output.SearchFilter = ""
-				output.LastSelectedIndex = 0
-				output.LastSelectedPosition = 0
-				output.Action = CollectionListActionClearSearch
+	for {
+		sel, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				if input.SearchFilter != "" {
+					output.SearchFilter = ""
+					output.LastSelectedIndex = 0
+					output.LastSelectedPosition = 0
+					output.Action = CollectionListActionClearSearch
+					return output, nil
+				}
 				return output, nil
 			}
-			return output, nil
+			return output, err
 		}
-		return output, err
-	}
 
-	switch sel.Action {
-	case gaba.ListActionSelected:
-		collection := sel.Items[sel.Selected[0]].Metadata.(romm.Collection)
+		switch sel.Action {
+		case gaba.ListActionSelected:
+			collection := sel.Items[sel.Selected[0]].Metadata.(romm.Collection)
 
-		output.SelectedCollection = collection
-		output.LastSelectedIndex = sel.Selected[0]
-		output.LastSelectedPosition = sel.VisiblePosition
-		output.Action = CollectionListActionSelected
-		return output, nil
+			output.SelectedCollection = collection
+			output.LastSelectedIndex = sel.Selected[0]
+			output.LastSelectedPosition = sel.VisiblePosition
+			output.Action = CollectionListActionSelected
+			return output, nil
 
-	case gaba.ListActionTriggered:
-		output.Action = CollectionListActionSearch
-		return output, nil
+		case gaba.ListActionTriggered:
+			output.Action = CollectionListActionSearch
+			return output, nil
 
-	default:
-		return output, nil
+		case gaba.ListActionSecondaryTriggered:
+			output.SelectedCollection = sel.Items[sel.Selected[0]].Metadata.(romm.Collection)
+			output.LastSelectedIndex = sel.Selected[0]
+			output.LastSelectedPosition = sel.VisiblePosition
+			output.Action = CollectionListActionEdit
+			return output, nil
+
+		case gaba.ListActionTertiaryTriggered:
+			collection := sel.Items[sel.Selected[0]].Metadata.(romm.Collection)
+			items := []gaba.MenuItem{{
+				Text:     i18n.Localize(&goi18n.Message{ID: "bulk_download_missing", Other: "Download Missing Games"}, nil),
+				Metadata: CollectionListActionDownloadMissing,
+			}}
+			if !collection.IsVirtual {
+				items = append(items, gaba.MenuItem{
+					Text:     i18n.Localize(&goi18n.Message{ID: "collection_subscription_enabled", Other: "Keep in Sync"}, nil),
+					Metadata: CollectionListActionSubscription,
+				})
+			}
+			items = append(items, gaba.MenuItem{
+				Text:     i18n.Localize(&goi18n.Message{ID: "button_new_collection", Other: "New Collection"}, nil),
+				Metadata: CollectionListActionNew,
+			})
+
+			action, ok := chooseAction(i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), items)
+			if !ok {
+				// Back to the list where it was left
+				options.SelectedIndex = sel.Selected[0]
+				options.VisibleStartIndex = max(0, sel.Selected[0]-sel.VisiblePosition)
+				continue
+			}
+			output.SelectedCollection = collection
+			output.LastSelectedIndex = sel.Selected[0]
+			output.LastSelectedPosition = sel.VisiblePosition
+			output.Action = action.(CollectionListAction)
+			return output, nil
+
+		default:
+			return output, nil
+		}
 	}
 }
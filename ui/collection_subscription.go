@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"grout/cache"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type CollectionSubscriptionInput struct {
+	Collection romm.Collection
+}
+
+type CollectionSubscriptionOutput struct {
+	Action     CollectionSubscriptionAction
+	Collection romm.Collection
+}
+
+// CollectionSubscriptionScreen keeps a collection in sync with the device. Games added to
+// it in RomM are downloaded each time the collections are refreshed, and games removed
+// from it can be deleted.
+type CollectionSubscriptionScreen struct{}
+
+func NewCollectionSubscriptionScreen() *CollectionSubscriptionScreen {
+	return &CollectionSubscriptionScreen{}
+}
+
+const (
+	collectionSubscriptionEnabled = iota + 1
+	collectionSubscriptionDeleteRemoved
+)
+
+func (s *CollectionSubscriptionScreen) Draw(input CollectionSubscriptionInput) (CollectionSubscriptionOutput, error) {
+	output := CollectionSubscriptionOutput{Action: CollectionSubscriptionActionBack, Collection: input.Collection}
+	logger := gaba.GetLogger()
+	cm := cache.GetCacheManager()
+	collection := input.Collection
+
+	sub, subscribed, err := cm.GetCollectionSubscription(collection)
+	if err != nil {
+		logger.Error("Failed to load collection subscription", "collection", collection.Name, "error", err)
+		return output, err
+	}
+
+	items := []gaba.ItemWithOptions{
+		{
+			Item: gaba.MenuItem{
+				Text:     i18n.Localize(&goi18n.Message{ID: "collection_subscription_enabled", Other: "Keep in Sync"}, nil),
+				Metadata: collectionSubscriptionEnabled,
+			},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "option_disabled", Other: "Disabled"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "option_enabled", Other: "Enabled"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(subscribed),
+		},
+		{
+			Item: gaba.MenuItem{
+				Text:     i18n.Localize(&goi18n.Message{ID: "collection_subscription_delete_removed", Other: "Delete Removed Games"}, nil),
+				Metadata: collectionSubscriptionDeleteRemoved,
+			},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "option_disabled", Other: "Disabled"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "option_enabled", Other: "Enabled"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(sub.DeleteRemoved),
+		},
+	}
+
+	result, err := gaba.OptionsList(
+		collection.Name,
+		gaba.OptionListSettings{
+			FooterHelpItems: OptionsListFooter(),
+			StatusBar:       StatusBar(),
+			UseSmallTitle:   true,
+		},
+		items,
+	)
+	if err != nil {
+		if errors.Is(err, gaba.ErrCancelled) {
+			return output, nil
+		}
+		logger.Error("Collection subscription error", "error", err)
+		return output, err
+	}
+
+	enabled := false
+	for _, item := range result.Items {
+		value, _ := item.Options[item.SelectedOption].Value.(bool)
+		switch item.Item.Metadata {
+		case collectionSubscriptionEnabled:
+			enabled = value
+		case collectionSubscriptionDeleteRemoved:
+			sub.DeleteRemoved = value
+		}
+	}
+
+	if !enabled {
+		if subscribed {
+			if err := cm.DeleteCollectionSubscription(collection); err != nil {
+				logger.Error("Failed to unsubscribe from collection", "collection", collection.Name, "error", err)
+			}
+		}
+		return output, nil
+	}
+
+	// A new subscription only picks up games added from now on, the ones already in the
+	// collection are offered for download separately
+	if !subscribed {
+		sub.CollectionID = collection.ID
+		sub.Smart = collection.IsSmart
+		sub.ROMIDs = collection.ROMIDs
+	}
+	sub.Name = collection.Name
+
+	if err := cm.SaveCollectionSubscription(sub); err != nil {
+		logger.Error("Failed to subscribe to collection", "collection", collection.Name, "error", err)
+		return output, nil
+	}
+
+	if !subscribed {
+		output.Action = CollectionSubscriptionActionSubscribed
+	}
+	return output, nil
+}
@@ -34,6 +34,15 @@ func (s *CollectionsSettingsScreen) Draw(input CollectionsSettingsInput) (Collec
 
 	items := s.buildMenuItems(config)
 
+	newCollectionText := i18n.Localize(&goi18n.Message{ID: "button_new_collection", Other: "New Collection"}, nil)
+	if !internal.IsKidModeEnabled() {
+		items = append(items, gaba.ItemWithOptions{
+			Item:           gaba.MenuItem{Text: newCollectionText},
+			Options:        []gaba.Option{{DisplayName: "", Value: "new_collection", Type: gaba.OptionTypeClickable}},
+			SelectedOption: 0,
+		})
+	}
+
 	result, err := gaba.OptionsList(
 		i18n.Localize(&goi18n.Message{ID: "settings_collections", Other: "Collections Settings"}, nil),
 		gaba.OptionListSettings{
@@ -57,6 +66,12 @@ func (s *CollectionsSettingsScreen) Draw(input CollectionsSettingsInput) (Collec
 		return output, err
 	}
 
+	if result.Action == gaba.ListActionSelected && result.Selected >= 0 && result.Selected < len(result.Items) &&
+		result.Items[result.Selected].Item.Text == newCollectionText {
+		output.Action = CollectionsSettingsActionNewCollection
+		return output, nil
+	}
+
 	s.applySettings(config, result.Items)
 
 	if (!prevRegular && config.ShowRegularCollections) ||
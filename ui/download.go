@@ -1,12 +1,14 @@
 package ui
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"grout/cfw"
 	"grout/cfw/muos"
 	"grout/internal"
+	"grout/internal/download"
 	"grout/internal/fileutil"
 	"grout/internal/gamelist"
 	"grout/internal/imageutil"
@@ -23,6 +25,7 @@ import (
 	"strings"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	"go.uber.org/atomic"
@@ -40,11 +43,18 @@ type DownloadInput struct {
 
 type DownloadOutput struct {
 	DownloadedGames []romm.Rom
+	FailedGames     []FailedDownload
+	Cancelled       bool
 	Platform        romm.Platform
 	AllGames        []romm.Rom
 	SearchFilter    string
 }
 
+type FailedDownload struct {
+	Game romm.Rom
+	Err  error
+}
+
 type DownloadScreen struct{}
 
 type artDownload struct {
@@ -96,44 +106,43 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 	downloads, artDownloads, gamelistEntries := s.buildDownloads(input.Config, input.Host, input.Platform, input.SelectedGames, input.SelectedFileID)
 
 	headers := make(map[string]string)
-	headers["Authorization"] = input.Host.BasicAuthHeader()
+	headers["Authorization"] = input.Host.AuthHeader()
+
+	for i := range downloads {
+		downloads[i].Headers = headers
+		downloads[i].InsecureSkipVerify = input.Host.InsecureSkipVerify
+	}
 
-	slices.SortFunc(downloads, func(a, b gaba.Download) int {
+	slices.SortFunc(downloads, func(a, b download.Request) int {
 		return strings.Compare(strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName))
 	})
 
-	logger.Debug("Starting ROM download", "downloads", downloads)
+	logger.Debug("Starting ROM download", "downloads", len(downloads))
 
-	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
-		AutoContinueOnComplete: input.Config.DownloadArt,
-		SkipSSLVerification:    input.Host.InsecureSkipVerify,
-	})
-	if err != nil {
+	// Partial files are staged outside the ROM directory and kept on cancel or failure
+	// so the next attempt resumes where this one stopped.
+	res, err := s.fetchAll(downloads)
+	if err != nil && !errors.Is(err, gaba.ErrCancelled) {
 		logger.Error("Error downloading", "error", err)
-
-		// Clean up any partial downloads when cancelled
-		if errors.Is(err, gaba.ErrCancelled) {
-			for _, d := range downloads {
-				fileutil.DeleteFile(d.Location)
-			}
-		}
-
 		return output, err
 	}
+	// Games that finished before the cancel are already in place, so still extract them
+	output.Cancelled = err != nil
 
 	logger.Debug("Download results", "completed", len(res.Completed), "failed", len(res.Failed))
 
 	if len(res.Failed) > 0 {
 		for _, f := range res.Failed {
-			logger.Warn("Download failed", "name", f.Download.DisplayName, "url", f.Download.URL, "error", f.Error)
+			logger.Warn("Download failed", "name", f.Request.DisplayName, "url", f.Request.URL, "error", f.Err)
 		}
+		s.showFailures(res.Failed)
 
-		for _, g := range downloads {
-			failedMatch := slices.ContainsFunc(res.Failed, func(de gaba.DownloadError) bool {
-				return de.Download.DisplayName == g.DisplayName
-			})
-			if failedMatch {
-				fileutil.DeleteFile(g.Location)
+		for _, g := range input.SelectedGames {
+			for _, f := range res.Failed {
+				if f.Request.DisplayName == g.Name {
+					output.FailedGames = append(output.FailedGames, FailedDownload{Game: g, Err: f.Err})
+					break
+				}
 			}
 		}
 	}
@@ -143,157 +152,37 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 	}
 
 	for _, g := range input.SelectedGames {
-		if !g.HasMultipleFiles {
-			continue
-		}
-
-		completed := slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
+		completed := slices.ContainsFunc(res.Completed, func(d download.Request) bool {
 			return d.DisplayName == g.Name
 		})
-		if !completed {
+		if !completed || !needsExtraction(input.Config, g) {
 			continue
 		}
 
-		gamePlatform := input.Platform
-		if input.Platform.ID == 0 && g.PlatformID != 0 {
-			gamePlatform = romm.Platform{
-				ID:     g.PlatformID,
-				FSSlug: g.PlatformFSSlug,
-				Name:   g.PlatformDisplayName,
-			}
-		}
-
-		tmpZipPath := filepath.Join(fileutil.TempDir(), fmt.Sprintf("grout_multirom_%d.zip", g.ID))
-		romDirectory := input.Config.GetPlatformRomDirectory(gamePlatform)
-		extractDir := filepath.Join(romDirectory, g.FsNameNoExt)
+		gamePlatform := resolveGamePlatform(input.Platform, g)
 
 		progress := &atomic.Float64{}
-		_, err := gaba.ProcessMessage(
+		gamePath, err := gaba.ProcessMessage(
 			i18n.Localize(&goi18n.Message{ID: "download_extracting", Other: "Extracting {{.Name}}..."}, map[string]interface{}{"Name": g.DisplayName}),
 			gaba.ProcessMessageOptions{
 				ShowThemeBackground: true,
 				ShowProgressBar:     true,
 				Progress:            progress,
 			},
-			func() (interface{}, error) {
-				logger.Debug("Extracting multi-file ROM", "game", g.DisplayName, "dest", extractDir)
-
-				if err := fileutil.Unzip(tmpZipPath, extractDir, progress); err != nil {
-					logger.Error("Failed to extract multi-file ROM", "game", g.DisplayName, "error", err)
-					os.Remove(tmpZipPath)
-					return nil, err
-				}
-
-				if cfw.GetCFW() == cfw.MuOS {
-					if err := muos.OrganizeMultiFileRom(extractDir, romDirectory, g.FsNameNoExt); err != nil {
-						logger.Error("Failed to organize multi-file ROM for muOS", "game", g.FsNameNoExt, "error", err)
-						os.Remove(tmpZipPath)
-						os.RemoveAll(extractDir)
-						return nil, err
-					}
-				}
-
-				if err := os.Remove(tmpZipPath); err != nil {
-					logger.Warn("Failed to remove temp zip file", "path", tmpZipPath, "error", err)
-				}
-
-				return nil, nil
+			func() (string, error) {
+				return extractDownload(input.Config, gamePlatform, g, progress)
 			},
 		)
 
 		if err != nil {
 			continue
 		}
-	}
-
-	if input.Config.UnzipDownloads {
-		for _, g := range input.SelectedGames {
-			if g.HasMultipleFiles {
-				continue
-			}
-
-			completed := slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
-				return d.DisplayName == g.Name
-			})
-			if !completed {
-				continue
-			}
 
-			gamePlatform := input.Platform
-			if input.Platform.ID == 0 && g.PlatformID != 0 {
-				gamePlatform = romm.Platform{
-					ID:     g.PlatformID,
-					FSSlug: g.PlatformFSSlug,
-					Name:   g.PlatformDisplayName,
-				}
-			}
-
-			if len(g.Files) > 0 {
-				ext := strings.ToLower(filepath.Ext(g.Files[0].FileName))
-				if ext == ".zip" || ext == ".7z" {
-					romDirectory := input.Config.GetPlatformRomDirectory(gamePlatform)
-					archivePath := filepath.Join(romDirectory, g.Files[0].FileName)
-
-					progress := &atomic.Float64{}
-					_, err := gaba.ProcessMessage(
-						i18n.Localize(&goi18n.Message{ID: "download_extracting", Other: "Extracting {{.Name}}..."}, map[string]interface{}{"Name": g.Name}),
-						gaba.ProcessMessageOptions{
-							ShowThemeBackground: true,
-							ShowProgressBar:     true,
-							Progress:            progress,
-						},
-						func() (interface{}, error) {
-							logger.Debug("Extracting single-file ROM", "game", g.Name, "file", archivePath)
-
-							var archiveFiles []string
-							var extractErr error
-							if ext == ".7z" {
-								archiveFiles, extractErr = fileutil.SevenZipFileNames(archivePath)
-								if extractErr == nil {
-									extractErr = fileutil.Un7zip(archivePath, romDirectory, progress)
-								}
-							} else {
-								archiveFiles, extractErr = fileutil.ZipFileNames(archivePath)
-								if extractErr == nil {
-									extractErr = fileutil.Unzip(archivePath, romDirectory, progress)
-								}
-							}
-
-							if extractErr != nil {
-								logger.Error("Failed to extract single-file ROM", "game", g.Name, "error", extractErr)
-								return nil, extractErr
-							}
-
-							if err := os.Remove(archivePath); err != nil {
-								logger.Warn("Failed to remove archive file after extraction", "path", archivePath, "error", err)
-							}
-
-							if len(archiveFiles) > 0 {
-								gamePath := archiveFiles[0]
-								if len(archiveFiles) > 1 {
-									for _, f := range archiveFiles {
-										if strings.ToLower(filepath.Ext(f)) == ".m3u" {
-											gamePath = f
-											break
-										}
-									}
-								}
-								for i, entry := range gamelistEntries {
-									if entry.Game.ID == g.ID {
-										gamelistEntries[i].GamePath = filepath.Join(romDirectory, gamePath)
-										break
-									}
-								}
-							}
-
-							return nil, nil
-						},
-					)
-
-					if err != nil {
-						logger.Warn("Failed to extract ROM, keeping archive file", "game", g.Name)
-						continue
-					}
+		if gamePath != "" {
+			for i, entry := range gamelistEntries {
+				if entry.Game.ID == g.ID {
+					gamelistEntries[i].GamePath = gamePath
+					break
 				}
 			}
 		}
@@ -301,7 +190,7 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 
 	downloadedGames := make([]romm.Rom, 0, len(res.Completed))
 	for _, g := range input.SelectedGames {
-		if slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
+		if slices.ContainsFunc(res.Completed, func(d download.Request) bool {
 			return d.DisplayName == g.Name
 		}) {
 			downloadedGames = append(downloadedGames, g)
@@ -336,8 +225,113 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 	return output, nil
 }
 
-func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host, platform romm.Platform, games []romm.Rom, selectedFileID int) ([]gaba.Download, []artDownload, []gamelist.RomGameEntry) {
-	downloads := make([]gaba.Download, 0, len(games))
+// resolveGamePlatform returns the platform a game belongs to. Collections are passed
+// around with an empty platform, in which case the game's own platform fields are used.
+func resolveGamePlatform(platform romm.Platform, g romm.Rom) romm.Platform {
+	if platform.ID == 0 && g.PlatformID != 0 {
+		return romm.Platform{
+			ID:     g.PlatformID,
+			FSSlug: g.PlatformFSSlug,
+			Name:   g.PlatformDisplayName,
+		}
+	}
+	return platform
+}
+
+// needsExtraction reports whether a downloaded game has to be unpacked before it can be played.
+func needsExtraction(config internal.Config, g romm.Rom) bool {
+	if g.HasMultipleFiles {
+		return true
+	}
+	if !config.UnzipDownloads || len(g.Files) == 0 {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(g.Files[0].FileName))
+	return ext == ".zip" || ext == ".7z"
+}
+
+// extractDownload unpacks a downloaded game in place. Multi-file games are extracted into
+// their own folder; single-file archives are extracted into the ROM directory and removed.
+// For archives it returns the path of the file to launch, otherwise an empty string.
+func extractDownload(config internal.Config, platform romm.Platform, g romm.Rom, progress *atomic.Float64) (string, error) {
+	logger := gaba.GetLogger()
+	romDirectory := config.GetPlatformRomDirectory(platform)
+
+	if g.HasMultipleFiles {
+		tmpZipPath := filepath.Join(fileutil.TempDir(), fmt.Sprintf("grout_multirom_%d.zip", g.ID))
+		extractDir := filepath.Join(romDirectory, g.FsNameNoExt)
+
+		logger.Debug("Extracting multi-file ROM", "game", g.DisplayName, "dest", extractDir)
+
+		if err := fileutil.Unzip(tmpZipPath, extractDir, progress); err != nil {
+			logger.Error("Failed to extract multi-file ROM", "game", g.DisplayName, "error", err)
+			os.Remove(tmpZipPath)
+			return "", err
+		}
+
+		if cfw.GetCFW() == cfw.MuOS {
+			if err := muos.OrganizeMultiFileRom(extractDir, romDirectory, g.FsNameNoExt); err != nil {
+				logger.Error("Failed to organize multi-file ROM for muOS", "game", g.FsNameNoExt, "error", err)
+				os.Remove(tmpZipPath)
+				os.RemoveAll(extractDir)
+				return "", err
+			}
+		}
+
+		if err := os.Remove(tmpZipPath); err != nil {
+			logger.Warn("Failed to remove temp zip file", "path", tmpZipPath, "error", err)
+		}
+
+		return "", nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(g.Files[0].FileName))
+	archivePath := filepath.Join(romDirectory, g.Files[0].FileName)
+
+	logger.Debug("Extracting single-file ROM", "game", g.Name, "file", archivePath)
+
+	var archiveFiles []string
+	var err error
+	if ext == ".7z" {
+		archiveFiles, err = fileutil.SevenZipFileNames(archivePath)
+		if err == nil {
+			err = fileutil.Un7zip(archivePath, romDirectory, progress)
+		}
+	} else {
+		archiveFiles, err = fileutil.ZipFileNames(archivePath)
+		if err == nil {
+			err = fileutil.Unzip(archivePath, romDirectory, progress)
+		}
+	}
+
+	if err != nil {
+		logger.Warn("Failed to extract ROM, keeping archive file", "game", g.Name, "error", err)
+		return "", err
+	}
+
+	if err := os.Remove(archivePath); err != nil {
+		logger.Warn("Failed to remove archive file after extraction", "path", archivePath, "error", err)
+	}
+
+	if len(archiveFiles) == 0 {
+		return "", nil
+	}
+
+	gamePath := archiveFiles[0]
+	if len(archiveFiles) > 1 {
+		for _, f := range archiveFiles {
+			if strings.ToLower(filepath.Ext(f)) == ".m3u" {
+				gamePath = f
+				break
+			}
+		}
+	}
+
+	return filepath.Join(romDirectory, gamePath), nil
+}
+
+func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host, platform romm.Platform, games []romm.Rom, selectedFileID int) ([]download.Request, []artDownload, []gamelist.RomGameEntry) {
+	downloads := make([]download.Request, 0, len(games))
 	artDownloads := make([]artDownload, 0, len(games))
 	gamesSummaries := make([]gamelist.RomGameEntry, 0, len(games))
 
@@ -349,18 +343,12 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			RomDirectory: "",
 			Platform:     &platform,
 		}
-		gamePlatform := platform
-		if platform.ID == 0 && g.PlatformID != 0 {
-			gamePlatform = romm.Platform{
-				ID:     g.PlatformID,
-				FSSlug: g.PlatformFSSlug,
-				Name:   g.PlatformDisplayName,
-			}
-		}
+		gamePlatform := resolveGamePlatform(platform, g)
 
 		romDirectory := config.GetPlatformRomDirectory(gamePlatform)
 		gamelistRomEntry.RomDirectory = romDirectory
 		downloadLocation := ""
+		checksum := download.Checksum{}
 
 		sourceURL := ""
 
@@ -380,17 +368,19 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 				}
 			}
 			downloadLocation = filepath.Join(romDirectory, fileToDownload.FileName)
+			checksum = fileChecksum(g, fileToDownload)
 			sourceURL, _ = url.JoinPath(host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", fileToDownload.FileName)
 			sourceURL += "?" + url.Values{"file_ids": {strconv.Itoa(fileToDownload.ID)}}.Encode()
 		}
 
 		gamelistRomEntry.GamePath = downloadLocation
 
-		downloads = append(downloads, gaba.Download{
+		downloads = append(downloads, download.Request{
 			URL:         sourceURL,
 			Location:    downloadLocation,
 			DisplayName: g.Name,
 			Timeout:     config.DownloadTimeout,
+			Checksum:    checksum,
 		})
 
 		if config.DownloadArt && (g.PathCoverLarge != "" || g.PathCoverSmall != "" || g.URLCover != "") {
@@ -413,6 +403,98 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 	return downloads, artDownloads, gamesSummaries
 }
 
+// fileChecksum returns what a downloaded file should be verified against. RomM hashes the
+// contents of archives rather than the archive itself, so those are only checked by size.
+// Multi-file games are served as a zip built on the fly and are not verified at all.
+func fileChecksum(g romm.Rom, f romm.RomFile) download.Checksum {
+	checksum := download.Checksum{Size: f.FileSizeBytes}
+
+	switch strings.ToLower(filepath.Ext(f.FileName)) {
+	case ".zip", ".7z", ".gz", ".tar", ".bz2":
+		return checksum
+	}
+
+	checksum.CRC32 = f.CrcHash
+	checksum.MD5 = f.Md5Hash
+	checksum.SHA1 = f.Sha1Hash
+
+	if checksum.CRC32 == "" && checksum.MD5 == "" && checksum.SHA1 == "" && len(g.Files) == 1 {
+		checksum.CRC32 = g.CrcHash
+		checksum.MD5 = g.Md5Hash
+		checksum.SHA1 = g.Sha1Hash
+	}
+
+	return checksum
+}
+
+// fetchAll downloads each request in turn behind a progress screen. Cancelling stops the
+// current download, leaving its partial file in place to resume later.
+func (s *DownloadScreen) fetchAll(requests []download.Request) (download.Result, error) {
+	result := download.Result{}
+
+	for i, req := range requests {
+		progress := &atomic.Float64{}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		_, err := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "download_progress", Other: "Downloading {{.Name}} ({{.Current}}/{{.Total}})..."}, map[string]interface{}{
+				"Name":    req.DisplayName,
+				"Current": i + 1,
+				"Total":   len(requests),
+			}),
+			gaba.ProcessMessageOptions{
+				ShowThemeBackground: true,
+				ShowProgressBar:     true,
+				Progress:            progress,
+				CancelButton:        buttons.VirtualButtonB,
+				FooterHelpItems:     []gaba.FooterHelpItem{FooterCancel()},
+			},
+			func() (interface{}, error) {
+				fetchErr := download.Fetch(ctx, req, progress)
+				done <- fetchErr
+				return nil, nil
+			},
+		)
+
+		if errors.Is(err, gaba.ErrCancelled) {
+			cancel()
+			<-done
+			return result, err
+		}
+
+		fetchErr := <-done
+		cancel()
+
+		if fetchErr != nil {
+			result.Failed = append(result.Failed, download.Failure{Request: req, Err: fetchErr})
+			continue
+		}
+		result.Completed = append(result.Completed, req)
+	}
+
+	return result, nil
+}
+
+func (s *DownloadScreen) showFailures(failures []download.Failure) {
+	lines := make([]string, 0, len(failures)+1)
+	lines = append(lines, i18n.Localize(&goi18n.Message{ID: "download_failed_title", Other: "Some downloads failed:"}, nil))
+
+	for _, f := range failures {
+		if errors.Is(f.Err, download.ErrChecksumMismatch) {
+			lines = append(lines, i18n.Localize(&goi18n.Message{ID: "download_failed_checksum", Other: "{{.Name}}: file is corrupt (checksum mismatch)"}, map[string]interface{}{"Name": f.Request.DisplayName}))
+		} else {
+			lines = append(lines, i18n.Localize(&goi18n.Message{ID: "download_failed_error", Other: "{{.Name}}: download failed, retry to resume"}, map[string]interface{}{"Name": f.Request.DisplayName}))
+		}
+	}
+
+	gaba.ConfirmationMessage(
+		strings.Join(lines, "\n"),
+		[]gaba.FooterHelpItem{FooterContinue()},
+		gaba.MessageOptions{},
+	)
+}
+
 func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames []romm.Rom, headers map[string]string, progress *atomic.Float64, insecureSkipVerify bool) {
 	logger := gaba.GetLogger()
 
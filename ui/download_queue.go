@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type DownloadQueueInput struct {
+	LastSelectedIndex    int
+	LastSelectedPosition int
+}
+
+type DownloadQueueOutput struct {
+	Action               DownloadQueueAction
+	LastSelectedIndex    int
+	LastSelectedPosition int
+}
+
+type DownloadQueueScreen struct{}
+
+func NewDownloadQueueScreen() *DownloadQueueScreen {
+	return &DownloadQueueScreen{}
+}
+
+func (s *DownloadQueueScreen) Draw(input DownloadQueueInput) (DownloadQueueOutput, error) {
+	output := DownloadQueueOutput{
+		Action:               DownloadQueueActionBack,
+		LastSelectedIndex:    input.LastSelectedIndex,
+		LastSelectedPosition: input.LastSelectedPosition,
+	}
+
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return output, nil
+	}
+
+	for {
+		entries, err := cm.GetDownloadQueue()
+		if err != nil {
+			gaba.GetLogger().Error("Failed to load download queue", "error", err)
+			return output, err
+		}
+
+		menuItems := make([]gaba.MenuItem, 0, len(entries))
+		hasPending := false
+		for _, entry := range entries {
+			if entry.Status == cache.DownloadStatusQueued || entry.Status == cache.DownloadStatusActive {
+				hasPending = true
+			}
+			menuItems = append(menuItems, gaba.MenuItem{
+				Text:     fmt.Sprintf("%s [%s] %s", queueStatusLabel(entry), entry.Platform.FSSlug, entry.Rom.Name),
+				Metadata: entry,
+			})
+		}
+
+		footerItems := []gaba.FooterHelpItem{FooterBack()}
+		if len(entries) > 0 {
+			footerItems = append(footerItems,
+				gaba.FooterHelpItem{ButtonName: i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), HelpText: i18n.Localize(&goi18n.Message{ID: "download_queue_clear_done", Other: "Clear Done"}, nil)},
+				gaba.FooterHelpItem{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "download_queue_remove", Other: "Remove"}, nil), Group: gaba.FooterGroupRight},
+				gaba.FooterHelpItem{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "download_queue_retry", Other: "Retry"}, nil), Group: gaba.FooterGroupRight},
+			)
+		}
+		if hasPending {
+			footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "download_queue_start", Other: "Start"}, nil), Group: gaba.FooterGroupRight})
+		}
+
+		options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "download_queue_title", Other: "Download Queue"}, nil), menuItems)
+		options.UseSmallTitle = true
+		options.EmptyMessage = i18n.Localize(&goi18n.Message{ID: "download_queue_empty", Other: "No downloads queued"}, nil)
+		options.ActionButton = buttons.VirtualButtonX
+		options.SecondaryActionButton = buttons.VirtualButtonY
+		options.TertiaryActionButton = buttons.VirtualButtonMenu
+		options.FooterHelpItems = footerItems
+		options.SelectedIndex = min(output.LastSelectedIndex, max(0, len(menuItems)-1))
+		options.VisibleStartIndex = max(0, options.SelectedIndex-output.LastSelectedPosition)
+		options.StatusBar = StatusBar()
+
+		res, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return output, nil
+			}
+			return output, err
+		}
+
+		if len(res.Selected) > 0 {
+			output.LastSelectedIndex = res.Selected[0]
+			output.LastSelectedPosition = res.VisiblePosition
+		}
+
+		switch res.Action {
+		case gaba.ListActionSelected:
+			if hasPending {
+				output.Action = DownloadQueueActionStart
+				return output, nil
+			}
+
+		case gaba.ListActionTriggered:
+			if entry, ok := selectedQueueEntry(res); ok && entry.Status == cache.DownloadStatusFailed {
+				if err := cm.RetryDownload(entry.ID); err != nil {
+					gaba.GetLogger().Error("Failed to retry download", "rom", entry.Rom.Name, "error", err)
+				}
+			}
+
+		case gaba.ListActionSecondaryTriggered:
+			if entry, ok := selectedQueueEntry(res); ok {
+				if err := cm.RemoveDownload(entry.ID); err != nil {
+					gaba.GetLogger().Error("Failed to remove download", "rom", entry.Rom.Name, "error", err)
+				}
+			}
+
+		case gaba.ListActionTertiaryTriggered:
+			if err := cm.ClearFinishedDownloads(); err != nil {
+				gaba.GetLogger().Error("Failed to clear finished downloads", "error", err)
+			}
+		}
+	}
+}
+
+func selectedQueueEntry(res *gaba.ListResult) (cache.QueuedDownload, bool) {
+	if len(res.Selected) == 0 {
+		return cache.QueuedDownload{}, false
+	}
+	entry, ok := res.Items[res.Selected[0]].Metadata.(cache.QueuedDownload)
+	return entry, ok
+}
+
+func queueStatusLabel(entry cache.QueuedDownload) string {
+	switch entry.Status {
+	case cache.DownloadStatusActive:
+		return i18n.Localize(&goi18n.Message{ID: "download_queue_status_active", Other: "[Downloading]"}, nil)
+	case cache.DownloadStatusFailed:
+		return i18n.Localize(&goi18n.Message{ID: "download_queue_status_failed", Other: "[Failed]"}, nil)
+	case cache.DownloadStatusDone:
+		return i18n.Localize(&goi18n.Message{ID: "download_queue_status_done", Other: "[Done]"}, nil)
+	default:
+		if entry.RetryCount > 0 {
+			return i18n.Localize(&goi18n.Message{ID: "download_queue_status_retrying", Other: "[Retry {{.Count}}/{{.Max}}]"}, map[string]interface{}{"Count": entry.RetryCount, "Max": cache.MaxDownloadRetries})
+		}
+		return i18n.Localize(&goi18n.Message{ID: "download_queue_status_queued", Other: "[Queued]"}, nil)
+	}
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"grout/cache"
+	"grout/internal"
+	"grout/internal/download"
+	"grout/internal/fileutil"
+	"grout/internal/stringutil"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// extractionRatio is how much bigger an archive is assumed to get once extracted.
+// ROM archives typically compress to around half their size.
+const extractionRatio = 2
+
+// spaceNeed is what a set of downloads needs on one filesystem. Archives are removed once
+// extracted, so only the largest one has to fit on top of everything that is kept.
+type spaceNeed struct {
+	disk      fileutil.DiskSpace
+	kept      int64
+	transient int64
+}
+
+func (n spaceNeed) total() int64 {
+	return n.kept + n.transient
+}
+
+// downloadSize returns the number of bytes that will be fetched for a game.
+func downloadSize(g romm.Rom, fileID int) int64 {
+	if g.HasMultipleFiles || len(g.Files) == 0 {
+		return g.FsSizeBytes
+	}
+
+	file := g.Files[0]
+	for _, f := range g.Files {
+		if fileID > 0 && f.ID == fileID {
+			file = f
+			break
+		}
+	}
+	if file.FileSizeBytes > 0 {
+		return file.FileSizeBytes
+	}
+	return g.FsSizeBytes
+}
+
+// estimateSpace adds up the space games need on each filesystem they touch, keyed by
+// filesystem ID. Multi-file games are fetched into the temp folder and extracted into the
+// ROM folder; single-file archives are extracted next to themselves when UnzipDownloads is on.
+// Every download is staged in the partial folder first. When that is on another filesystem
+// than where the file ends up, it is copied over, so the staged copy needs room as well.
+func estimateSpace(config internal.Config, needs map[string]*spaceNeed, platform romm.Platform, games []romm.Rom, fileID int) {
+	logger := gaba.GetLogger()
+
+	need := func(path string) *spaceNeed {
+		disk, err := fileutil.GetDiskSpace(path)
+		if err != nil {
+			logger.Warn("Unable to check free space", "path", path, "error", err)
+			return nil
+		}
+		if needs[disk.FilesystemID] == nil {
+			needs[disk.FilesystemID] = &spaceNeed{disk: disk}
+		}
+		return needs[disk.FilesystemID]
+	}
+
+	stagingNeed := need(download.PartialDir())
+	stage := func(dest *spaceNeed, size int64) {
+		if stagingNeed != nil && stagingNeed != dest {
+			stagingNeed.transient = max(stagingNeed.transient, size)
+		}
+	}
+
+	for _, g := range games {
+		size := downloadSize(g, fileID)
+		romNeed := need(config.GetPlatformRomDirectory(resolveGamePlatform(platform, g)))
+		if romNeed == nil {
+			continue
+		}
+
+		switch {
+		case g.HasMultipleFiles:
+			romNeed.kept += size
+			tmpNeed := need(fileutil.TempDir())
+			if tmpNeed != nil {
+				tmpNeed.transient = max(tmpNeed.transient, size)
+			}
+			stage(tmpNeed, size)
+		case needsExtraction(config, g):
+			romNeed.kept += size * extractionRatio
+			romNeed.transient = max(romNeed.transient, size)
+			stage(romNeed, size)
+		default:
+			romNeed.kept += size
+			stage(romNeed, size)
+		}
+	}
+}
+
+// CheckFreeSpace reports whether games, together with anything still waiting in the download
+// queue, fit on the card. When they don't, it returns the space needed and free on the first
+// filesystem that is short.
+func CheckFreeSpace(config internal.Config, platform romm.Platform, games []romm.Rom, fileID int) (needed int64, free uint64, ok bool) {
+	needs := make(map[string]*spaceNeed)
+	estimateSpace(config, needs, platform, games, fileID)
+
+	adding := make(map[int]bool, len(games))
+	for _, g := range games {
+		adding[g.ID] = true
+	}
+
+	if cm := cache.GetCacheManager(); cm != nil {
+		if queued, err := cm.GetDownloadQueue(cache.DownloadStatusQueued, cache.DownloadStatusActive); err == nil {
+			for _, entry := range queued {
+				// Queueing a game again replaces its entry, so it only needs room once
+				if adding[entry.Rom.ID] && entry.FileID == fileID {
+					continue
+				}
+				estimateSpace(config, needs, entry.Platform, []romm.Rom{entry.Rom}, entry.FileID)
+			}
+		}
+	}
+
+	for _, n := range needs {
+		if uint64(n.total()) > n.disk.Free {
+			return n.total(), n.disk.Free, false
+		}
+	}
+	return 0, 0, true
+}
+
+// ConfirmFreeSpace checks that games fit on the card before they are downloaded, see
+// CheckFreeSpace. When they don't, the user is told how much space is missing and can cancel
+// or download anyway. Returns true to go ahead.
+func ConfirmFreeSpace(config internal.Config, platform romm.Platform, games []romm.Rom, fileID int) bool {
+	needed, free, ok := CheckFreeSpace(config, platform, games, fileID)
+	if ok {
+		return true
+	}
+
+	gaba.GetLogger().Warn("Not enough free space for download", "needed", needed, "free", free)
+
+	result, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "download_insufficient_space", Other: "Not enough free space.\n{{.Needed}} needed, {{.Free}} available.\nDownload anyway?"}, map[string]interface{}{
+			"Needed": stringutil.FormatBytes(needed),
+			"Free":   stringutil.FormatBytes(int64(free)),
+		}),
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			footerItem("A", "button_download_anyway", "Download Anyway"),
+		},
+		gaba.MessageOptions{},
+	)
+	return err == nil && result != nil && result.Confirmed
+}
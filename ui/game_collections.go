@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"errors"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const favouritesCollectionName = "Favourites"
+
+type GameCollectionsInput struct {
+	Config *internal.Config
+	Host   romm.Host
+	Games  []romm.Rom
+}
+
+type GameCollectionsOutput struct {
+	Action GameCollectionsAction
+}
+
+// GameCollectionsScreen adds games to, or removes them from, the user's favourites and
+// their own collections in RomM. A collection starts selected when it holds every game.
+type GameCollectionsScreen struct{}
+
+func NewGameCollectionsScreen() *GameCollectionsScreen {
+	return &GameCollectionsScreen{}
+}
+
+type collectionChange struct {
+	collection romm.Collection
+	add        bool
+}
+
+func (s *GameCollectionsScreen) Draw(input GameCollectionsInput) (GameCollectionsOutput, error) {
+	output := GameCollectionsOutput{Action: GameCollectionsActionBack}
+	logger := gaba.GetLogger()
+
+	if internal.IsOffline() {
+		ShowOfflineMessage(&goi18n.Message{ID: "game_collections_offline", Other: "RomM can't be reached right now.\nCollections can be changed once it is back online."})
+		return output, nil
+	}
+
+	collections, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "game_collections_loading", Other: "Loading collections..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() ([]romm.Collection, error) {
+			return fetchOwnCollections(input.Host, input.Config)
+		},
+	)
+	if err != nil {
+		logger.Error("Failed to load collections", "error", err)
+		internal.NoteRequestError(err)
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "game_collections_load_failed", Other: "Unable to load your collections.\nPlease try again later."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return output, nil
+	}
+
+	romIDs := make([]int, len(input.Games))
+	for i, game := range input.Games {
+		romIDs[i] = game.ID
+	}
+
+	title := input.Games[0].DisplayName
+	if len(input.Games) > 1 {
+		title = i18n.Localize(&goi18n.Message{ID: "game_collections_title_multiple", Other: "{{.Count}} Games"}, map[string]interface{}{"Count": len(input.Games)})
+	}
+
+	menuItems := make([]gaba.MenuItem, 0, len(collections))
+	for _, collection := range collections {
+		menuItems = append(menuItems, collectionMenuItem(collection, containsAll(collection.ROMIDs, romIDs)))
+	}
+
+	for {
+		saveItem := FooterSave()
+		saveItem.IsConfirmButton = true
+
+		options := gaba.DefaultListOptions(title, menuItems)
+		options.UseSmallTitle = true
+		options.InitialMultiSelectMode = true
+		options.ActionButton = buttons.VirtualButtonX
+		options.FooterHelpItems = []gaba.FooterHelpItem{
+			FooterBack(),
+			footerItem("X", "button_new_collection", "New Collection"),
+			saveItem,
+		}
+		options.StatusBar = StatusBar()
+
+		sel, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return output, nil
+			}
+			logger.Error("Collection selection failed", "error", err)
+			return output, err
+		}
+
+		if sel.Action == gaba.ListActionTriggered {
+			// Keep what was ticked so far, with the new collection ticked as well
+			menuItems = sel.Items
+			for i := range menuItems {
+				menuItems[i].Selected = slices.Contains(sel.Selected, i)
+			}
+			if collection, ok := createCollectionUI(input.Host, input.Config); ok {
+				menuItems = append(menuItems, collectionMenuItem(collection, true))
+			}
+			continue
+		}
+
+		if sel.Action != gaba.ListActionSelected {
+			return output, nil
+		}
+
+		var changes []collectionChange
+		for i, item := range sel.Items {
+			collection := item.Metadata.(romm.Collection)
+			member := containsAll(collection.ROMIDs, romIDs)
+			selected := slices.Contains(sel.Selected, i)
+			// Collections holding only some of the games are left alone unless ticked
+			if member != selected {
+				changes = append(changes, collectionChange{collection: collection, add: selected})
+			}
+		}
+
+		if len(changes) > 0 {
+			applyCollectionChanges(input.Host, input.Config, romIDs, changes)
+		}
+
+		return output, nil
+	}
+}
+
+func collectionMenuItem(collection romm.Collection, selected bool) gaba.MenuItem {
+	name := collection.Name
+	if collection.IsFavorite {
+		name = i18n.Localize(&goi18n.Message{ID: "game_collections_favourites", Other: "Favourites"}, nil)
+	}
+	return gaba.MenuItem{
+		Text:     name,
+		Selected: selected,
+		Metadata: collection,
+	}
+}
+
+// createCollectionUI asks for a name and creates an empty private collection with it.
+// Returns false if nothing was created.
+func createCollectionUI(host romm.Host, config *internal.Config) (romm.Collection, bool) {
+	logger := gaba.GetLogger()
+
+	res, err := gaba.Keyboard("", i18n.Localize(&goi18n.Message{ID: "help_exit_text", Other: "Press any button to close help"}, nil))
+	if err != nil {
+		if !errors.Is(err, gaba.ErrCancelled) {
+			logger.Error("Error with keyboard", "error", err)
+		}
+		return romm.Collection{}, false
+	}
+
+	name := strings.TrimSpace(res.Text)
+	if name == "" {
+		return romm.Collection{}, false
+	}
+
+	collection, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_editor_creating", Other: "Creating collection..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (romm.Collection, error) {
+			client := romm.NewClientFromHost(host, config.ApiTimeout)
+			return client.CreateCollection(romm.Collection{Name: name})
+		},
+	)
+	if err != nil {
+		logger.Error("Failed to create collection", "name", name, "error", err)
+		internal.NoteRequestError(err)
+		showCollectionSaveFailed()
+		return romm.Collection{}, false
+	}
+
+	if err := cache.GetCacheManager().SaveCollection(collection); err != nil {
+		logger.Warn("Unable to cache new collection", "collection", collection.Name, "error", err)
+	}
+	return collection, true
+}
+
+func showCollectionSaveFailed() {
+	gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_editor_save_failed", Other: "Unable to save the collection.\nCheck the logs for details."}, nil),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+}
+
+// fetchOwnCollections returns the regular collections the user can change, favourites
+// first. A favourites collection that doesn't exist yet is returned with no ID so it can
+// be created when the first game is added to it.
+func fetchOwnCollections(host romm.Host, config *internal.Config) ([]romm.Collection, error) {
+	client := romm.NewClientFromHost(host, config.ApiTimeout)
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := client.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	favourites := romm.Collection{Name: favouritesCollectionName, IsFavorite: true}
+	var own []romm.Collection
+	for _, collection := range collections {
+		if collection.UserID != user.ID || collection.IsSmart || collection.IsVirtual {
+			continue
+		}
+		if collection.IsFavorite {
+			favourites = collection
+			continue
+		}
+		own = append(own, collection)
+	}
+
+	return append([]romm.Collection{favourites}, own...), nil
+}
+
+// applyCollectionChanges shows changes in the cache straight away and writes them to
+// RomM in the background.
+func applyCollectionChanges(host romm.Host, config *internal.Config, romIDs []int, changes []collectionChange) {
+	logger := gaba.GetLogger()
+	cm := cache.GetCacheManager()
+
+	for _, change := range changes {
+		if change.collection.ID == 0 {
+			continue
+		}
+		collection := change.collection
+		collection.ROMIDs = withRomIDs(collection.ROMIDs, romIDs, change.add)
+		if err := cm.SaveCollection(collection); err != nil {
+			logger.Warn("Unable to update cached collection", "collection", collection.Name, "error", err)
+		}
+	}
+
+	go pushCollectionChanges(host, config, romIDs, changes)
+}
+
+// pushCollectionChanges writes changes to RomM. Each collection is fetched again first so
+// games added to it elsewhere since the list was loaded aren't dropped. A write that
+// fails makes the next sync fetch every collection, which undoes it in the cache.
+func pushCollectionChanges(host romm.Host, config *internal.Config, romIDs []int, changes []collectionChange) {
+	logger := gaba.GetLogger()
+	client := romm.NewClientFromHost(host, config.ApiTimeout)
+	cm := cache.GetCacheManager()
+
+	for _, change := range changes {
+		var collection romm.Collection
+		var err error
+		if change.collection.ID == 0 {
+			collection, err = client.CreateCollection(change.collection)
+		} else {
+			collection, err = client.GetCollection(change.collection.ID)
+		}
+		if err == nil {
+			collection.ROMIDs = withRomIDs(collection.ROMIDs, romIDs, change.add)
+			collection, err = client.UpdateCollection(collection)
+		}
+
+		if err != nil {
+			logger.Error("Failed to update collection in RomM", "collection", change.collection.Name, "games", len(romIDs), "add", change.add, "error", err)
+			internal.NoteRequestError(err)
+			if err := cm.InvalidateCollections(); err != nil {
+				logger.Warn("Unable to invalidate cached collections", "error", err)
+			}
+			continue
+		}
+
+		logger.Debug("Updated collection in RomM", "collection", collection.Name, "games", len(romIDs), "add", change.add)
+		if err := cm.SaveCollection(collection); err != nil {
+			logger.Warn("Unable to update cached collection", "collection", collection.Name, "error", err)
+		}
+	}
+}
+
+// withRomIDs returns romIDs with every one of ids added to it, or removed from it.
+func withRomIDs(romIDs []int, ids []int, add bool) []int {
+	result := slices.DeleteFunc(slices.Clone(romIDs), func(id int) bool { return slices.Contains(ids, id) })
+	if add {
+		result = append(result, ids...)
+	}
+	return result
+}
+
+func containsAll(romIDs []int, ids []int) bool {
+	for _, id := range ids {
+		if !slices.Contains(romIDs, id) {
+			return false
+		}
+	}
+	return true
+}
@@ -291,6 +291,10 @@ func (s *GameDetailsScreen) getCoverImagePath(config *internal.Config, host romm
 		return cachePath
 	}
 
+	if internal.IsOffline() {
+		return ""
+	}
+
 	coverURL := game.GetArtworkURL(config.ArtKind, host)
 	imageData := s.fetchImageFromURL(host, coverURL)
 
@@ -319,7 +323,7 @@ func (s *GameDetailsScreen) fetchImageFromURL(host romm.Host, imageURL string) [
 		return nil
 	}
 
-	req.SetBasicAuth(host.Username, host.Password)
+	req.Header.Set("Authorization", host.AuthHeader())
 
 	client := &http.Client{Timeout: internal.DefaultHTTPTimeout}
 	resp, err := client.Do(req)
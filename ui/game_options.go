@@ -39,6 +39,20 @@ func (s *GameOptionsScreen) Draw(input GameOptionsInput) (GameOptionsOutput, err
 
 	items := s.buildMenuItems(config, input.Game)
 
+	saveHistoryText := i18n.Localize(&goi18n.Message{ID: "game_options_save_history", Other: "Save History"}, nil)
+	items = append(items, gaba.ItemWithOptions{
+		Item:           gaba.MenuItem{Text: saveHistoryText},
+		Options:        []gaba.Option{{DisplayName: "", Value: "save_history", Type: gaba.OptionTypeClickable}},
+		SelectedOption: 0,
+	})
+
+	collectionsText := i18n.Localize(&goi18n.Message{ID: "game_options_collections", Other: "Favourites & Collections"}, nil)
+	items = append(items, gaba.ItemWithOptions{
+		Item:           gaba.MenuItem{Text: collectionsText},
+		Options:        []gaba.Option{{DisplayName: "", Value: "collections", Type: gaba.OptionTypeClickable}},
+		SelectedOption: 0,
+	})
+
 	showQRText := i18n.Localize(&goi18n.Message{ID: "game_options_show_qr", Other: "Show QR Code"}, nil)
 	items = append(items, gaba.ItemWithOptions{
 		Item:           gaba.MenuItem{Text: showQRText},
@@ -74,6 +88,14 @@ func (s *GameOptionsScreen) Draw(input GameOptionsInput) (GameOptionsOutput, err
 				output.Action = GameOptionsActionShowQR
 				return output, nil
 			}
+			if selectedItem.Item.Text == saveHistoryText {
+				output.Action = GameOptionsActionSaveHistory
+				return output, nil
+			}
+			if selectedItem.Item.Text == collectionsText {
+				output.Action = GameOptionsActionCollections
+				return output, nil
+			}
 		}
 	}
 
@@ -132,6 +154,15 @@ func (s *GameOptionsScreen) buildMenuItems(config *internal.Config, game romm.Ro
 		})
 	}
 
+	items = append(items, gaba.ItemWithOptions{
+		Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "game_options_sync_saves", Other: "Sync Saves"}, nil)},
+		Options: []gaba.Option{
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_true", Other: "True"}, nil), Value: true},
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_false", Other: "False"}, nil), Value: false},
+		},
+		SelectedOption: boolToIndex(config.ExcludedSyncGames[game.ID]),
+	})
+
 	return items
 }
 
@@ -141,6 +172,22 @@ func (s *GameOptionsScreen) applySettings(config *internal.Config, game romm.Rom
 	for _, item := range items {
 		text := item.Item.Text
 
+		if text == i18n.Localize(&goi18n.Message{ID: "game_options_sync_saves", Other: "Sync Saves"}, nil) {
+			syncSaves, ok := item.Options[item.SelectedOption].Value.(bool)
+			if !ok {
+				continue
+			}
+			if syncSaves {
+				delete(config.ExcludedSyncGames, game.ID)
+			} else {
+				if config.ExcludedSyncGames == nil {
+					config.ExcludedSyncGames = make(map[int]bool)
+				}
+				config.ExcludedSyncGames[game.ID] = true
+			}
+			continue
+		}
+
 		if text == i18n.Localize(&goi18n.Message{ID: "game_options_save_directory", Other: "Save Directory"}, nil) {
 			newDir, ok := item.Options[item.SelectedOption].Value.(string)
 			if !ok {
@@ -258,7 +258,7 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 	options.SelectAllButton = gabaconst.VirtualButtonR1
 	options.SecondaryActionButton = gabaconst.VirtualButtonY
 
-	if hasBIOS && !internal.IsKidModeEnabled() {
+	if !internal.IsKidModeEnabled() {
 		options.TertiaryActionButton = gabaconst.VirtualButtonMenu
 	}
 
@@ -266,8 +266,12 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 
 	footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_back", Other: "Back"}, nil)})
 
-	if hasBIOS && !internal.IsKidModeEnabled() {
-		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), HelpText: i18n.Localize(&goi18n.Message{ID: "button_bios", Other: "BIOS"}, nil)})
+	if !internal.IsKidModeEnabled() {
+		menuHelp := i18n.Localize(&goi18n.Message{ID: "button_collections", Other: "Collections"}, nil)
+		if hasBIOS {
+			menuHelp = i18n.Localize(&goi18n.Message{ID: "button_more", Other: "More"}, nil)
+		}
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), HelpText: menuHelp})
 	}
 
 	footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_filters", Other: "Filters"}, nil), Group: gaba.FooterGroupRight})
@@ -280,47 +284,94 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 	options.VisibleStartIndex = max(0, input.LastSelectedIndex-input.LastSelectedPosition)
 	options.StatusBar = StatusBar()
 
-	res, err := gaba.List(options)
-	if err != nil {
-		if errors.Is(err, gaba.ErrCancelled) {
-			if clearLastFilter(&output, input.LastApplied) {
+	for {
+		res, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				if clearLastFilter(&output, input.LastApplied) {
+					return output, nil
+				}
+				output.Action = GameListActionBack
 				return output, nil
 			}
-			output.Action = GameListActionBack
-			return output, nil
+			return output, err
 		}
-		return output, err
-	}
 
-	switch res.Action {
-	case gaba.ListActionSelected:
-		selectedGames := make([]romm.Rom, 0, len(res.Selected))
-		for _, idx := range res.Selected {
-			selectedGames = append(selectedGames, res.Items[idx].Metadata.(romm.Rom))
-		}
-		output.LastSelectedIndex = res.Selected[0]
-		output.LastSelectedPosition = res.VisiblePosition
-		output.SelectedGames = selectedGames
-		output.Action = GameListActionSelected
-		return output, nil
+		switch res.Action {
+		case gaba.ListActionSelected:
+			selectedGames := make([]romm.Rom, 0, len(res.Selected))
+			for _, idx := range res.Selected {
+				selectedGames = append(selectedGames, res.Items[idx].Metadata.(romm.Rom))
+			}
+			// The list only holds what it shows, the screens that follow need everything
+			if cm := cache.GetCacheManager(); cm != nil {
+				selectedGames = cm.LoadFullGames(selectedGames)
+			}
+			output.LastSelectedIndex = res.Selected[0]
+			output.LastSelectedPosition = res.VisiblePosition
+			output.SelectedGames = selectedGames
+			output.Action = GameListActionSelected
+			return output, nil
 
-	case gaba.ListActionTriggered:
-		output.Action = GameListActionSearch
-		return output, nil
+		case gaba.ListActionTriggered:
+			output.Action = GameListActionSearch
+			return output, nil
 
-	case gaba.ListActionSecondaryTriggered:
-		output.LastSelectedIndex = res.Selected[0]
-		output.LastSelectedPosition = res.VisiblePosition
-		output.Action = GameListActionFilters
-		return output, nil
+		case gaba.ListActionSecondaryTriggered:
+			output.LastSelectedIndex = res.Selected[0]
+			output.LastSelectedPosition = res.VisiblePosition
+			output.Action = GameListActionFilters
+			return output, nil
 
-	case gaba.ListActionTertiaryTriggered:
-		output.Action = GameListActionBIOS
+		case gaba.ListActionTertiaryTriggered:
+			action, ok := s.chooseMenuAction(len(res.Selected) > 0, hasBIOS)
+			if !ok {
+				// Back to the list where it was left
+				if len(res.Selected) > 0 {
+					options.SelectedIndex = res.Selected[0]
+					options.VisibleStartIndex = max(0, res.Selected[0]-res.VisiblePosition)
+				}
+				continue
+			}
+			if action == GameListActionCollections {
+				output.LastSelectedIndex = res.Selected[0]
+				output.LastSelectedPosition = res.VisiblePosition
+				output.SelectedGames = make([]romm.Rom, 0, len(res.Selected))
+				for _, idx := range res.Selected {
+					output.SelectedGames = append(output.SelectedGames, res.Items[idx].Metadata.(romm.Rom))
+				}
+			}
+			output.Action = action
+			return output, nil
+		}
+
+		output.Action = GameListActionBack
 		return output, nil
 	}
+}
+
+// chooseMenuAction asks what the menu button should open, going straight there when the
+// focused game's collections or the platform's BIOS files are the only choice.
+func (s *GameListScreen) chooseMenuAction(hasGame, hasBIOS bool) (GameListAction, bool) {
+	var items []gaba.MenuItem
+	if hasGame {
+		items = append(items, gaba.MenuItem{
+			Text:     i18n.Localize(&goi18n.Message{ID: "game_options_collections", Other: "Favourites & Collections"}, nil),
+			Metadata: GameListActionCollections,
+		})
+	}
+	if hasBIOS {
+		items = append(items, gaba.MenuItem{
+			Text:     i18n.Localize(&goi18n.Message{ID: "button_bios", Other: "BIOS"}, nil),
+			Metadata: GameListActionBIOS,
+		})
+	}
 
-	output.Action = GameListActionBack
-	return output, nil
+	action, ok := chooseAction(i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), items)
+	if !ok {
+		return GameListActionBack, false
+	}
+	return action.(GameListAction), true
 }
 
 type loadGamesResult struct {
@@ -375,6 +426,11 @@ func (s *GameListScreen) loadGames(input GameListInput) (loadGamesResult, error)
 		}
 	}
 
+	// Offline there is nothing beyond the cache to show
+	if internal.IsOffline() {
+		return loadGamesResult{}, internal.ErrOffline
+	}
+
 	// Cache miss or stale - show loading screen and fetch
 	var loadErr error
 
@@ -439,6 +495,7 @@ func (s *GameListScreen) loadGames(input GameListInput) (loadGamesResult, error)
 		)
 
 		if err != nil || loadErr != nil {
+			internal.NoteRequestError(loadErr)
 			return loadGamesResult{}, fmt.Errorf("failed to load games: %w", err)
 		}
 
@@ -513,6 +570,7 @@ func (s *GameListScreen) loadGames(input GameListInput) (loadGamesResult, error)
 	)
 
 	if err != nil || loadErr != nil {
+		internal.NoteRequestError(loadErr)
 		return loadGamesResult{}, fmt.Errorf("failed to load games: %w", err)
 	}
 
@@ -554,7 +612,9 @@ func (s *GameListScreen) showErrorMessage(err error) {
 	var message string
 
 	classifiedErr := romm.ClassifyError(err)
-	if errors.Is(classifiedErr, romm.ErrTimeout) {
+	if errors.Is(err, internal.ErrOffline) {
+		message = i18n.Localize(&goi18n.Message{ID: "games_list_offline", Other: "Offline!\nThese games aren't in the cache yet."}, nil)
+	} else if errors.Is(classifiedErr, romm.ErrTimeout) {
 		message = i18n.Localize(&goi18n.Message{ID: "games_list_load_timeout", Other: "Connection timed out!\nPlease check your network connection."}, nil)
 	} else {
 		message = i18n.Localize(&goi18n.Message{ID: "games_list_load_error", Other: "Failed to load games.\nPlease try again later."}, nil)
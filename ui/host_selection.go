@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"errors"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type HostSelectionInput struct {
+	Hosts      []romm.Host
+	ActiveHost romm.Host
+}
+
+type HostSelectionOutput struct {
+	Action HostSelectionAction
+	Host   romm.Host
+}
+
+// HostSelectionScreen lists the configured RomM servers to switch between, add to or
+// remove from. The server being browsed can't be removed.
+type HostSelectionScreen struct{}
+
+func NewHostSelectionScreen() *HostSelectionScreen {
+	return &HostSelectionScreen{}
+}
+
+func (s *HostSelectionScreen) Draw(input HostSelectionInput) (HostSelectionOutput, error) {
+	output := HostSelectionOutput{Action: HostSelectionActionBack}
+
+	var menuItems []gaba.MenuItem
+	selectedIndex := 0
+	for i, host := range input.Hosts {
+		name := HostDisplayName(host)
+		if host.URL() == input.ActiveHost.URL() {
+			name = i18n.Localize(&goi18n.Message{ID: "host_selection_current", Other: "{{.Name}} (Current)"}, map[string]interface{}{"Name": name})
+			selectedIndex = i
+		}
+		menuItems = append(menuItems, gaba.MenuItem{Text: name, Metadata: host})
+	}
+	menuItems = append(menuItems, gaba.MenuItem{
+		Text:     i18n.Localize(&goi18n.Message{ID: "host_selection_add", Other: "Add Server"}, nil),
+		Metadata: romm.Host{},
+	})
+
+	options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "host_selection_title", Other: "Servers"}, nil), menuItems)
+	options.UseSmallTitle = true
+	options.ActionButton = buttons.VirtualButtonX
+	options.SelectedIndex = selectedIndex
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "host_selection_remove", Other: "Remove"}, nil), Group: gaba.FooterGroupRight},
+		FooterSelect(),
+	}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		if errors.Is(err, gaba.ErrCancelled) {
+			return output, nil
+		}
+		return output, err
+	}
+	if len(sel.Selected) == 0 {
+		return output, nil
+	}
+
+	host := sel.Items[sel.Selected[0]].Metadata.(romm.Host)
+	output.Host = host
+
+	switch sel.Action {
+	case gaba.ListActionSelected:
+		if host.URL() == "" {
+			output.Action = HostSelectionActionAdd
+		} else {
+			output.Action = HostSelectionActionSelected
+		}
+	case gaba.ListActionTriggered:
+		if host.URL() != "" && host.URL() != input.ActiveHost.URL() {
+			output.Action = HostSelectionActionRemove
+		}
+	}
+
+	return output, nil
+}
+
+// HostDisplayName is the name a server was given at login, or its address.
+func HostDisplayName(host romm.Host) string {
+	if host.DisplayName != "" {
+		return host.DisplayName
+	}
+	return host.URL()
+}
@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/internal"
+	"grout/internal/stringutil"
+	"grout/sync"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type LocalGamesInput struct {
+	Config *internal.Config
+}
+
+type LocalGamesOutput struct {
+	Action LocalGamesAction
+}
+
+type LocalGamesScreen struct{}
+
+func NewLocalGamesScreen() *LocalGamesScreen {
+	return &LocalGamesScreen{}
+}
+
+type localGamesPlatform struct {
+	FSSlug string
+	Name   string
+	Games  []sync.LocalGame
+}
+
+func (p localGamesPlatform) size() int64 {
+	var total int64
+	for _, g := range p.Games {
+		total += g.Size
+	}
+	return total
+}
+
+func (s *LocalGamesScreen) Draw(input LocalGamesInput) (LocalGamesOutput, error) {
+	output := LocalGamesOutput{Action: LocalGamesActionBack}
+
+	scan, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "local_games_scanning", Other: "Scanning local games..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (map[string][]sync.LocalGame, error) {
+			return sync.ScanLocalGames(input.Config), nil
+		},
+	)
+	if err != nil {
+		return output, err
+	}
+
+	platforms := make([]localGamesPlatform, 0, len(scan))
+	for fsSlug, games := range scan {
+		platforms = append(platforms, localGamesPlatform{FSSlug: fsSlug, Name: games[0].Platform.Name, Games: games})
+	}
+	slices.SortFunc(platforms, func(a, b localGamesPlatform) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	selectedIndex := 0
+	for {
+		platforms = slices.DeleteFunc(platforms, func(p localGamesPlatform) bool { return len(p.Games) == 0 })
+
+		menuItems := make([]gaba.MenuItem, 0, len(platforms))
+		for _, p := range platforms {
+			menuItems = append(menuItems, gaba.MenuItem{
+				Text: i18n.Localize(&goi18n.Message{ID: "local_games_platform_entry", Other: "{{.Name}} ({{.Count}}, {{.Size}})"}, map[string]interface{}{
+					"Name":  p.Name,
+					"Count": len(p.Games),
+					"Size":  stringutil.FormatBytes(p.size()),
+				}),
+				Metadata: p.FSSlug,
+			})
+		}
+
+		options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "local_games_title", Other: "Manage Local Games"}, nil), menuItems)
+		options.UseSmallTitle = true
+		options.EmptyMessage = i18n.Localize(&goi18n.Message{ID: "local_games_empty", Other: "No games found on this device"}, nil)
+		options.FooterHelpItems = []gaba.FooterHelpItem{FooterBack(), FooterSelect()}
+		options.SelectedIndex = min(selectedIndex, max(0, len(menuItems)-1))
+		options.StatusBar = StatusBar()
+
+		res, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return output, nil
+			}
+			return output, err
+		}
+
+		if res.Action != gaba.ListActionSelected || len(res.Selected) == 0 {
+			continue
+		}
+
+		selectedIndex = res.Selected[0]
+		platform := &platforms[selectedIndex]
+		s.drawPlatformGames(input.Config, platform)
+	}
+}
+
+// drawPlatformGames lists the games of one platform and deletes the ones the user picks.
+func (s *LocalGamesScreen) drawPlatformGames(config *internal.Config, platform *localGamesPlatform) {
+	selectedIndex := 0
+
+	for len(platform.Games) > 0 {
+		menuItems := make([]gaba.MenuItem, 0, len(platform.Games))
+		for _, g := range platform.Games {
+			menuItems = append(menuItems, gaba.MenuItem{
+				Text:     fmt.Sprintf("%s (%s)", g.Name, stringutil.FormatBytes(g.Size)),
+				Metadata: g,
+			})
+		}
+
+		options := gaba.DefaultListOptions(platform.Name, menuItems)
+		options.UseSmallTitle = true
+		options.FooterHelpItems = []gaba.FooterHelpItem{
+			FooterBack(),
+			footerItem("A", "button_delete", "Delete"),
+		}
+		options.SelectedIndex = min(selectedIndex, len(menuItems)-1)
+		options.StatusBar = StatusBar()
+
+		res, err := gaba.List(options)
+		if err != nil {
+			return
+		}
+
+		if res.Action != gaba.ListActionSelected || len(res.Selected) == 0 {
+			continue
+		}
+
+		selectedIndex = res.Selected[0]
+		game := platform.Games[selectedIndex]
+
+		deleteSaves, confirmed := s.confirmDelete(game)
+		if !confirmed {
+			continue
+		}
+
+		_, err = gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "local_games_deleting", Other: "Deleting {{.Name}}..."}, map[string]interface{}{"Name": game.Name}),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (interface{}, error) {
+				return nil, sync.DeleteLocalGame(config, game, deleteSaves)
+			},
+		)
+		if err != nil {
+			gaba.GetLogger().Error("Failed to delete local game", "game", game.Name, "error", err)
+			gaba.ConfirmationMessage(
+				i18n.Localize(&goi18n.Message{ID: "local_games_delete_failed", Other: "Some files for {{.Name}} could not be deleted."}, map[string]interface{}{"Name": game.Name}),
+				ContinueFooter(),
+				gaba.MessageOptions{},
+			)
+		}
+
+		platform.Games = slices.Delete(platform.Games, selectedIndex, selectedIndex+1)
+	}
+}
+
+// confirmDelete asks before deleting a game. Games with saves offer to keep or delete them,
+// keeping them being the default. Returns whether to delete the saves and whether to go ahead.
+func (s *LocalGamesScreen) confirmDelete(game sync.LocalGame) (bool, bool) {
+	message := i18n.Localize(&goi18n.Message{ID: "local_games_delete_confirm", Other: "Delete {{.Name}} ({{.Size}})?"}, map[string]interface{}{
+		"Name": game.Name,
+		"Size": stringutil.FormatBytes(game.Size),
+	})
+
+	if len(game.Saves) == 0 {
+		result, err := gaba.ConfirmationMessage(
+			message,
+			[]gaba.FooterHelpItem{
+				FooterCancel(),
+				footerItem("A", "button_delete", "Delete"),
+			},
+			gaba.MessageOptions{},
+		)
+		return false, err == nil && result != nil && result.Confirmed
+	}
+
+	result, err := gaba.SelectionMessage(
+		message,
+		[]gaba.SelectionOption{
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "local_games_keep_saves", Other: "Keep Saves"}, nil), Value: false},
+			{
+				DisplayName: i18n.Localize(&goi18n.Message{ID: "local_games_delete_saves", Other: "Delete Saves"}, nil),
+				Description: i18n.Localize(&goi18n.Message{ID: "local_games_delete_saves_description", Other: "Delete {{.Name}} and its saves?\nA backup of each save is kept."}, map[string]interface{}{"Name": game.Name}),
+				Value:       true,
+			},
+		},
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			{ButtonName: icons.LeftRight, HelpText: i18n.Localize(&goi18n.Message{ID: "button_select", Other: "Select"}, nil)},
+			footerItem("A", "button_delete", "Delete"),
+		},
+		gaba.SelectionMessageSettings{},
+	)
+	if err != nil || result == nil {
+		return false, false
+	}
+
+	deleteSaves, _ := result.SelectedValue.(bool)
+	return deleteSaves, true
+}
@@ -20,18 +20,30 @@ import (
 
 type loginInput struct {
 	ExistingHost romm.Host
+	CanPair      bool // The existing host advertises device pairing
 }
 
+type loginAction int
+
+const (
+	loginActionLogin loginAction = iota
+	loginActionDiscover
+	loginActionPair
+)
+
 type loginOutput struct {
 	Host      romm.Host
 	Config    *internal.Config
 	Cancelled bool
+	Action    loginAction
 }
 
 type loginAttemptResult struct {
 	ErrorType string
 	ErrorMsg  *goi18n.Message
 	Success   bool
+	Token     romm.Token
+	Username  string // Set when pairing, where no username was entered
 }
 
 type LoginScreen struct{}
@@ -166,15 +178,27 @@ func (s *LoginScreen) draw(input loginInput) (loginOutput, error) {
 		},
 	}
 
+	footerItems := []gabagool.FooterHelpItem{
+		{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_quit", Other: "Quit"}, nil)},
+		{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_find_servers", Other: "Find Servers"}, nil)},
+	}
+	pairButton := icons.VirtualButtonUnassigned
+	if input.CanPair {
+		pairButton = icons.VirtualButtonX
+		footerItems = append(footerItems, gabagool.FooterHelpItem{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_pair", Other: "Pair"}, nil)})
+	}
+	footerItems = append(footerItems,
+		gabagool.FooterHelpItem{ButtonName: icons.LeftRight, HelpText: i18n.Localize(&goi18n.Message{ID: "button_cycle", Other: "Cycle"}, nil)},
+		gabagool.FooterHelpItem{ButtonName: icons.Start, HelpText: i18n.Localize(&goi18n.Message{ID: "button_login", Other: "Login"}, nil)},
+	)
+
 	res, err := gabagool.OptionsList(
 		i18n.Localize(&goi18n.Message{ID: "login_title", Other: "Login to RomM"}, nil),
 		gabagool.OptionListSettings{
-			DisableBackButton: false,
-			FooterHelpItems: []gabagool.FooterHelpItem{
-				{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_quit", Other: "Quit"}, nil)},
-				{ButtonName: icons.LeftRight, HelpText: i18n.Localize(&goi18n.Message{ID: "button_cycle", Other: "Cycle"}, nil)},
-				{ButtonName: icons.Start, HelpText: i18n.Localize(&goi18n.Message{ID: "button_login", Other: "Login"}, nil)},
-			},
+			DisableBackButton:     false,
+			ActionButton:          icons.VirtualButtonY,
+			SecondaryActionButton: pairButton,
+			FooterHelpItems:       footerItems,
 		},
 		items,
 	)
@@ -198,14 +222,37 @@ func (s *LoginScreen) draw(input loginInput) (loginOutput, error) {
 		InsecureSkipVerify: loginSettings[5].Options[loginSettings[5].SelectedOption].Value.(bool),
 	}
 
-	return loginOutput{Host: newHost}, nil
+	// Y looks for servers on the network and X pairs with the server entered, keeping
+	// whatever was already entered
+	output := loginOutput{Host: newHost}
+	switch res.Action {
+	case gabagool.ListActionTriggered:
+		output.Action = loginActionDiscover
+	case gabagool.ListActionSecondaryTriggered:
+		output.Action = loginActionPair
+	}
+	return output, nil
 }
 
 func LoginFlow(existingHost romm.Host) (*internal.Config, error) {
+	return loginFlow(existingHost, false)
+}
+
+// ErrLoginCancelled is returned by CancellableLoginFlow when the user backs out.
+var ErrLoginCancelled = errors.New("login cancelled")
+
+// CancellableLoginFlow is LoginFlow for adding or switching to another RomM server.
+// Backing out returns ErrLoginCancelled rather than quitting, since there is still the
+// current server to go back to.
+func CancellableLoginFlow(existingHost romm.Host) (*internal.Config, error) {
+	return loginFlow(existingHost, true)
+}
+
+func loginFlow(existingHost romm.Host, cancellable bool) (*internal.Config, error) {
 	screen := newLoginScreen()
 
 	for {
-		result, err := screen.draw(loginInput{ExistingHost: existingHost})
+		result, err := screen.draw(loginInput{ExistingHost: existingHost, CanPair: pairingAvailable(existingHost)})
 		if err != nil {
 			gabagool.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "login_error_unexpected", Other: "Something unexpected happened!\nCheck the logs for more info."}, nil), gabagool.ProcessMessageOptions{}, func() (interface{}, error) {
 				time.Sleep(3 * time.Second)
@@ -215,14 +262,41 @@ func LoginFlow(existingHost romm.Host) (*internal.Config, error) {
 		}
 
 		if result.Cancelled {
+			if cancellable {
+				return nil, ErrLoginCancelled
+			}
 			os.Exit(1)
 		}
 
 		host := result.Host
 
-		loginResult := attemptLogin(host)
+		var loginResult loginAttemptResult
+		switch result.Action {
+		case loginActionDiscover:
+			if found, ok := discoverServerUI(); ok {
+				found.Username = host.Username
+				found.Password = host.Password
+				host = found
+			}
+			existingHost = host
+			continue
+		case loginActionPair:
+			var cancelled bool
+			loginResult, cancelled = pairUI(host)
+			if cancelled {
+				existingHost = host
+				continue
+			}
+		default:
+			loginResult = attemptLogin(host)
+		}
 
 		if loginResult.Success {
+			// Only the token pair is persisted; the password is discarded here
+			host = host.WithToken(loginResult.Token)
+			if loginResult.Username != "" {
+				host.Username = loginResult.Username
+			}
 			config := &internal.Config{
 				Hosts: []romm.Host{host},
 			}
@@ -255,12 +329,12 @@ func attemptLogin(host romm.Host) loginAttemptResult {
 			}
 
 			loginClient := romm.NewClientFromHost(host, internal.LoginTimeout)
-			err = loginClient.Login(host.Username, host.Password)
+			token, err := loginClient.Login(host.Username, host.Password)
 			if err != nil {
 				return classifyLoginError(err), nil
 			}
 
-			return loginAttemptResult{Success: true}, nil
+			return loginAttemptResult{Success: true, Token: token}, nil
 		},
 	)
 
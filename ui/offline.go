@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"grout/internal"
+	"grout/romm"
+	"sync"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const (
+	offlineIcon          = "\U000F0164" // Cloud with a line through it
+	offlineWatchInterval = 5 * time.Second
+	offlineRetryInterval = 30 * time.Second
+)
+
+// OfflineMonitor shows an icon in the status bar while RomM can't be reached and checks
+// its heartbeat until it can again. The icon is empty while online.
+type OfflineMonitor struct {
+	mu       sync.Mutex
+	host     romm.Host
+	icon     *gaba.DynamicStatusBarIcon
+	onOnline func()
+}
+
+// NewOfflineMonitor creates a monitor for host. onOnline is called on the monitor's own
+// goroutine each time connectivity returns.
+func NewOfflineMonitor(host romm.Host, onOnline func()) *OfflineMonitor {
+	return &OfflineMonitor{
+		host:     host,
+		icon:     gaba.NewDynamicStatusBarIcon(""),
+		onOnline: onOnline,
+	}
+}
+
+// SetHost makes the monitor check host, once servers have been switched.
+func (m *OfflineMonitor) SetHost(host romm.Host) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.host = host
+}
+
+func (m *OfflineMonitor) currentHost() romm.Host {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.host
+}
+
+func (m *OfflineMonitor) Icon() gaba.StatusBarIcon {
+	return gaba.StatusBarIcon{
+		Dynamic: m.icon,
+	}
+}
+
+func (m *OfflineMonitor) Start() {
+	offline := internal.IsOffline()
+	if offline {
+		m.icon.SetText(offlineIcon)
+	}
+	go m.run(offline)
+}
+
+func (m *OfflineMonitor) run(wasOffline bool) {
+	ticker := time.NewTicker(offlineWatchInterval)
+	defer ticker.Stop()
+
+	// Whatever noticed RomM was gone has just tried it, so wait a full interval
+	lastCheck := time.Now()
+	for range ticker.C {
+		if !internal.IsOffline() {
+			if wasOffline {
+				m.backOnline()
+				wasOffline = false
+			}
+			continue
+		}
+
+		if !wasOffline {
+			m.icon.SetText(offlineIcon)
+			wasOffline = true
+			lastCheck = time.Now()
+		}
+		if time.Since(lastCheck) < offlineRetryInterval {
+			continue
+		}
+
+		lastCheck = time.Now()
+		if internal.CheckConnectivity(m.currentHost()) {
+			m.backOnline()
+			wasOffline = false
+		}
+	}
+}
+
+// backOnline clears the icon and calls onOnline. The heartbeat checked here isn't the only
+// one, switching servers checks too, so this runs whichever of them found RomM again.
+func (m *OfflineMonitor) backOnline() {
+	gaba.GetLogger().Info("RomM is reachable again")
+	m.icon.SetText("")
+	if m.onOnline != nil {
+		m.onOnline()
+	}
+}
+
+// ShowOfflineMessage tells the user something has to wait until RomM is back.
+func ShowOfflineMessage(message *goi18n.Message) {
+	gaba.ConfirmationMessage(i18n.Localize(message, nil), ContinueFooter(), gaba.MessageOptions{})
+}
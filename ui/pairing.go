@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"grout/internal"
+	"grout/internal/imageutil"
+	"grout/romm"
+	"os"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const pairingQRSize = 256
+
+// pairingAvailable checks whether host advertises device pairing, so the login screen only
+// offers it for servers that do.
+func pairingAvailable(host romm.Host) bool {
+	if strings.TrimPrefix(strings.TrimPrefix(host.RootURI, "https://"), "http://") == "" {
+		return false
+	}
+
+	result, _ := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "login_checking_server", Other: "Checking server..."}, nil),
+		gaba.ProcessMessageOptions{},
+		func() (interface{}, error) {
+			return romm.NewClientFromHost(host, internal.ValidationTimeout).SupportsPairing(), nil
+		},
+	)
+	supported, _ := result.(bool)
+	return supported
+}
+
+// pairUI logs in to host without a password. A QR code and short code are shown for the
+// user to confirm in RomM on their phone, while Grout waits for the token. Returns true
+// if the user backed out.
+func pairUI(host romm.Host) (loginAttemptResult, bool) {
+	logger := gaba.GetLogger()
+	client := romm.NewClientFromHost(host, internal.LoginTimeout)
+
+	result, _ := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "login_validating", Other: "Validating connection..."}, nil),
+		gaba.ProcessMessageOptions{},
+		func() (interface{}, error) {
+			if err := romm.NewClientFromHost(host, internal.ValidationTimeout).ValidateConnection(); err != nil {
+				return classifyLoginError(err), nil
+			}
+
+			pairing, err := client.StartPairing()
+			if err != nil {
+				return classifyPairingError(err), nil
+			}
+			return pairing, nil
+		},
+	)
+
+	pairing, ok := result.(romm.Pairing)
+	if !ok {
+		return result.(loginAttemptResult), false
+	}
+
+	verificationURI := pairing.VerificationURI
+	if strings.HasPrefix(verificationURI, "/") {
+		verificationURI = host.URL() + verificationURI
+	}
+
+	var qrBytes []byte
+	if qrPath, err := imageutil.CreateTempQRCode(verificationURI, pairingQRSize); err == nil {
+		qrBytes, _ = os.ReadFile(qrPath)
+		os.Remove(qrPath)
+	} else {
+		logger.Error("Unable to generate pairing QR code", "error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	token, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "login_pairing_instructions", Other: "Scan the QR code or open {{.URL}}\nand confirm the code {{.Code}}"}, map[string]interface{}{
+			"URL":  verificationURI,
+			"Code": pairing.UserCode,
+		}),
+		gaba.ProcessMessageOptions{
+			ImageBytes:      qrBytes,
+			ImageWidth:      pairingQRSize,
+			ImageHeight:     pairingQRSize,
+			CancelButton:    buttons.VirtualButtonB,
+			FooterHelpItems: []gaba.FooterHelpItem{FooterCancel()},
+		},
+		func() (romm.Token, error) {
+			return client.PollPairing(ctx, pairing)
+		},
+	)
+	if errors.Is(err, gaba.ErrCancelled) {
+		return loginAttemptResult{}, true
+	}
+	if err != nil {
+		logger.Error("Pairing failed", "error", err)
+		return classifyPairingError(err), false
+	}
+
+	loginResult := loginAttemptResult{Success: true, Token: token}
+	if user, err := romm.NewClientFromHost(host.WithToken(token), internal.LoginTimeout).GetCurrentUser(); err == nil {
+		loginResult.Username = user.Username
+	} else {
+		logger.Warn("Unable to get the paired user", "error", err)
+	}
+	return loginResult, false
+}
+
+func classifyPairingError(err error) loginAttemptResult {
+	switch {
+	case errors.Is(err, romm.ErrPairingUnsupported):
+		return loginAttemptResult{
+			ErrorType: "pairing",
+			ErrorMsg:  &goi18n.Message{ID: "login_error_pairing_unsupported", Other: "This RomM server doesn't support pairing!\nPlease log in with your username and password."},
+		}
+	case errors.Is(err, romm.ErrPairingExpired):
+		return loginAttemptResult{
+			ErrorType: "pairing",
+			ErrorMsg:  &goi18n.Message{ID: "login_error_pairing_expired", Other: "The pairing code expired!\nPlease try again."},
+		}
+	case errors.Is(err, romm.ErrPairingDenied):
+		return loginAttemptResult{
+			ErrorType: "pairing",
+			ErrorMsg:  &goi18n.Message{ID: "login_error_pairing_denied", Other: "Pairing was denied in RomM!"},
+		}
+	default:
+		return classifyLoginError(err)
+	}
+}
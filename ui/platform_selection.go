@@ -16,7 +16,9 @@ type PlatformSelectionInput struct {
 	Platforms            *[]romm.Platform // Pointer to allow dynamic updates from state
 	QuitOnBack           bool
 	ShowCollections      bool
+	ShowRecentlyPlayed   bool
 	ShowSaveSync         *atomic.Bool // nil = hidden, otherwise controls visibility dynamically
+	ShowDownloadQueue    bool
 	LastSelectedIndex    int
 	LastSelectedPosition int
 }
@@ -59,6 +61,26 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 		})
 	}
 
+	if input.ShowRecentlyPlayed {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:           i18n.Localize(&goi18n.Message{ID: "platform_selection_recently_played", Other: "Recently Played"}, nil),
+			Selected:       false,
+			Focused:        false,
+			Metadata:       romm.Platform{FSSlug: "recently_played"},
+			NotReorderable: true,
+		})
+	}
+
+	if input.ShowDownloadQueue {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:           i18n.Localize(&goi18n.Message{ID: "platform_selection_download_queue", Other: "Download Queue"}, nil),
+			Selected:       false,
+			Focused:        false,
+			Metadata:       romm.Platform{FSSlug: "download_queue"},
+			NotReorderable: true,
+		})
+	}
+
 	for _, platform := range platforms {
 		menuItems = append(menuItems, gaba.MenuItem{
 			Text:     platform.Name,
@@ -82,6 +104,12 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 				HelpText:   i18n.Localize(&goi18n.Message{ID: "button_quit", Other: "Quit"}, nil),
 			})
 		}
+		if !internal.IsKidModeEnabled() {
+			footerItems = append(footerItems, gaba.FooterHelpItem{
+				ButtonName: i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil),
+				HelpText:   i18n.Localize(&goi18n.Message{ID: "button_more", Other: "More"}, nil),
+			})
+		}
 		if input.ShowSaveSync != nil && !internal.IsKidModeEnabled() {
 			footerItems = append(footerItems, gaba.FooterHelpItem{
 				ButtonName: "Y",
@@ -93,13 +121,20 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 	} else {
 		footerItems = []gaba.FooterHelpItem{
 			{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_back", Other: "Back"}, nil)},
-			{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_select", Other: "Select"}, nil)},
 		}
+		if !internal.IsKidModeEnabled() {
+			footerItems = append(footerItems, gaba.FooterHelpItem{
+				ButtonName: i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil),
+				HelpText:   i18n.Localize(&goi18n.Message{ID: "button_more", Other: "More"}, nil),
+			})
+		}
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_select", Other: "Select"}, nil)})
 	}
 
 	options := gaba.DefaultListOptions("Grout", menuItems)
 	if !internal.IsKidModeEnabled() {
 		options.ActionButton = buttons.VirtualButtonX
+		options.TertiaryActionButton = buttons.VirtualButtonMenu
 	}
 	if input.ShowSaveSync != nil {
 		options.SecondaryActionButton = buttons.VirtualButtonY
@@ -111,75 +146,132 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 
 	options.StatusBar = StatusBar()
 
-	sel, err := gaba.List(options)
+	for {
+		sel, err := gaba.List(options)
 
-	// Check for reordering before handling errors
-	// This ensures we save the order even when user presses B (cancel)
-	platformsReordered := false
-	startIndex := 0
-	if input.ShowCollections {
-		startIndex = 1
-	}
+		// Check for reordering before handling errors
+		// This ensures we save the order even when user presses B (cancel)
+		platformsReordered := false
+		startIndex := 0
+		if input.ShowCollections {
+			startIndex++
+		}
+		if input.ShowRecentlyPlayed {
+			startIndex++
+		}
+		if input.ShowDownloadQueue {
+			startIndex++
+		}
 
-	if sel != nil && len(sel.Items) > 0 {
-		if len(sel.Items)-startIndex == len(platforms) {
-			for i := 0; i < len(platforms); i++ {
-				originalPlatform := platforms[i]
-				returnedPlatform := sel.Items[i+startIndex].Metadata.(romm.Platform)
-				if originalPlatform.FSSlug != returnedPlatform.FSSlug {
-					platformsReordered = true
-					break
+		if sel != nil && len(sel.Items) > 0 {
+			if len(sel.Items)-startIndex == len(platforms) {
+				for i := 0; i < len(platforms); i++ {
+					originalPlatform := platforms[i]
+					returnedPlatform := sel.Items[i+startIndex].Metadata.(romm.Platform)
+					if originalPlatform.FSSlug != returnedPlatform.FSSlug {
+						platformsReordered = true
+						break
+					}
 				}
 			}
-		}
 
-		if platformsReordered {
-			var reorderedPlatforms []romm.Platform
-			for i := startIndex; i < len(sel.Items); i++ {
-				platform := sel.Items[i].Metadata.(romm.Platform)
-				reorderedPlatforms = append(reorderedPlatforms, platform)
+			if platformsReordered {
+				var reorderedPlatforms []romm.Platform
+				for i := startIndex; i < len(sel.Items); i++ {
+					platform := sel.Items[i].Metadata.(romm.Platform)
+					reorderedPlatforms = append(reorderedPlatforms, platform)
+				}
+				output.ReorderedPlatforms = reorderedPlatforms
 			}
-			output.ReorderedPlatforms = reorderedPlatforms
 		}
-	}
 
-	if err != nil {
-		if errors.Is(err, gaba.ErrCancelled) {
-			output.Action = PlatformSelectionActionQuit
-			return output, nil
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				output.Action = PlatformSelectionActionQuit
+				return output, nil
+			}
+			return output, err
 		}
-		return output, err
-	}
 
-	switch sel.Action {
-	case gaba.ListActionSelected:
-		platform := sel.Items[sel.Selected[0]].Metadata.(romm.Platform)
+		switch sel.Action {
+		case gaba.ListActionSelected:
+			platform := sel.Items[sel.Selected[0]].Metadata.(romm.Platform)
 
-		output.SelectedPlatform = platform
-		output.LastSelectedIndex = sel.Selected[0]
-		output.LastSelectedPosition = sel.VisiblePosition
+			output.SelectedPlatform = platform
+			output.LastSelectedIndex = sel.Selected[0]
+			output.LastSelectedPosition = sel.VisiblePosition
 
-		if platform.FSSlug == "collections" {
-			output.Action = PlatformSelectionActionCollections
-			return output, nil
-		}
+			if platform.FSSlug == "collections" {
+				output.Action = PlatformSelectionActionCollections
+				return output, nil
+			}
 
-		output.Action = PlatformSelectionActionSelected
-		return output, nil
+			if platform.FSSlug == "recently_played" {
+				output.Action = PlatformSelectionActionRecentlyPlayed
+				return output, nil
+			}
 
-	case gaba.ListActionTriggered:
-		if input.QuitOnBack {
-			output.Action = PlatformSelectionActionSettings
+			if platform.FSSlug == "download_queue" {
+				output.Action = PlatformSelectionActionDownloadQueue
+				return output, nil
+			}
+
+			output.Action = PlatformSelectionActionSelected
 			return output, nil
-		}
 
-	case gaba.ListActionSecondaryTriggered:
-		if input.QuitOnBack && input.ShowSaveSync != nil {
-			output.Action = PlatformSelectionActionSaveSync
+		case gaba.ListActionTriggered:
+			if input.QuitOnBack {
+				output.Action = PlatformSelectionActionSettings
+				return output, nil
+			}
+
+		case gaba.ListActionSecondaryTriggered:
+			if input.QuitOnBack && input.ShowSaveSync != nil {
+				output.Action = PlatformSelectionActionSaveSync
+				return output, nil
+			}
+
+		case gaba.ListActionTertiaryTriggered:
+			action, ok := s.chooseMenuAction(input.QuitOnBack, sel.Items[sel.Selected[0]].Metadata.(romm.Platform))
+			if !ok {
+				// Back to the list where it was left, keeping any reordering
+				options.Items = sel.Items
+				options.SelectedIndex = sel.Selected[0]
+				options.VisibleStartIndex = max(0, sel.Selected[0]-sel.VisiblePosition)
+				continue
+			}
+			output.SelectedPlatform = sel.Items[sel.Selected[0]].Metadata.(romm.Platform)
+			output.LastSelectedIndex = sel.Selected[0]
+			output.LastSelectedPosition = sel.VisiblePosition
+			output.Action = action
 			return output, nil
 		}
+
+		output.Action = PlatformSelectionActionQuit
+		return output, nil
 	}
+}
 
-	output.Action = PlatformSelectionActionQuit
-	return output, nil
+// chooseMenuAction asks what the menu button should open. Missing games can only be
+// downloaded for a platform, not the entries above the platforms.
+func (s *PlatformSelectionScreen) chooseMenuAction(showHosts bool, focused romm.Platform) (PlatformSelectionAction, bool) {
+	var items []gaba.MenuItem
+	if focused.ID != 0 {
+		items = append(items, gaba.MenuItem{
+			Text:     i18n.Localize(&goi18n.Message{ID: "bulk_download_missing", Other: "Download Missing Games"}, nil),
+			Metadata: PlatformSelectionActionDownloadMissing,
+		})
+	}
+	if showHosts {
+		items = append(items, gaba.MenuItem{
+			Text:     i18n.Localize(&goi18n.Message{ID: "button_servers", Other: "Servers"}, nil),
+			Metadata: PlatformSelectionActionHosts,
+		})
+	}
+
+	action, ok := chooseAction(i18n.Localize(&goi18n.Message{ID: "button_menu", Other: "Menu"}, nil), items)
+	if !ok {
+		return PlatformSelectionActionQuit, false
+	}
+	return action.(PlatformSelectionAction), true
 }
@@ -1,11 +1,14 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"grout/cache"
 	"grout/internal"
 	"grout/romm"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	uatomic "go.uber.org/atomic"
@@ -15,6 +18,7 @@ type RebuildCacheInput struct {
 	Host      romm.Host
 	Config    *internal.Config
 	CacheSync *cache.BackgroundSync
+	Downloads *BackgroundDownload
 }
 
 type RebuildCacheOutput struct {
@@ -38,43 +42,75 @@ func NewRebuildCacheScreen() *RebuildCacheScreen {
 func (s *RebuildCacheScreen) Draw(input RebuildCacheInput) (RebuildCacheOutput, error) {
 	logger := gaba.GetLogger()
 
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return RebuildCacheOutput{Action: RebuildCacheActionError}, cache.ErrNotInitialized
+	}
+
+	// Nothing may write to the library while it is cleared. Queued downloads carry on
+	// once it has been rebuilt.
 	if input.CacheSync != nil {
 		input.CacheSync.Stop()
 	}
-
-	if err := cache.DeleteCacheFolder(); err != nil {
-		logger.Error("Failed to delete cache folder", "error", err)
+	if input.Downloads != nil {
+		input.Downloads.Stop()
+		defer func() {
+			if cm.CountPendingDownloads() > 0 {
+				input.Downloads.Trigger()
+			}
+		}()
 	}
 
-	if err := cache.InitCacheManager(input.Host, input.Config); err != nil {
-		logger.Error("Failed to reinitialize cache manager", "error", err)
+	if err := cm.Clear(); err != nil {
+		logger.Error("Failed to clear cache", "error", err)
+		if input.CacheSync != nil {
+			input.CacheSync.Restart()
+		}
 		return RebuildCacheOutput{Action: RebuildCacheActionError}, err
 	}
 
 	platforms, err := internal.GetMappedPlatforms(input.Host, input.Config.DirectoryMappings, input.Config.ApiTimeout)
 	if err != nil {
 		logger.Error("Failed to fetch platforms", "error", err)
+		// The background sync fills the cleared cache once RomM answers again
+		if input.CacheSync != nil {
+			input.CacheSync.Restart()
+		}
 		return RebuildCacheOutput{Action: RebuildCacheActionError}, err
 	}
 
 	platforms = internal.SortPlatformsByOrder(platforms, input.Config.PlatformOrder)
 
-	cm := cache.GetCacheManager()
 	progress := uatomic.NewFloat64(0)
-	gaba.ProcessMessage(
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+
+	_, err = gaba.ProcessMessage(
 		i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
 		gaba.ProcessMessageOptions{
 			ShowThemeBackground: true,
 			ShowProgressBar:     true,
 			Progress:            progress,
+			CancelButton:        buttons.VirtualButtonB,
+			FooterHelpItems:     []gaba.FooterHelpItem{FooterCancel()},
 		},
 		func() (any, error) {
-			_, err := cm.PopulateFullCacheWithProgress(platforms, progress)
+			defer close(done)
+			_, err := cm.PopulateFullCacheWithProgress(ctx, platforms, progress)
 			return nil, err
 		},
 	)
 
-	if input.CacheSync != nil {
+	if errors.Is(err, gaba.ErrCancelled) {
+		// Wait for the partial platform to roll back, then finish the rebuild in the background
+		cancel()
+		<-done
+		logger.Info("Cache rebuild cancelled, continuing in the background")
+		if input.CacheSync != nil {
+			input.CacheSync.Restart()
+		}
+	} else if input.CacheSync != nil {
 		input.CacheSync.SetSynced()
 	}
 
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type RecentlyPlayedInput struct {
+	Config               *internal.Config
+	Host                 romm.Host
+	LastSelectedIndex    int
+	LastSelectedPosition int
+}
+
+type RecentlyPlayedOutput struct {
+	Action               RecentlyPlayedAction
+	SelectedGame         romm.Rom
+	Platform             romm.Platform
+	LastSelectedIndex    int
+	LastSelectedPosition int
+}
+
+// RecentlyPlayedScreen lists the games the CFW recorded being played, most recent first.
+type RecentlyPlayedScreen struct{}
+
+func NewRecentlyPlayedScreen() *RecentlyPlayedScreen {
+	return &RecentlyPlayedScreen{}
+}
+
+func (s *RecentlyPlayedScreen) Draw(input RecentlyPlayedInput) (RecentlyPlayedOutput, error) {
+	output := RecentlyPlayedOutput{
+		Action:               RecentlyPlayedActionBack,
+		LastSelectedIndex:    input.LastSelectedIndex,
+		LastSelectedPosition: input.LastSelectedPosition,
+	}
+	logger := gaba.GetLogger()
+
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return output, nil
+	}
+
+	activity, err := cm.GetPlayActivity()
+	if err != nil {
+		logger.Error("Failed to load play activity", "error", err)
+		return output, err
+	}
+
+	ids := make([]int, 0, len(activity))
+	for _, a := range activity {
+		ids = append(ids, a.RomID)
+	}
+	games, err := cm.GetGamesByIDs(ids)
+	if err != nil {
+		logger.Error("Failed to load recently played games", "error", err)
+		return output, err
+	}
+	gamesByID := make(map[int]romm.Rom, len(games))
+	for _, game := range games {
+		gamesByID[game.ID] = game
+	}
+
+	// Games no longer in RomM are left out
+	menuItems := make([]gaba.MenuItem, 0, len(activity))
+	for _, a := range activity {
+		game, ok := gamesByID[a.RomID]
+		if !ok {
+			continue
+		}
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     recentlyPlayedLabel(game, a),
+			Metadata: game,
+		})
+	}
+
+	options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "recently_played_title", Other: "Recently Played"}, nil), menuItems)
+	options.UseSmallTitle = true
+	options.EmptyMessage = i18n.Localize(&goi18n.Message{ID: "recently_played_empty", Other: "No games played yet"}, nil)
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		FooterSelect(),
+	}
+	options.SelectedIndex = input.LastSelectedIndex
+	options.VisibleStartIndex = max(0, input.LastSelectedIndex-input.LastSelectedPosition)
+	options.StatusBar = StatusBar()
+
+	res, err := gaba.List(options)
+	if err != nil {
+		if errors.Is(err, gaba.ErrCancelled) {
+			return output, nil
+		}
+		return output, err
+	}
+
+	if res.Action != gaba.ListActionSelected || len(res.Selected) == 0 {
+		return output, nil
+	}
+
+	game := res.Items[res.Selected[0]].Metadata.(romm.Rom)
+	output.Action = RecentlyPlayedActionSelected
+	output.SelectedGame = game
+	output.Platform = resolveGamePlatform(romm.Platform{}, game)
+	output.LastSelectedIndex = res.Selected[0]
+	output.LastSelectedPosition = res.VisiblePosition
+	return output, nil
+}
+
+func recentlyPlayedLabel(game romm.Rom, activity cache.PlayActivity) string {
+	label := fmt.Sprintf("%s [%s] - %s", game.Name, game.PlatformDisplayName, activity.LastPlayed.Local().Format(saveHistoryTimeFormat))
+	if activity.PlayTime >= time.Minute {
+		label = fmt.Sprintf("%s (%s)", label, formatPlayTime(activity.PlayTime))
+	}
+	return label
+}
+
+// formatPlayTime shows a play time in hours and minutes, e.g. "2h 05m" or "45m".
+func formatPlayTime(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", hours, minutes)
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/internal"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"grout/sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const saveHistoryTimeFormat = "2006-01-02 15:04"
+
+type SaveHistoryInput struct {
+	Config *internal.Config
+	Host   romm.Host
+	Game   romm.Rom
+}
+
+type SaveHistoryOutput struct {
+	Action SaveHistoryAction
+}
+
+type SaveHistoryScreen struct{}
+
+func NewSaveHistoryScreen() *SaveHistoryScreen {
+	return &SaveHistoryScreen{}
+}
+
+func (s *SaveHistoryScreen) Draw(input SaveHistoryInput) (SaveHistoryOutput, error) {
+	output := SaveHistoryOutput{Action: SaveHistoryActionBack}
+	logger := gaba.GetLogger()
+
+	selectedIndex := 0
+	for {
+		history, err := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "save_history_loading", Other: "Loading save history..."}, nil),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (sync.SaveHistory, error) {
+				history, err := sync.GetSaveHistory(input.Host, input.Config, input.Game)
+				if err != nil {
+					// Local backups are still worth showing when RomM is unreachable
+					logger.Warn("Save history is missing remote saves", "error", err)
+				}
+				return history, nil
+			},
+		)
+		if err != nil {
+			return output, err
+		}
+
+		menuItems := make([]gaba.MenuItem, 0, len(history.Versions))
+		for _, version := range history.Versions {
+			menuItems = append(menuItems, gaba.MenuItem{
+				Text:     saveVersionLabel(version),
+				Metadata: version,
+			})
+		}
+
+		options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "save_history_title", Other: "Save History"}, nil), menuItems)
+		options.UseSmallTitle = true
+		options.EmptyMessage = i18n.Localize(&goi18n.Message{ID: "save_history_empty", Other: "No earlier saves found"}, nil)
+		options.FooterHelpItems = []gaba.FooterHelpItem{
+			FooterBack(),
+			footerItem("A", "button_restore", "Restore"),
+		}
+		options.SelectedIndex = min(selectedIndex, max(0, len(menuItems)-1))
+		options.StatusBar = StatusBar()
+
+		res, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return output, nil
+			}
+			return output, err
+		}
+
+		if res.Action != gaba.ListActionSelected || len(res.Selected) == 0 {
+			continue
+		}
+
+		selectedIndex = res.Selected[0]
+		version := history.Versions[selectedIndex]
+		if !s.confirmRestore(version, history.Current != nil) {
+			continue
+		}
+
+		_, err = gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "save_history_restoring", Other: "Restoring save..."}, nil),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (string, error) {
+				return sync.RestoreSaveVersion(input.Host, input.Config, input.Game, history.Current, version)
+			},
+		)
+
+		message := i18n.Localize(&goi18n.Message{ID: "save_history_restored", Other: "Save restored.\nIt will be uploaded on the next sync."}, nil)
+		if err != nil {
+			logger.Error("Failed to restore save", "game", input.Game.Name, "error", err)
+			message = i18n.Localize(&goi18n.Message{ID: "save_history_restore_failed", Other: "Unable to restore the save.\nCheck the logs for details."}, nil)
+		}
+		gaba.ConfirmationMessage(message, ContinueFooter(), gaba.MessageOptions{})
+	}
+}
+
+func (s *SaveHistoryScreen) confirmRestore(version sync.SaveVersion, hasCurrent bool) bool {
+	message := i18n.Localize(&goi18n.Message{ID: "save_history_restore_confirm", Other: "Restore the save from {{.Time}}?"}, map[string]interface{}{
+		"Time": version.Time.Local().Format(saveHistoryTimeFormat),
+	})
+	if hasCurrent {
+		message = i18n.Localize(&goi18n.Message{ID: "save_history_restore_confirm_backup", Other: "Restore the save from {{.Time}}?\nYour current save will be backed up first."}, map[string]interface{}{
+			"Time": version.Time.Local().Format(saveHistoryTimeFormat),
+		})
+	}
+
+	result, err := gaba.ConfirmationMessage(
+		message,
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			footerItem("A", "button_restore", "Restore"),
+		},
+		gaba.MessageOptions{},
+	)
+	return err == nil && result != nil && result.Confirmed
+}
+
+func saveVersionLabel(version sync.SaveVersion) string {
+	source := i18n.Localize(&goi18n.Message{ID: "save_history_source_local", Other: "[Backup]"}, nil)
+	if version.Source == sync.SaveVersionRemote {
+		source = i18n.Localize(&goi18n.Message{ID: "save_history_source_remote", Other: "[RomM]"}, nil)
+	}
+
+	label := fmt.Sprintf("%s %s - %s", source, version.Time.Local().Format(saveHistoryTimeFormat), stringutil.FormatBytes(version.Size))
+	if version.Emulator != "" {
+		label = fmt.Sprintf("%s (%s)", label, version.Emulator)
+	}
+	return label
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"grout/cache"
 	"grout/internal"
@@ -35,6 +37,11 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (SaveSyncOutput, error) {
 	output := SaveSyncOutput{}
 	config := input.Config
 
+	if internal.IsOffline() {
+		ShowOfflineMessage(&goi18n.Message{ID: "save_sync_offline", Other: "RomM can't be reached right now.\nSaves will sync once it is back online."})
+		return output, nil
+	}
+
 	romScan, _ := gaba.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "save_sync_scanning_roms", Other: "Scanning ROMs..."}, nil), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
 		return sync.ScanRoms(config), nil
 	})
@@ -45,14 +52,21 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (SaveSyncOutput, error) {
 		FuzzyMatches []sync.PendingFuzzyMatch
 	}
 
-	scanData, _ := gaba.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "save_sync_scanning", Other: "Scanning save files..."}, nil), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+	// Backing out of the scan aborts its requests to RomM
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scanData, err := gaba.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "save_sync_scanning", Other: "Scanning save files..."}, nil), gaba.ProcessMessageOptions{
+		CancelButton:    buttons.VirtualButtonB,
+		FooterHelpItems: []gaba.FooterHelpItem{FooterCancel()},
+	}, func() (interface{}, error) {
 		localRoms, ok := romScan.(sync.LocalRomScan)
 		if !ok {
 			gaba.GetLogger().Error("Unable to scan ROMs!")
 			return nil, nil
 		}
 
-		syncs, unmatched, fuzzyMatches, err := sync.FindSaveSyncsFromScan(input.Host, input.Config, localRoms)
+		syncs, unmatched, fuzzyMatches, err := sync.FindSaveSyncsFromScan(ctx, input.Host, input.Config, localRoms)
 		if err != nil {
 			gaba.GetLogger().Error("Unable to scan save files!", "error", err)
 			return nil, nil
@@ -60,6 +74,9 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (SaveSyncOutput, error) {
 
 		return scanResult{Syncs: syncs, Unmatched: unmatched, FuzzyMatches: fuzzyMatches}, nil
 	})
+	if errors.Is(err, gaba.ErrCancelled) {
+		return output, nil
+	}
 
 	var results []sync.Result
 	var unmatched []sync.UnmatchedSave
@@ -69,36 +86,58 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (SaveSyncOutput, error) {
 		syncs := scan.Syncs
 
 		for _, fm := range scan.FuzzyMatches {
+			// The match is remembered in the cache of the host the save syncs with
+			cm, release, err := cache.HostCache(fm.Host, input.Config)
+			if err != nil {
+				gaba.GetLogger().Error("Failed to open cache of host", "host", fm.Host.URL(), "error", err)
+			}
+
 			confirmed := showFuzzyMatchConfirmation(fm)
 			if confirmed {
-				err := cache.SaveFilenameMapping(fm.FSSlug, fm.LocalFilename, fm.MatchedRomID, fm.MatchedName)
+				err := cm.SaveFilenameMapping(fm.FSSlug, fm.LocalFilename, fm.MatchedRomID, fm.MatchedName)
 				if err != nil {
 					gaba.GetLogger().Error("Failed to save filename mapping", "error", err)
 				} else {
-					_ = cache.ClearFailedLookup(fm.FSSlug, fm.LocalFilename)
+					_ = cm.ClearFailedLookup(fm.FSSlug, fm.LocalFilename)
 					gaba.GetLogger().Info("Fuzzy match confirmed and saved",
 						"local", fm.LocalFilename,
 						"matched", fm.MatchedName)
 
 					if localSave := createLocalSaveFromPath(fm.SavePath, fm.FSSlug); localSave != nil {
 						gameBase := strings.TrimSuffix(filepath.Base(fm.SavePath), filepath.Ext(fm.SavePath))
+						action := sync.Upload
+						if input.Config.IsSyncExcluded(fm.FSSlug, fm.MatchedRomID) {
+							action = sync.Excluded
+						}
 						syncs = append(syncs, sync.SaveSync{
 							RomID:    fm.MatchedRomID,
 							RomName:  fm.MatchedName,
 							FSSlug:   fm.FSSlug,
 							GameBase: gameBase,
 							Local:    localSave,
-							Action:   sync.Upload,
+							Action:   action,
+							Host:     fm.Host,
 						})
 					}
 				}
 			} else {
-				_ = cache.RecordFailedLookup(fm.FSSlug, fm.LocalFilename)
+				_ = cm.RecordFailedLookup(fm.FSSlug, fm.LocalFilename)
 				unmatched = append(unmatched, sync.UnmatchedSave{
 					SavePath: fm.SavePath,
 					FSSlug:   fm.FSSlug,
 				})
 			}
+			release()
+		}
+
+		for i := range syncs {
+			if s := syncs[i]; s.Action == sync.Conflict {
+				name := s.RomName
+				if name == "" {
+					name = s.GameBase
+				}
+				syncs[i].Action = showConflictResolution(name, s.Local.LastModified, s.RemoteTime(), true)
+			}
 		}
 
 		results = make([]sync.Result, 0, len(syncs))
@@ -116,7 +155,7 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (SaveSyncOutput, error) {
 					total := len(syncs)
 					for i := range syncs {
 						s := &syncs[i]
-						result := s.Execute(input.Host, input.Config)
+						result := s.Execute(input.Config)
 						results = append(results, result)
 						if !result.Success {
 							gaba.GetLogger().Error("Unable to sync save!", "game", s.GameBase, "error", result.Error)
@@ -129,6 +168,41 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (SaveSyncOutput, error) {
 		}
 	}
 
+	if localRoms, ok := romScan.(sync.LocalRomScan); ok && config.SyncSaveStates {
+		syncingStates := i18n.Localize(&goi18n.Message{ID: "save_sync_syncing_states", Other: "Syncing save states..."}, nil)
+
+		var stateSyncs []sync.StateSync
+		gaba.ProcessMessage(syncingStates, gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+			var err error
+			stateSyncs, err = sync.FindStateSyncs(ctx, input.Host, config, localRoms)
+			if err != nil {
+				gaba.GetLogger().Error("Unable to scan save states!", "error", err)
+			}
+			return nil, nil
+		})
+
+		// Save History doesn't list states, so a state can't be kept alongside the other
+		for i := range stateSyncs {
+			if s := stateSyncs[i]; s.Action == sync.Conflict {
+				name := strings.TrimSuffix(s.RomName, filepath.Ext(s.RomName))
+				if name == "" {
+					name = s.BaseName
+				}
+				name = stateEntryName(name, sync.StateSlotLabel(s.Slot))
+				stateSyncs[i].Action = showConflictResolution(name, s.Local.LastModified, s.RemoteTime(), false)
+			}
+		}
+
+		if len(stateSyncs) > 0 {
+			gaba.ProcessMessage(syncingStates, gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+				for i := range stateSyncs {
+					results = append(results, stateSyncs[i].Execute(config))
+				}
+				return nil, nil
+			})
+		}
+	}
+
 	if len(results) > 0 || len(unmatched) > 0 {
 		reportScreen := newSyncReportScreen()
 		_, err := reportScreen.draw(syncReportInput{
@@ -187,6 +261,60 @@ func showFuzzyMatchConfirmation(fm sync.PendingFuzzyMatch) bool {
 	return err == nil
 }
 
+// showConflictResolution asks what to do with a save changed both on the device and in RomM
+// since the last sync. Keep Both is only offered if keepBoth is set. Skipping leaves the
+// conflict in place to be reported.
+func showConflictResolution(name string, local, remote time.Time, keepBoth bool) sync.Action {
+	message := i18n.Localize(&goi18n.Message{
+		ID:    "save_conflict_message",
+		Other: "Save Conflict: {{.Name}}\n\nThis save changed on this device and in RomM since the last sync.\nDevice: {{.Local}}\nRomM: {{.Remote}}",
+	}, map[string]interface{}{
+		"Name":   name,
+		"Local":  local.Local().Format(saveHistoryTimeFormat),
+		"Remote": remote.Local().Format(saveHistoryTimeFormat),
+	})
+
+	options := []gaba.SelectionOption{
+		{
+			DisplayName: i18n.Localize(&goi18n.Message{ID: "save_conflict_keep_local", Other: "Keep Device"}, nil),
+			Description: i18n.Localize(&goi18n.Message{ID: "save_conflict_keep_local_description", Other: "Upload this device's save to RomM."}, nil),
+			Value:       sync.Upload,
+		},
+		{
+			DisplayName: i18n.Localize(&goi18n.Message{ID: "save_conflict_keep_remote", Other: "Keep RomM"}, nil),
+			Description: i18n.Localize(&goi18n.Message{ID: "save_conflict_keep_remote_description", Other: "Download the RomM save. This device's save is backed up first."}, nil),
+			Value:       sync.Download,
+		},
+	}
+	if keepBoth {
+		options = append(options, gaba.SelectionOption{
+			DisplayName: i18n.Localize(&goi18n.Message{ID: "save_conflict_keep_both", Other: "Keep Both"}, nil),
+			Description: i18n.Localize(&goi18n.Message{ID: "save_conflict_keep_both_description", Other: "Keep playing this device's save and store the RomM save in Save History."}, nil),
+			Value:       sync.KeepBoth,
+		})
+	}
+
+	result, err := gaba.SelectionMessage(
+		message,
+		options,
+		[]gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_skip", Other: "Skip"}, nil)},
+			{ButtonName: buttons.LeftRight, HelpText: i18n.Localize(&goi18n.Message{ID: "button_select", Other: "Select"}, nil)},
+			{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_confirm", Other: "Confirm"}, nil)},
+		},
+		gaba.SelectionMessageSettings{},
+	)
+	if err != nil || result == nil {
+		return sync.Conflict
+	}
+
+	action, ok := result.SelectedValue.(sync.Action)
+	if !ok {
+		return sync.Conflict
+	}
+	return action
+}
+
 func createLocalSaveFromPath(savePath, fsSlug string) *sync.LocalSave {
 	info, err := os.Stat(savePath)
 	if err != nil {
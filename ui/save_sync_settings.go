@@ -23,6 +23,10 @@ type SaveSyncSettingsOutput struct {
 	Config *internal.Config
 }
 
+// syncExcludedOption is the value of the option that leaves a platform out of save sync,
+// kept apart from the save directory values which are plain strings.
+type syncExcludedOption struct{}
+
 type SaveSyncSettingsScreen struct {
 	displayToFSSlug map[string]string
 }
@@ -42,6 +46,15 @@ func (s *SaveSyncSettingsScreen) Draw(input SaveSyncSettingsInput) (SaveSyncSett
 		return output, nil
 	}
 
+	items = append([]gaba.ItemWithOptions{{
+		Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "save_sync_settings_states", Other: "Sync Save States"}, nil)},
+		Options: []gaba.Option{
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_true", Other: "True"}, nil), Value: true},
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_false", Other: "False"}, nil), Value: false},
+		},
+		SelectedOption: boolToIndex(!config.SyncSaveStates),
+	}}, items...)
+
 	result, err := gaba.OptionsList(
 		i18n.Localize(&goi18n.Message{ID: "save_sync_settings_title", Other: "Save Sync Settings"}, nil),
 		gaba.OptionListSettings{
@@ -127,9 +140,16 @@ func (s *SaveSyncSettingsScreen) buildMenuItems(config *internal.Config) []gaba.
 			})
 		}
 
+		options = append(options, gaba.Option{
+			DisplayName: i18n.Localize(&goi18n.Message{ID: "save_sync_settings_excluded", Other: "Don't Sync"}, nil),
+			Value:       syncExcludedOption{},
+		})
+
 		// Determine currently selected option
 		selectedIndex := 0
-		if config.SaveDirectoryMappings != nil {
+		if config.ExcludedSyncPlatforms[fsSlug] {
+			selectedIndex = len(options) - 1
+		} else if config.SaveDirectoryMappings != nil {
 			if currentMapping, ok := config.SaveDirectoryMappings[fsSlug]; ok && currentMapping != "" {
 				for i, opt := range options {
 					if val, ok := opt.Value.(string); ok && val == currentMapping {
@@ -165,11 +185,27 @@ func (s *SaveSyncSettingsScreen) applySettings(config *internal.Config, items []
 	}
 
 	for _, item := range items {
+		if item.Item.Text == i18n.Localize(&goi18n.Message{ID: "save_sync_settings_states", Other: "Sync Save States"}, nil) {
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.SyncSaveStates = val
+			}
+			continue
+		}
+
 		// Look up fsSlug from display name
 		fsSlug, ok := s.displayToFSSlug[item.Item.Text]
 		if !ok {
 			continue
 		}
+		if _, excluded := item.Options[item.SelectedOption].Value.(syncExcludedOption); excluded {
+			if config.ExcludedSyncPlatforms == nil {
+				config.ExcludedSyncPlatforms = make(map[string]bool)
+			}
+			config.ExcludedSyncPlatforms[fsSlug] = true
+			continue
+		}
+		delete(config.ExcludedSyncPlatforms, fsSlug)
+
 		if val, ok := item.Options[item.SelectedOption].Value.(string); ok {
 			if val == "" {
 				// Remove from map if set to default
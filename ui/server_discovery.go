@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"grout/romm"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const serverDiscoveryDuration = 5 * time.Second
+
+// discoverServerUI searches the local network for RomM servers and lets the user pick
+// one. Returns the picked server's address, or false if none was picked.
+func discoverServerUI() (romm.Host, bool) {
+	var servers []romm.DiscoveredServer
+	gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "login_discovering", Other: "Looking for RomM servers on your network..."}, nil),
+		gaba.ProcessMessageOptions{},
+		func() (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), serverDiscoveryDuration)
+			defer cancel()
+			servers = romm.DiscoverServers(ctx)
+			return nil, nil
+		},
+	)
+
+	if len(servers) == 0 {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "login_discovery_none", Other: "No RomM servers found on your network.\nPlease enter the hostname instead."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return romm.Host{}, false
+	}
+
+	var menuItems []gaba.MenuItem
+	for _, server := range servers {
+		name := removeScheme(server.Host.URL())
+		if server.Name != "" {
+			name = server.Name + " - " + name
+		}
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     i18n.Localize(&goi18n.Message{ID: "login_discovered_server", Other: "{{.Name}} (RomM {{.Version}})"}, map[string]interface{}{"Name": name, "Version": server.Version}),
+			Metadata: server.Host,
+		})
+	}
+
+	options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "login_discovery_title", Other: "Servers Found"}, nil), menuItems)
+	options.UseSmallTitle = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{FooterBack(), FooterSelect()}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		if !errors.Is(err, gaba.ErrCancelled) {
+			gaba.GetLogger().Error("Server selection failed", "error", err)
+		}
+		return romm.Host{}, false
+	}
+	if len(sel.Selected) == 0 {
+		return romm.Host{}, false
+	}
+
+	return sel.Items[sel.Selected[0]].Metadata.(romm.Host), true
+}
@@ -35,6 +35,8 @@ type SettingsOutput struct {
 	AdvancedSettingsClicked    bool
 	SaveSyncSettingsClicked    bool
 	CheckUpdatesClicked        bool
+	ManageGamesClicked         bool
+	StorageClicked             bool
 	LastSelectedIndex          int
 	LastVisibleStartIndex      int
 }
@@ -54,6 +56,8 @@ const (
 	SettingSaveSync            SettingType = "save_sync"
 	SettingSaveSyncSettings    SettingType = "save_sync_settings"
 	SettingAdvancedSettings    SettingType = "advanced_settings"
+	SettingManageGames         SettingType = "manage_games"
+	SettingStorage             SettingType = "storage"
 	SettingInfo                SettingType = "info"
 	SettingCheckUpdates        SettingType = "check_updates"
 )
@@ -64,6 +68,8 @@ var settingsOrder = []SettingType{
 	SettingDirectoryMappings,
 	SettingSaveSync,
 	SettingSaveSyncSettings,
+	SettingManageGames,
+	SettingStorage,
 	SettingAdvancedSettings,
 	SettingInfo,
 	SettingCheckUpdates,
@@ -130,6 +136,18 @@ func (s *SettingsScreen) Draw(input SettingsInput) (SettingsOutput, error) {
 			return output, nil
 		}
 
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_manage_games", Other: "Manage Local Games"}, nil) {
+			output.ManageGamesClicked = true
+			output.Action = SettingsActionManageGames
+			return output, nil
+		}
+
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_storage", Other: "Storage Usage"}, nil) {
+			output.StorageClicked = true
+			output.Action = SettingsActionStorage
+			return output, nil
+		}
+
 		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_advanced", Other: "Advanced"}, nil) {
 			output.AdvancedSettingsClicked = true
 			output.Action = SettingsActionAdvanced
@@ -206,6 +224,18 @@ func (s *SettingsScreen) buildMenuItem(settingType SettingType, config *internal
 			VisibleWhen: &visibility.saveSyncSettings,
 		}
 
+	case SettingManageGames:
+		return gaba.ItemWithOptions{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_manage_games", Other: "Manage Local Games"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		}
+
+	case SettingStorage:
+		return gaba.ItemWithOptions{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_storage", Other: "Storage Usage"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		}
+
 	case SettingAdvancedSettings:
 		return gaba.ItemWithOptions{
 			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_advanced", Other: "Advanced"}, nil)},
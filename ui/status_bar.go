@@ -1,22 +1,47 @@
 package ui
 
 import (
+	"sync"
+
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
-var defaultStatusBar = gaba.StatusBarOptions{
-	Enabled:    true,
-	ShowTime:   true,
-	TimeFormat: gaba.TimeFormat24Hour,
-	Icons: []gaba.StatusBarIcon{
-		{},
-	},
-}
+var (
+	statusBarMu      sync.Mutex
+	defaultStatusBar = gaba.StatusBarOptions{
+		Enabled:    true,
+		ShowTime:   true,
+		TimeFormat: gaba.TimeFormat24Hour,
+		Icons: []gaba.StatusBarIcon{
+			{},
+		},
+	}
+)
 
 func StatusBar() gaba.StatusBarOptions {
+	statusBarMu.Lock()
+	defer statusBarMu.Unlock()
 	return defaultStatusBar
 }
 
 func AddStatusBarIcon(icon gaba.StatusBarIcon) {
+	statusBarMu.Lock()
+	defer statusBarMu.Unlock()
 	defaultStatusBar.Icons = append([]gaba.StatusBarIcon{icon}, defaultStatusBar.Icons...)
 }
+
+// RemoveStatusBarIcon removes a dynamic icon previously added with AddStatusBarIcon.
+// The icon list is replaced rather than modified so screens already drawn are unaffected.
+func RemoveStatusBarIcon(icon gaba.StatusBarIcon) {
+	statusBarMu.Lock()
+	defer statusBarMu.Unlock()
+
+	icons := make([]gaba.StatusBarIcon, 0, len(defaultStatusBar.Icons))
+	for _, existing := range defaultStatusBar.Icons {
+		if icon.Dynamic != nil && existing.Dynamic == icon.Dynamic {
+			continue
+		}
+		icons = append(icons, existing)
+	}
+	defaultStatusBar.Icons = icons
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"errors"
+	"grout/internal"
+	"grout/internal/stringutil"
+	"grout/sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type StorageInput struct {
+	Config *internal.Config
+}
+
+type StorageOutput struct {
+	Action StorageAction
+}
+
+type StorageScreen struct{}
+
+func NewStorageScreen() *StorageScreen {
+	return &StorageScreen{}
+}
+
+func (s *StorageScreen) Draw(input StorageInput) (StorageOutput, error) {
+	output := StorageOutput{Action: StorageActionBack}
+
+	usage, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "storage_calculating", Other: "Calculating storage usage..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (sync.StorageUsage, error) {
+			return sync.CalculateStorageUsage(input.Config), nil
+		},
+	)
+	if err != nil {
+		return output, err
+	}
+
+	options := gaba.DefaultInfoScreenOptions()
+	options.Sections = s.buildSections(usage)
+	options.ShowThemeBackground = false
+	options.ShowScrollbar = true
+	options.ConfirmButton = buttons.VirtualButtonUnassigned
+
+	_, err = gaba.DetailScreen(
+		i18n.Localize(&goi18n.Message{ID: "storage_title", Other: "Storage Usage"}, nil),
+		options,
+		[]gaba.FooterHelpItem{FooterBack()},
+	)
+	if err != nil && !errors.Is(err, gaba.ErrCancelled) {
+		gaba.GetLogger().Error("Storage screen error", "error", err)
+		return output, err
+	}
+
+	return output, nil
+}
+
+func (s *StorageScreen) buildSections(usage sync.StorageUsage) []gaba.Section {
+	sections := make([]gaba.Section, 0, len(usage.Platforms)+1)
+
+	var roms, saves int64
+	for _, p := range usage.Platforms {
+		roms += p.Roms + p.BoxArt
+		saves += p.Saves
+	}
+
+	summary := []gaba.MetadataItem{
+		{
+			Label: i18n.Localize(&goi18n.Message{ID: "storage_free", Other: "Free"}, nil),
+			Value: i18n.Localize(&goi18n.Message{ID: "storage_free_of_total", Other: "{{.Free}} of {{.Total}}"}, map[string]interface{}{
+				"Free":  stringutil.FormatBytes(int64(usage.Disk.Free)),
+				"Total": stringutil.FormatBytes(int64(usage.Disk.Total)),
+			}),
+		},
+		{Label: i18n.Localize(&goi18n.Message{ID: "storage_roms", Other: "ROMs"}, nil), Value: stringutil.FormatBytes(roms)},
+		{Label: i18n.Localize(&goi18n.Message{ID: "storage_saves", Other: "Saves"}, nil), Value: stringutil.FormatBytes(saves)},
+		{Label: i18n.Localize(&goi18n.Message{ID: "storage_bios", Other: "BIOS"}, nil), Value: stringutil.FormatBytes(usage.BIOS)},
+		{Label: i18n.Localize(&goi18n.Message{ID: "storage_artwork_cache", Other: "Artwork Cache"}, nil), Value: stringutil.FormatBytes(usage.Artwork)},
+		{Label: i18n.Localize(&goi18n.Message{ID: "storage_cache", Other: "Grout Cache"}, nil), Value: stringutil.FormatBytes(usage.Cache)},
+	}
+	sections = append(sections, gaba.NewInfoSection(i18n.Localize(&goi18n.Message{ID: "storage_device", Other: "Device"}, nil), summary))
+
+	for _, p := range usage.Platforms {
+		if p.Total() == 0 {
+			continue
+		}
+
+		metadata := []gaba.MetadataItem{
+			{Label: i18n.Localize(&goi18n.Message{ID: "storage_roms", Other: "ROMs"}, nil), Value: stringutil.FormatBytes(p.Roms)},
+		}
+		if p.BoxArt > 0 {
+			metadata = append(metadata, gaba.MetadataItem{Label: i18n.Localize(&goi18n.Message{ID: "storage_box_art", Other: "Box Art"}, nil), Value: stringutil.FormatBytes(p.BoxArt)})
+		}
+		metadata = append(metadata,
+			gaba.MetadataItem{Label: i18n.Localize(&goi18n.Message{ID: "storage_saves", Other: "Saves"}, nil), Value: stringutil.FormatBytes(p.Saves)},
+			gaba.MetadataItem{Label: i18n.Localize(&goi18n.Message{ID: "storage_artwork_cache", Other: "Artwork Cache"}, nil), Value: stringutil.FormatBytes(p.Artwork)},
+		)
+
+		sections = append(sections, gaba.NewInfoSection(p.Name, metadata))
+	}
+
+	return sections
+}
@@ -65,6 +65,9 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 	downloadedCount := 0
 	skippedCount := 0
 	failedCount := 0
+	keptBothCount := 0
+	conflictCount := 0
+	excludedCount := 0
 
 	for _, r := range results {
 		if !r.Success {
@@ -78,6 +81,12 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 			downloadedCount++
 		case sync.Skip:
 			skippedCount++
+		case sync.KeepBoth:
+			keptBothCount++
+		case sync.Conflict:
+			conflictCount++
+		case sync.Excluded:
+			excludedCount++
 		}
 	}
 
@@ -99,6 +108,21 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 			Label: i18n.Localize(&goi18n.Message{ID: "save_sync_skipped", Other: "Skipped"}, nil), Value: fmt.Sprintf("%d", skippedCount)})
 	}
 
+	if keptBothCount > 0 {
+		summary = append(summary, gaba.MetadataItem{
+			Label: i18n.Localize(&goi18n.Message{ID: "save_sync_kept_both", Other: "Kept Both"}, nil), Value: fmt.Sprintf("%d", keptBothCount)})
+	}
+
+	if conflictCount > 0 {
+		summary = append(summary, gaba.MetadataItem{
+			Label: i18n.Localize(&goi18n.Message{ID: "save_sync_conflicts", Other: "Conflicts"}, nil), Value: fmt.Sprintf("%d", conflictCount)})
+	}
+
+	if excludedCount > 0 {
+		summary = append(summary, gaba.MetadataItem{
+			Label: i18n.Localize(&goi18n.Message{ID: "save_sync_excluded", Other: "Excluded"}, nil), Value: fmt.Sprintf("%d", excludedCount)})
+	}
+
 	if failedCount > 0 {
 		summary = append(summary, gaba.MetadataItem{
 			Label: i18n.Localize(&goi18n.Message{ID: "save_sync_failed", Other: "Failed"}, nil), Value: fmt.Sprintf("%d", failedCount)})
@@ -117,7 +141,7 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 				if displayName == "" {
 					displayName = filepath.Base(r.FilePath)
 				}
-				downloadedFiles += displayName
+				downloadedFiles += withStateSlot(displayName, r)
 			}
 		}
 		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_downloaded", Other: "Downloaded"}, nil), downloadedFiles))
@@ -135,7 +159,7 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 				if displayName == "" {
 					displayName = strings.TrimSuffix(filepath.Base(r.FilePath), filepath.Ext(r.FilePath))
 				}
-				uploadedFiles += displayName
+				uploadedFiles += withStateSlot(displayName, r)
 			}
 		}
 		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_uploaded", Other: "Uploaded"}, nil), uploadedFiles))
@@ -156,12 +180,48 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 				if displayName == "" {
 					displayName = r.GameName
 				}
-				failedFiles += fmt.Sprintf("%s (%s): %s", displayName, r.Action, errorMsg)
+				failedFiles += fmt.Sprintf("%s (%s): %s", withStateSlot(displayName, r), r.Action, errorMsg)
 			}
 		}
 		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_failed", Other: "Failed"}, nil), failedFiles))
 	}
 
+	if conflictCount > 0 {
+		conflictFiles := ""
+		for _, r := range results {
+			if r.Action != sync.Conflict {
+				continue
+			}
+			if conflictFiles != "" {
+				conflictFiles += "\n"
+			}
+			displayName := r.RomDisplayName
+			if displayName == "" {
+				displayName = r.GameName
+			}
+			conflictFiles += i18n.Localize(&goi18n.Message{ID: "save_sync_conflict_unresolved", Other: "{{.Name}} (Changed on both sides, not synced)"}, map[string]interface{}{"Name": withStateSlot(displayName, r)})
+		}
+		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_conflicts", Other: "Conflicts"}, nil), conflictFiles))
+	}
+
+	if excludedCount > 0 {
+		excludedFiles := ""
+		for _, r := range results {
+			if r.Action != sync.Excluded {
+				continue
+			}
+			if excludedFiles != "" {
+				excludedFiles += "\n"
+			}
+			displayName := r.RomDisplayName
+			if displayName == "" {
+				displayName = r.GameName
+			}
+			excludedFiles += displayName
+		}
+		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_excluded", Other: "Excluded"}, nil), excludedFiles))
+	}
+
 	// Display unmatched saves (ROM not found in RomM)
 	if len(unmatched) > 0 {
 		unmatchedText := ""
@@ -176,3 +236,15 @@ func (s *SyncReportScreen) buildSections(results []sync.Result, unmatched []sync
 
 	return sections
 }
+
+// withStateSlot marks save state results with their slot so they can be told apart from the game's save.
+func withStateSlot(name string, r sync.Result) string {
+	if r.StateSlot == "" {
+		return name
+	}
+	return stateEntryName(name, r.StateSlot)
+}
+
+func stateEntryName(name, slot string) string {
+	return i18n.Localize(&goi18n.Message{ID: "save_sync_state_entry", Other: "{{.Name}} (State {{.Slot}})"}, map[string]interface{}{"Name": name, "Slot": slot})
+}